@@ -0,0 +1,33 @@
+// Command escalator evaluates the escalation rules against the active
+// tickets. It runs on a schedule, each invocation makes a single pass.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"example.com/ticket-system/internal/escalation"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/streams"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func main() {
+	ctx := context.Background()
+
+	wf, err := workflow.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("could not load workflow: %s", err)
+	}
+	rules, err := escalation.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("could not load escalation rules: %s", err)
+	}
+	repos := repositories.NewFromEnv(ctx, wf)
+	engine := escalation.NewEngine(rules, repos.Tickets, streams.LogNotifier{})
+	lambda.Start(func(ctx context.Context) error {
+		return engine.Evaluate(ctx, time.Now())
+	})
+}
@@ -0,0 +1,30 @@
+// Command importworker processes bulk import chunks delivered by the SQS
+// import queue.
+package main
+
+import (
+	"context"
+	"log"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func main() {
+	ctx := context.Background()
+
+	wf, err := workflow.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("could not load workflow: %s", err)
+	}
+	repos := repositories.NewFromEnv(ctx, wf)
+	assigner, err := assignment.LoadFromEnv(repos)
+	if err != nil {
+		log.Fatalf("could not load assignment strategy: %s", err)
+	}
+	service := imports.NewService(repos.ImportJobs, repos.Tickets, nil, assigner, wf)
+	lambda.Start(imports.NewSQSHandler(service))
+}
@@ -0,0 +1,31 @@
+// Command outboxrelay sends the events waiting in the outbox to the
+// configured sink. It runs on a schedule, each invocation drains the outbox.
+package main
+
+import (
+	"context"
+	"log"
+
+	"example.com/ticket-system/internal/outbox"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func main() {
+	ctx := context.Background()
+
+	wf, err := workflow.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("could not load workflow: %s", err)
+	}
+	repos := repositories.NewFromEnv(ctx, wf)
+	sink, err := outbox.NewSinkFromEnv(repos)
+	if err != nil {
+		log.Fatalf("could not configure the outbox sink: %s", err)
+	}
+	relay := outbox.NewRelay(repos.Outbox, sink)
+	lambda.Start(func(ctx context.Context) error {
+		return relay.Drain(ctx)
+	})
+}
@@ -0,0 +1,114 @@
+// Command server serves the ticket API on a plain net/http listener, for
+// local development and hosting outside Lambda.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/auth"
+	"example.com/ticket-system/internal/escalation"
+	"example.com/ticket-system/internal/http/router"
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/outbox"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/sla"
+	"example.com/ticket-system/internal/streams"
+	"example.com/ticket-system/internal/workflow"
+)
+
+func main() {
+	addr := flag.String("addr", envOrDefault("HTTP_ADDR", ":8080"), "listen address")
+	readTimeout := flag.Duration("read-timeout", 15*time.Second, "maximum duration for reading a request")
+	writeTimeout := flag.Duration("write-timeout", 30*time.Second, "maximum duration for writing a response")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "time allowed for in-flight requests on shutdown")
+	relayInterval := flag.Duration("outbox-interval", time.Second, "delay between two passes of the outbox relay")
+	escalationInterval := flag.Duration("escalation-interval", time.Minute, "delay between two passes of the escalation rules")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wf, err := workflow.LoadFromEnv()
+	if err != nil {
+		slog.Error("Could not load workflow", "error", err)
+		os.Exit(1)
+	}
+	authenticator, err := auth.NewFromEnv()
+	if err != nil {
+		slog.Error("Could not configure authentication", "error", err)
+		os.Exit(1)
+	}
+	policies, err := sla.LoadFromEnv()
+	if err != nil {
+		slog.Error("Could not load SLA policies", "error", err)
+		os.Exit(1)
+	}
+	rules, err := escalation.LoadFromEnv()
+	if err != nil {
+		slog.Error("Could not load escalation rules", "error", err)
+		os.Exit(1)
+	}
+	repos := repositories.NewFromEnv(ctx, wf)
+	assigner, err := assignment.LoadFromEnv(repos)
+	if err != nil {
+		slog.Error("Could not load assignment strategy", "error", err)
+		os.Exit(1)
+	}
+	sink, err := outbox.NewSinkFromEnv(repos)
+	if err != nil {
+		slog.Error("Could not configure the outbox sink", "error", err)
+		os.Exit(1)
+	}
+	// events left in the outbox on shutdown are sent by the next instance
+	go outbox.NewRelay(repos.Outbox, sink).Run(ctx, *relayInterval)
+	go escalation.NewEngine(rules, repos.Tickets, streams.LogNotifier{}).Run(ctx, *escalationInterval)
+	importer, pool := imports.NewServiceFromEnv(ctx, repos, assigner, wf)
+	if pool != nil {
+		// lets queued import chunks finish after the listener has stopped
+		defer pool.Stop()
+	}
+	server := &http.Server{
+		Addr:         *addr,
+		Handler:      router.New(repos, importer, authenticator, policies, assigner),
+		ReadTimeout:  *readTimeout,
+		WriteTimeout: *writeTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting HTTP server", "addr", *addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server failed", "error", err)
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		slog.Info("Shutting down HTTP server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Graceful shutdown failed", "error", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
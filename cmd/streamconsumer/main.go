@@ -0,0 +1,13 @@
+// Command streamconsumer handles the DynamoDB stream of the tickets table.
+// Every ticket write is passed to the notification, metrics and search
+// indexing handlers.
+package main
+
+import (
+	"example.com/ticket-system/internal/streams"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func main() {
+	lambda.Start(streams.NewStreamHandler(streams.NewHandlersFromEnv()...))
+}
@@ -0,0 +1,158 @@
+// Package assignment picks the agent new tickets are assigned to. The
+// strategy is chosen by configuration: round-robin over the team, the member
+// with the fewest open tickets, or the member whose skills best match the
+// ticket tags. Tickets created with an assignee are left alone.
+package assignment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"gopkg.in/yaml.v3"
+)
+
+// Strategy names, recorded on the tickets they assign in Ticket.AssignedBy
+const (
+	StrategyRoundRobin = "round-robin"
+	StrategyLeastOpen  = "least-open"
+	StrategySkills     = "skills"
+)
+
+// Assignee of tickets nobody is assigned to
+const unassigned = "None"
+
+var ErrInvalidDefinition = errors.New("invalid assignment definition")
+
+// Definition is the serializable form of the configuration, loaded from
+// JSON or YAML
+type Definition struct {
+	Strategy string `json:"strategy" yaml:"strategy"`
+	// the team tickets are assigned to
+	Agents []AgentDefinition `json:"agents" yaml:"agents"`
+}
+
+type AgentDefinition struct {
+	Name string `json:"name" yaml:"name"`
+	// compared with the ticket tags by the skills strategy
+	Skills []string `json:"skills,omitempty" yaml:"skills,omitempty"`
+}
+
+// Strategy picks the assignee of each ticket, an empty name leaves the
+// ticket unassigned. Tickets are passed together so a batch is spread over
+// the team.
+type Strategy interface {
+	Pick(ctx context.Context, tickets []*models.Ticket) ([]string, error)
+}
+
+// Assigner assigns the tickets created without assignee. The zero Assigner
+// leaves them unassigned.
+type Assigner struct {
+	name     string
+	strategy Strategy
+}
+
+// Builds the assigner of the configured strategy, turns holds the
+// round-robin counters and tickets is queried for the open tickets of the
+// agents
+func New(def Definition, turns repositories.AssignmentRepository, tickets repositories.TicketRepository) (*Assigner, error) {
+	if def.Strategy == "" {
+		return &Assigner{}, nil
+	}
+	if len(def.Agents) == 0 {
+		return nil, fmt.Errorf("%w - strategy %s has no agents", ErrInvalidDefinition, def.Strategy)
+	}
+	names := map[string]bool{}
+	agents := make([]string, 0, len(def.Agents))
+	for _, agent := range def.Agents {
+		if agent.Name == "" || agent.Name == unassigned || names[agent.Name] {
+			return nil, fmt.Errorf("%w - missing or duplicate agent %q", ErrInvalidDefinition, agent.Name)
+		}
+		names[agent.Name] = true
+		agents = append(agents, agent.Name)
+	}
+
+	assigner := &Assigner{name: def.Strategy}
+	switch def.Strategy {
+	case StrategyRoundRobin:
+		assigner.strategy = &roundRobin{agents: agents, turns: turns}
+	case StrategyLeastOpen:
+		assigner.strategy = &leastOpen{agents: agents, tickets: tickets}
+	case StrategySkills:
+		assigner.strategy = newSkills(def.Agents, &leastOpen{tickets: tickets})
+	default:
+		return nil, fmt.Errorf("%w - unknown strategy %s", ErrInvalidDefinition, def.Strategy)
+	}
+	return assigner, nil
+}
+
+// Reads the configuration from a .json, .yaml or .yml file
+func Load(path string, turns repositories.AssignmentRepository, tickets repositories.TicketRepository) (*Assigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+
+	var def Definition
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &def)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &def)
+	default:
+		err = fmt.Errorf("unsupported file type %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+	return New(def, turns, tickets)
+}
+
+// Loads the configuration file set in ASSIGNMENT_CONFIG. Without it tickets
+// are left for a human to triage.
+func LoadFromEnv(repos repositories.Repositories) (*Assigner, error) {
+	if path := os.Getenv("ASSIGNMENT_CONFIG"); path != "" {
+		return Load(path, repos.Assignment, repos.Tickets)
+	}
+	return &Assigner{}, nil
+}
+
+// Assign sets the assignee of the tickets without one and records the
+// strategy that picked it. Resolved and closed tickets, which imports may
+// bring, are left alone. A failing strategy leaves the tickets unassigned
+// rather than failing their creation.
+func (a *Assigner) Assign(ctx context.Context, tickets ...*models.Ticket) {
+	if a.strategy == nil {
+		return
+	}
+	var pending []*models.Ticket
+	for _, ticket := range tickets {
+		if ticket.Status == models.StatusResolved || ticket.Status == models.StatusClosed {
+			continue
+		}
+		if ticket.AssignedTo == "" || ticket.AssignedTo == unassigned {
+			pending = append(pending, ticket)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	assignees, err := a.strategy.Pick(ctx, pending)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to assign tickets", "strategy", a.name, "error", err)
+		return
+	}
+	for i, ticket := range pending {
+		if assignees[i] != "" {
+			ticket.AssignedTo = assignees[i]
+			ticket.AssignedBy = a.name
+		}
+	}
+}
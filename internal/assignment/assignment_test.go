@@ -0,0 +1,149 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTickets(n int) []*models.Ticket {
+	tickets := make([]*models.Ticket, n)
+	for i := range tickets {
+		tickets[i] = &models.Ticket{Status: models.StatusOpen, AssignedTo: unassigned}
+	}
+	return tickets
+}
+
+func assignees(tickets []*models.Ticket) []string {
+	var names []string
+	for _, ticket := range tickets {
+		names = append(names, ticket.AssignedTo)
+	}
+	return names
+}
+
+func TestRoundRobin(t *testing.T) {
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	assigner, err := New(Definition{
+		Strategy: StrategyRoundRobin,
+		Agents:   []AgentDefinition{{Name: "maria"}, {Name: "sofia"}, {Name: "andrew"}},
+	}, repo, repo)
+	require.NoError(t, err)
+
+	acme := identity.WithIdentity(context.Background(), identity.Identity{Tenant: "acme"})
+	tickets := newTickets(4)
+	assigner.Assign(acme, tickets[0], tickets[1])
+	// the rotation continues where the previous call stopped
+	assigner.Assign(acme, tickets[2], tickets[3])
+	assert.Equal(t, []string{"maria", "sofia", "andrew", "maria"}, assignees(tickets))
+	assert.Equal(t, StrategyRoundRobin, tickets[0].AssignedBy)
+
+	// every tenant has its own rotation
+	globex := identity.WithIdentity(context.Background(), identity.Identity{Tenant: "globex"})
+	tickets = newTickets(1)
+	assigner.Assign(globex, tickets...)
+	assert.Equal(t, "maria", tickets[0].AssignedTo)
+}
+
+func TestLeastOpen(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	for _, ticket := range []models.Ticket{
+		{Status: models.StatusOpen, AssignedTo: "maria"},
+		{Status: models.StatusInProgress, AssignedTo: "maria"},
+		{Status: models.StatusOpen, AssignedTo: "sofia"},
+		{Status: models.StatusClosed, AssignedTo: "andrew"},
+		{Status: models.StatusResolved, AssignedTo: "andrew"},
+	} {
+		_, err := repo.CreateTicket(ctx, &ticket)
+		require.NoError(t, err)
+	}
+	assigner, err := New(Definition{
+		Strategy: StrategyLeastOpen,
+		Agents:   []AgentDefinition{{Name: "maria"}, {Name: "sofia"}, {Name: "andrew"}},
+	}, repo, repo)
+	require.NoError(t, err)
+
+	tickets := newTickets(4)
+	assigner.Assign(ctx, tickets...)
+	// andrew has none open, then sofia is first among the agents with one
+	assert.Equal(t, []string{"andrew", "sofia", "andrew", "maria"}, assignees(tickets))
+}
+
+func TestSkills(t *testing.T) {
+	path := filepath.Join("..", "..", "assignment.example.yaml")
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	assigner, err := Load(path, repo, repo)
+	require.NoError(t, err)
+
+	billing := &models.Ticket{Status: models.StatusOpen, Tags: []string{"billing", "invoices"}}
+	network := &models.Ticket{Status: models.StatusOpen, Tags: []string{"network"}}
+	both := &models.Ticket{Status: models.StatusOpen, Tags: []string{"network", "billing"}}
+	other := &models.Ticket{Status: models.StatusOpen, AssignedTo: unassigned, Tags: []string{"hardware"}}
+	assigner.Assign(context.Background(), billing, network, both, other)
+
+	assert.Equal(t, "maria", billing.AssignedTo)
+	// sofia and andrew both know networks, sofia is defined first
+	assert.Equal(t, "sofia", network.AssignedTo)
+	assert.Equal(t, "andrew", both.AssignedTo)
+	assert.Equal(t, StrategySkills, both.AssignedBy)
+	assert.Equal(t, unassigned, other.AssignedTo)
+	assert.Empty(t, other.AssignedBy)
+}
+
+func TestAssignLeavesAssignedTickets(t *testing.T) {
+	tickets := repositories.NewMockTicketRepository(t)
+	assigner, err := New(Definition{Strategy: StrategyLeastOpen, Agents: []AgentDefinition{{Name: "maria"}}}, nil, tickets)
+	require.NoError(t, err)
+
+	assigned := &models.Ticket{Status: models.StatusOpen, AssignedTo: "andrew"}
+	resolved := &models.Ticket{Status: models.StatusResolved}
+	assigner.Assign(context.Background(), assigned, resolved)
+	assert.Equal(t, "andrew", assigned.AssignedTo)
+	assert.Empty(t, resolved.AssignedTo)
+	tickets.AssertNotCalled(t, "GetTicketsAssignedTo", mock.Anything, mock.Anything)
+
+	// a failing strategy leaves the ticket unassigned
+	tickets.EXPECT().GetTicketsAssignedTo(mock.Anything, "maria").Return(nil, errors.New("unavailable")).Once()
+	ticket := newTickets(1)[0]
+	assigner.Assign(context.Background(), ticket)
+	assert.Equal(t, unassigned, ticket.AssignedTo)
+
+	// the zero Assigner does nothing
+	(&Assigner{}).Assign(context.Background(), ticket)
+	assert.Equal(t, unassigned, ticket.AssignedTo)
+}
+
+func TestNewRejectsInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name string
+		def  Definition
+	}{
+		{"unknown strategy", Definition{Strategy: "random", Agents: []AgentDefinition{{Name: "maria"}}}},
+		{"no agents", Definition{Strategy: StrategyRoundRobin}},
+		{"missing name", Definition{Strategy: StrategyRoundRobin, Agents: []AgentDefinition{{}}}},
+		{"duplicate name", Definition{Strategy: StrategyRoundRobin, Agents: []AgentDefinition{{Name: "maria"}, {Name: "maria"}}}},
+		{"reserved name", Definition{Strategy: StrategyRoundRobin, Agents: []AgentDefinition{{Name: unassigned}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.def, nil, nil)
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "assignment.toml")
+	require.NoError(t, os.WriteFile(path, []byte(""), 0o644))
+	_, err := Load(path, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+}
@@ -0,0 +1,144 @@
+package assignment
+
+import (
+	"context"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+)
+
+// Hands the tickets to the agents in turn. The counter is kept in the
+// repository so every instance of the API continues the same rotation.
+type roundRobin struct {
+	agents []string
+	turns  repositories.AssignmentRepository
+}
+
+func (s *roundRobin) Pick(ctx context.Context, tickets []*models.Ticket) ([]string, error) {
+	first, err := s.turns.ReserveAssignmentTurns(ctx, StrategyRoundRobin, len(tickets))
+	if err != nil {
+		return nil, err
+	}
+	assignees := make([]string, len(tickets))
+	for i := range tickets {
+		assignees[i] = s.agents[(first+int64(i))%int64(len(s.agents))]
+	}
+	return assignees, nil
+}
+
+// Gives each ticket to the agent with the fewest open tickets, the first one
+// defined on a tie. Tickets of the batch count as they are handed out.
+type leastOpen struct {
+	agents  []string
+	tickets repositories.TicketRepository
+}
+
+func (s *leastOpen) Pick(ctx context.Context, tickets []*models.Ticket) ([]string, error) {
+	open, err := s.openTickets(ctx, s.agents)
+	if err != nil {
+		return nil, err
+	}
+	assignees := make([]string, len(tickets))
+	for i := range tickets {
+		assignees[i] = least(s.agents, open)
+		open[assignees[i]]++
+	}
+	return assignees, nil
+}
+
+// Counts the tickets of each agent that are neither resolved nor closed,
+// through the AssignedTo index
+func (s *leastOpen) openTickets(ctx context.Context, agents []string) (map[string]int, error) {
+	open := make(map[string]int, len(agents))
+	for _, agent := range agents {
+		tickets, err := s.tickets.GetTicketsAssignedTo(ctx, agent)
+		if err != nil {
+			return nil, err
+		}
+		for _, ticket := range tickets {
+			if ticket.Status != models.StatusResolved && ticket.Status != models.StatusClosed {
+				open[agent]++
+			}
+		}
+	}
+	return open, nil
+}
+
+func least(agents []string, open map[string]int) string {
+	var picked string
+	for _, agent := range agents {
+		if picked == "" || open[agent] < open[picked] {
+			picked = agent
+		}
+	}
+	return picked
+}
+
+type skilledAgent struct {
+	name   string
+	skills map[string]bool
+}
+
+// Gives each ticket to the agents having the most skills among its tags,
+// the one with the fewest open tickets among them. Tickets matching no
+// agent are left unassigned.
+type skills struct {
+	agents    []skilledAgent
+	names     []string
+	leastOpen *leastOpen
+}
+
+func newSkills(defs []AgentDefinition, leastOpen *leastOpen) *skills {
+	s := &skills{leastOpen: leastOpen}
+	for _, def := range defs {
+		agent := skilledAgent{name: def.Name, skills: make(map[string]bool)}
+		for _, skill := range def.Skills {
+			agent.skills[skill] = true
+		}
+		s.agents = append(s.agents, agent)
+		s.names = append(s.names, def.Name)
+	}
+	return s
+}
+
+func (s *skills) Pick(ctx context.Context, tickets []*models.Ticket) ([]string, error) {
+	var open map[string]int
+	assignees := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		candidates := s.bestMatches(ticket)
+		if len(candidates) == 0 {
+			continue
+		}
+		if open == nil {
+			var err error
+			if open, err = s.leastOpen.openTickets(ctx, s.names); err != nil {
+				return nil, err
+			}
+		}
+		assignees[i] = least(candidates, open)
+		open[assignees[i]]++
+	}
+	return assignees, nil
+}
+
+func (s *skills) bestMatches(ticket *models.Ticket) []string {
+	best := 0
+	var candidates []string
+	for _, agent := range s.agents {
+		matched := 0
+		for _, tag := range ticket.Tags {
+			if agent.skills[tag] {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0 || matched < best:
+		case matched > best:
+			best = matched
+			candidates = []string{agent.name}
+		default:
+			candidates = append(candidates, agent.name)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"example.com/ticket-system/internal/identity"
+)
+
+var (
+	ErrNotConfigured    = errors.New("no token keys configured")
+	ErrInvalidTenant    = errors.New("invalid tenant")
+	ErrTenantNotAllowed = errors.New("tenant not allowed")
+)
+
+// Header selecting the tenant of a request
+const TenantHeader = "X-Tenant-ID"
+
+// Authenticator identifies the caller of a request
+type Authenticator interface {
+	Authenticate(r *http.Request) (identity.Identity, error)
+}
+
+// Authenticate reads the bearer token of the request
+func (v *Verifier) Authenticate(r *http.Request) (identity.Identity, error) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return identity.Identity{}, ErrMissingToken
+	}
+	claims, err := v.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return identity.Identity{}, err
+	}
+	return identity.Identity{Subject: claims.Subject, Roles: claims.Roles, Tenant: claims.Tenant}, nil
+}
+
+// ResolveTenant settles the tenant of a request from the tenant claim and
+// the requested tenant header. Callers bound to a tenant by their token
+// cannot pick another one; only admins without a tenant claim may choose
+// any tenant. Everybody else works on the default tenant.
+func ResolveTenant(id identity.Identity, requested string) (identity.Identity, error) {
+	if requested != "" && !identity.ValidTenant(requested) {
+		return id, fmt.Errorf("%w - %q", ErrInvalidTenant, requested)
+	}
+	if id.Tenant != "" && !identity.ValidTenant(id.Tenant) {
+		return id, fmt.Errorf("%w - %q", ErrInvalidTenant, id.Tenant)
+	}
+	switch {
+	case requested == "" || requested == id.Tenant:
+	case id.Tenant == "" && id.HasRole(identity.RoleAdmin):
+		id.Tenant = requested
+	default:
+		return id, fmt.Errorf("%w - %q", ErrTenantNotAllowed, requested)
+	}
+	if id.Tenant == "" {
+		id.Tenant = identity.DefaultTenant
+	}
+	return id, nil
+}
+
+// developmentAuthenticator trusts the X-Actor header and grants every
+// request the admin role. It is only used when AUTH_DISABLED is set.
+type developmentAuthenticator struct{}
+
+func (developmentAuthenticator) Authenticate(r *http.Request) (identity.Identity, error) {
+	subject := r.Header.Get("X-Actor")
+	if subject == "" {
+		subject = identity.SystemActor
+	}
+	return identity.Identity{Subject: subject, Roles: []string{identity.RoleAdmin}}, nil
+}
+
+// NewFromEnv builds the verifier from
+//
+//	JWT_HMAC_SECRET      shared secret for HS256 tokens
+//	JWT_PUBLIC_KEY_FILE  PEM encoded RSA public key for RS256 tokens
+//	JWT_JWKS_FILE        JWKS document with RS256 keys
+//	JWT_ISSUER           expected iss claim, optional
+//	JWT_AUDIENCE         expected aud claim, optional
+//
+// At least one key is required. AUTH_DISABLED=true skips authentication for
+// local development.
+func NewFromEnv() (Authenticator, error) {
+	if os.Getenv("AUTH_DISABLED") == "true" {
+		return developmentAuthenticator{}, nil
+	}
+
+	var options []Option
+	if secret := os.Getenv("JWT_HMAC_SECRET"); secret != "" {
+		options = append(options, WithHMACSecret([]byte(secret)))
+	}
+	if path := os.Getenv("JWT_PUBLIC_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseRSAPublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		options = append(options, WithRSAKey("", key))
+	}
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := ParseJWKS(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for kid, key := range keys {
+			options = append(options, WithRSAKey(kid, key))
+		}
+	}
+	if len(options) == 0 {
+		return nil, ErrNotConfigured
+	}
+
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		options = append(options, WithIssuer(issuer))
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		options = append(options, WithAudience(audience))
+	}
+	return NewVerifier(options...), nil
+}
@@ -0,0 +1,180 @@
+// Package auth authenticates API callers with bearer JWTs. Tokens are signed
+// with HS256 using a shared secret, or RS256 using public keys loaded from a
+// PEM file or a JWKS document. Parsing and signature checks are left to
+// golang-jwt.
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Allowed clock difference with the token issuer
+const clockLeeway = time.Minute
+
+// Claims are the registered claims checked by the verifier plus the roles
+// of the caller
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant"`
+}
+
+// Latest numeric date accepted, in seconds either side of the epoch, the
+// dates of a token are refused beyond it rather than wrapped around
+const maxNumericDate = math.MaxInt64 / float64(time.Second)
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	var dates struct {
+		ExpiresAt float64 `json:"exp"`
+		NotBefore float64 `json:"nbf"`
+		IssuedAt  float64 `json:"iat"`
+	}
+	if err := json.Unmarshal(data, &dates); err != nil {
+		return err
+	}
+	for _, date := range []float64{dates.ExpiresAt, dates.NotBefore, dates.IssuedAt} {
+		if math.Abs(date) > maxNumericDate {
+			return fmt.Errorf("numeric date %g out of range", date)
+		}
+	}
+	type plain Claims
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// Verifier checks token signatures and claims. Only the algorithms a key is
+// configured for are accepted, so an RS256 public key can never be used as
+// an HS256 secret.
+type Verifier struct {
+	secret []byte
+	keys   map[string]*rsa.PublicKey
+	// keys without an id, such as the one of a PEM file
+	unnamedKeys []*rsa.PublicKey
+	issuer      string
+	audience    string
+	now         func() time.Time
+}
+
+type Option func(*Verifier)
+
+// WithHMACSecret accepts HS256 tokens signed with secret
+func WithHMACSecret(secret []byte) Option {
+	return func(v *Verifier) {
+		v.secret = secret
+	}
+}
+
+// WithRSAKey accepts RS256 tokens signed by the key. kid may be empty, keys
+// without an id are tried for tokens that do not name one.
+func WithRSAKey(kid string, key *rsa.PublicKey) Option {
+	return func(v *Verifier) {
+		if kid == "" {
+			v.unnamedKeys = append(v.unnamedKeys, key)
+			return
+		}
+		v.keys[kid] = key
+	}
+}
+
+// WithIssuer requires the iss claim to match
+func WithIssuer(issuer string) Option {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires the aud claim to contain audience
+func WithAudience(audience string) Option {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+func NewVerifier(options ...Option) *Verifier {
+	v := &Verifier{
+		keys: make(map[string]*rsa.PublicKey),
+		now:  time.Now,
+	}
+	for _, option := range options {
+		option(v)
+	}
+	return v
+}
+
+// Verify checks the signature and the time, issuer and audience claims of
+// the token and returns its claims
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, v.key, v.parserOptions()...)
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	case err != nil:
+		return nil, fmt.Errorf("%w - %w", ErrInvalidToken, err)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w - missing sub", ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+// Only the algorithms of the configured keys are allowed. A nil list would
+// allow any algorithm, a verifier without keys gets an empty one.
+func (v *Verifier) parserOptions() []jwt.ParserOption {
+	methods := []string{}
+	if v.secret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.keys) > 0 || len(v.unnamedKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockLeeway),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+	return options
+}
+
+// a token naming a key must be signed by it, otherwise every key is tried
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	if token.Method == jwt.SigningMethodHS256 {
+		if v.secret == nil {
+			return nil, errors.New("HS256 not accepted")
+		}
+		return v.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid != "" {
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("no key for kid %q", kid)
+	}
+	var keys jwt.VerificationKeySet
+	for _, key := range v.unnamedKeys {
+		keys.Keys = append(keys.Keys, key)
+	}
+	for _, key := range v.keys {
+		keys.Keys = append(keys.Keys, key)
+	}
+	return keys, nil
+}
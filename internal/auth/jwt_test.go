@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test-secret")
+
+func segment(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, claims map[string]any) string {
+	input := segment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	input := segment(t, map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":   "hugo",
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"tickets"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"agent"},
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier := NewVerifier(WithHMACSecret(testSecret), WithIssuer("https://issuer.example.com"), WithAudience("tickets"))
+
+	claims, err := verifier.Verify(signHS256(t, testSecret, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "hugo", claims.Subject)
+	assert.Equal(t, []string{"agent"}, claims.Roles)
+
+	tests := []struct {
+		name     string
+		token    func() string
+		expected error
+	}{
+		{
+			name:     "wrong secret",
+			token:    func() string { return signHS256(t, []byte("other"), validClaims()) },
+			expected: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+				return signHS256(t, testSecret, claims)
+			},
+			expected: ErrTokenExpired,
+		},
+		{
+			name: "missing expiry",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return signHS256(t, testSecret, claims)
+			},
+			expected: ErrInvalidToken,
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				claims := validClaims()
+				claims["nbf"] = time.Now().Add(time.Hour).Unix()
+				return signHS256(t, testSecret, claims)
+			},
+			expected: ErrInvalidToken,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return signHS256(t, testSecret, claims)
+			},
+			expected: ErrInvalidToken,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "billing"
+				return signHS256(t, testSecret, claims)
+			},
+			expected: ErrInvalidToken,
+		},
+		{
+			name: "unsigned",
+			token: func() string {
+				return segment(t, map[string]string{"alg": "none"}) + "." + segment(t, validClaims()) + "."
+			},
+			expected: ErrInvalidToken,
+		},
+		{
+			name: "expiry out of range",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = 1e300
+				return signHS256(t, testSecret, claims)
+			},
+			expected: ErrInvalidToken,
+		},
+		{
+			name:     "malformed",
+			token:    func() string { return "not-a-token" },
+			expected: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token())
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks := segmentJWKS(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey, "key-2": &other.PublicKey})
+	keys, err := ParseJWKS(jwks)
+	require.NoError(t, err)
+	var options []Option
+	for kid, k := range keys {
+		options = append(options, WithRSAKey(kid, k))
+	}
+	verifier := NewVerifier(options...)
+
+	claims, err := verifier.Verify(signRS256(t, key, "key-1", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "hugo", claims.Subject)
+
+	// signed by another key than the one named
+	_, err = verifier.Verify(signRS256(t, other, "key-1", validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = verifier.Verify(signRS256(t, key, "unknown", validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// without an HMAC secret HS256 tokens are refused, whatever they are signed with
+	_, err = verifier.Verify(signHS256(t, x509.MarshalPKCS1PublicKey(&key.PublicKey), validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseRSAPublicKeyPEM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	parsed, err := ParseRSAPublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(parsed))
+
+	_, err = ParseRSAPublicKeyPEM([]byte("not a key"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	verifier := NewVerifier(WithRSAKey("", parsed))
+	_, err = verifier.Verify(signRS256(t, key, "", validClaims()))
+	assert.NoError(t, err)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&small.PublicKey)
+	require.NoError(t, err)
+	_, err = ParseRSAPublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = ParseJWKS(segmentJWKS(t, map[string]*rsa.PublicKey{"small": &small.PublicKey}))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestUnnamedKeys(t *testing.T) {
+	pemKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// a JWKS key without an id does not replace the key of the PEM file
+	keys, err := ParseJWKS(segmentJWKS(t, map[string]*rsa.PublicKey{"": &jwksKey.PublicKey}))
+	require.NoError(t, err)
+	verifier := NewVerifier(WithRSAKey("", &pemKey.PublicKey), WithRSAKey("", keys[""]))
+	_, err = verifier.Verify(signRS256(t, pemKey, "", validClaims()))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(signRS256(t, jwksKey, "", validClaims()))
+	assert.NoError(t, err)
+}
+
+func TestAuthenticate(t *testing.T) {
+	verifier := NewVerifier(WithHMACSecret(testSecret))
+
+	req := httptest.NewRequest("GET", "/tickets", nil)
+	_, err := verifier.Authenticate(req)
+	assert.ErrorIs(t, err, ErrMissingToken)
+
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, testSecret, validClaims()))
+	id, err := verifier.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, identity.Identity{Subject: "hugo", Roles: []string{"agent"}}, id)
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("AUTH_DISABLED", "")
+	t.Setenv("JWT_HMAC_SECRET", "")
+	t.Setenv("JWT_PUBLIC_KEY_FILE", "")
+	t.Setenv("JWT_JWKS_FILE", "")
+	_, err := NewFromEnv()
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	t.Setenv("JWT_HMAC_SECRET", string(testSecret))
+	authenticator, err := NewFromEnv()
+	require.NoError(t, err)
+	req := httptest.NewRequest("GET", "/tickets", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, testSecret, validClaims()))
+	_, err = authenticator.Authenticate(req)
+	assert.NoError(t, err)
+}
+
+func segmentJWKS(t *testing.T, keys map[string]*rsa.PublicKey) []byte {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	// an encryption key is ignored
+	set.Keys = append(set.Keys, jwk{Kty: "RSA", Kid: "enc", Use: "enc"})
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+func TestResolveTenant(t *testing.T) {
+	requester := identity.Identity{Subject: "hugo", Roles: []string{identity.RoleRequester}}
+	bound := identity.Identity{Subject: "hugo", Roles: []string{identity.RoleAdmin}, Tenant: "acme"}
+	operator := identity.Identity{Subject: "ops", Roles: []string{identity.RoleAdmin}}
+
+	tests := []struct {
+		name      string
+		id        identity.Identity
+		requested string
+		tenant    string
+		expected  error
+	}{
+		{"no tenant", requester, "", identity.DefaultTenant, nil},
+		{"tenant claim", bound, "", "acme", nil},
+		{"header matching claim", bound, "acme", "acme", nil},
+		{"header overriding claim", bound, "globex", "", ErrTenantNotAllowed},
+		{"requester picking a tenant", requester, "acme", "", ErrTenantNotAllowed},
+		{"operator picking a tenant", operator, "globex", "globex", nil},
+		{"key injection", operator, "acme#ticket#1", "", ErrInvalidTenant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ResolveTenant(tt.id, tt.requested)
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.tenant, id.Tenant)
+		})
+	}
+}
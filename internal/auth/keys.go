@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidKey = errors.New("invalid key")
+
+// Smaller RSA keys are refused
+const minRSAKeyBits = 2048
+
+// ParseRSAPublicKeyPEM reads a PKIX or PKCS #1 public key, or the key of a
+// certificate
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w - no PEM block", ErrInvalidKey)
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("%w - unexpected PEM block %q", ErrInvalidKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidKey, err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w - not an RSA key", ErrInvalidKey)
+	}
+	if rsaKey.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("%w - key smaller than %d bits", ErrInvalidKey, minRSAKeyBits)
+	}
+	return rsaKey, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// ParseJWKS returns the RSA signing keys of a JWKS document by key id. Keys
+// of other types or meant for encryption are skipped.
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidKey, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") || (key.Alg != "" && key.Alg != "RS256") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("%w - key %q modulus: %w", ErrInvalidKey, key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("%w - key %q exponent: %w", ErrInvalidKey, key.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("%w - key %q exponent out of range", ErrInvalidKey, key.Kid)
+		}
+		modulus := new(big.Int).SetBytes(n)
+		if modulus.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("%w - key %q smaller than %d bits", ErrInvalidKey, key.Kid, minRSAKeyBits)
+		}
+		keys[key.Kid] = &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w - no RSA signing keys", ErrInvalidKey)
+	}
+	return keys, nil
+}
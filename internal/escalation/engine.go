@@ -0,0 +1,150 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/streams"
+)
+
+type Engine struct {
+	rules    *Rules
+	tickets  repositories.TicketRepository
+	notifier streams.Notifier
+}
+
+func NewEngine(rules *Rules, tickets repositories.TicketRepository, notifier streams.Notifier) *Engine {
+	return &Engine{
+		rules:    rules,
+		tickets:  tickets,
+		notifier: notifier,
+	}
+}
+
+// Run evaluates the rules every interval until ctx is done
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Evaluate(ctx, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "Failed to escalate tickets", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate makes a single pass over the active tickets of every tenant and
+// fires the rules matching them at now. A ticket that fails to escalate is
+// reported and the others still are.
+func (e *Engine) Evaluate(ctx context.Context, now time.Time) error {
+	if len(e.rules.rules) == 0 {
+		return nil
+	}
+	tickets, err := e.tickets.ListTicketsInStatus(ctx, e.rules.statuses())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, tenantTicket := range tickets {
+		ticketCtx := identity.WithIdentity(ctx, identity.Identity{Subject: identity.SystemActor, Tenant: tenantTicket.Tenant})
+		ticket := tenantTicket.Ticket
+		for i := range e.rules.rules {
+			rule := &e.rules.rules[i]
+			if !rule.Matches(&ticket, now) {
+				continue
+			}
+			escalated, err := e.escalate(ticketCtx, rule, &ticket)
+			if escalated != nil {
+				ticket = *escalated
+			}
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				// the ticket changed since it was listed, the next pass sees
+				// the new version
+				break
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("ticket %s rule %s: %w", ticket.TicketID, rule.Name, err))
+			}
+			if escalated == nil {
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// The rule is recorded on the ticket, together with the priority and tags,
+// before anything else happens. That write is conditional on the version
+// listed, so of two engines racing on a ticket only one fires the rule. An
+// action failing afterwards is not retried. Returns the ticket as last
+// written, nil when the rule was not recorded.
+func (e *Engine) escalate(ctx context.Context, rule *Rule, ticket *models.Ticket) (*models.Ticket, error) {
+	escalated := *ticket
+	escalated.Escalations = append(append([]string{}, ticket.Escalations...), rule.Name)
+	escalated.Tags = append([]string{}, ticket.Tags...)
+	rule.Actions.apply(&escalated)
+	if err := e.tickets.UpdateTicket(ctx, &escalated); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Ticket escalated", "tenant", identity.Tenant(ctx), "ticketId", ticket.TicketID, "rule", rule.Name)
+
+	var errs []error
+	if rule.Actions.Assign != "" {
+		if updated, err := e.tickets.UpdateAssignTo(ctx, ticket.TicketID, rule.Actions.Assign, escalated.Version); err != nil {
+			errs = append(errs, err)
+		} else {
+			escalated = *updated
+		}
+	}
+	if rule.Actions.Status != "" && rule.Actions.Status != escalated.Status {
+		if updated, err := e.tickets.UpdateStatus(ctx, ticket.TicketID, string(rule.Actions.Status), escalated.Version); err != nil {
+			errs = append(errs, err)
+		} else {
+			escalated = *updated
+		}
+	}
+	for _, recipient := range recipients(rule.Actions.Notify, &escalated) {
+		err := e.notifier.Notify(ctx, streams.Notification{
+			Tenant:    identity.Tenant(ctx),
+			Recipient: recipient,
+			TicketID:  ticket.TicketID,
+			Message:   fmt.Sprintf("Ticket %s was escalated by rule %s", ticket.TicketID, rule.Name),
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return &escalated, errors.Join(errs...)
+}
+
+// Resolves the placeholders, skipping the people the ticket does not have
+func recipients(names []string, ticket *models.Ticket) []string {
+	var resolved []string
+	for _, name := range names {
+		switch name {
+		case RecipientAssignee:
+			name = ticket.AssignedTo
+		case RecipientRequester:
+			name = ticket.CreatedBy
+		}
+		if name = strings.TrimSpace(name); name != "" && name != "None" {
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved
+}
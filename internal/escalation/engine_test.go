@@ -0,0 +1,158 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/streams"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func notification(tenant string, recipient string, ticketID string) func(streams.Notification) bool {
+	return func(n streams.Notification) bool {
+		return n.Tenant == tenant && n.Recipient == recipient && n.TicketID == ticketID
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules, err := Load(filepath.Join("..", "..", "escalation.example.yaml"))
+	require.NoError(t, err)
+
+	acme := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo", Tenant: "acme"})
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	create := func(ticket models.Ticket) string {
+		ticket.CreatedBy = "hugo"
+		ticket.CreatedAt = models.NewTimestamp(created)
+		id, err := repo.CreateTicket(acme, &ticket)
+		require.NoError(t, err)
+		return id
+	}
+	unassigned := create(models.Ticket{Description: "unassigned", Status: models.StatusOpen, AssignedTo: "None"})
+	breached := create(models.Ticket{
+		Description: "breached",
+		Status:      models.StatusInProgress,
+		AssignedTo:  "andrew",
+		Priority:    "HIGH",
+		SLA: &models.TicketSLA{
+			Policy:           "high",
+			FirstResponseDue: models.FormatSortableTime(created.Add(time.Hour)),
+			ResolutionDue:    models.FormatSortableTime(created.Add(8 * time.Hour)),
+			RespondedAt:      models.FormatSortableTime(created.Add(10 * time.Minute)),
+		},
+	})
+	assigned := create(models.Ticket{Description: "assigned", Status: models.StatusOpen, AssignedTo: "andrew"})
+	resolved := create(models.Ticket{Description: "resolved", Status: models.StatusResolved, AssignedTo: "None"})
+
+	notifier := streams.NewMockNotifier(t)
+	notifier.On("Notify", mock.Anything, mock.MatchedBy(notification("acme", "oncall-lead", unassigned))).Return(nil).Once()
+	notifier.On("Notify", mock.Anything, mock.MatchedBy(notification("acme", "andrew", breached))).Return(nil).Once()
+	notifier.On("Notify", mock.Anything, mock.MatchedBy(notification("acme", "support-manager", breached))).Return(nil).Once()
+	engine := NewEngine(rules, repo, notifier)
+
+	// too early for both rules
+	require.NoError(t, engine.Evaluate(context.Background(), created.Add(20*time.Minute)))
+	notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+
+	now := created.Add(9 * time.Hour)
+	require.NoError(t, engine.Evaluate(context.Background(), now))
+	// the rules fire once, the second pass leaves the tickets alone
+	require.NoError(t, engine.Evaluate(context.Background(), now.Add(time.Hour)))
+
+	ticket, err := repo.GetTicket(acme, unassigned)
+	require.NoError(t, err)
+	assert.Equal(t, "oncall-lead", ticket.AssignedTo)
+	assert.Equal(t, []string{"unassigned-30m"}, ticket.Escalations)
+
+	ticket, err = repo.GetTicket(acme, breached)
+	require.NoError(t, err)
+	assert.Equal(t, models.PriorityUrgent, ticket.Priority)
+	assert.Equal(t, []string{"escalated"}, ticket.Tags)
+	assert.Equal(t, []string{"sla-breached"}, ticket.Escalations)
+
+	for _, id := range []string{assigned, resolved} {
+		ticket, err = repo.GetTicket(acme, id)
+		require.NoError(t, err)
+		assert.Empty(t, ticket.Escalations)
+		assert.Equal(t, int64(1), ticket.Version)
+	}
+}
+
+func TestEvaluateReportsFailedActions(t *testing.T) {
+	rules, err := New(Definition{Rules: []RuleDefinition{
+		{Name: "close-stale", When: ConditionDefinition{Statuses: []models.TicketStatus{models.StatusOpen}, OlderThan: "24h"}, Actions: ActionDefinition{Status: models.StatusClosed, Notify: []string{RecipientRequester}}},
+	}})
+	require.NoError(t, err)
+
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	id, err := repo.CreateTicket(context.Background(), &models.Ticket{
+		Description: "stale",
+		Status:      models.StatusOpen,
+		CreatedBy:   "hugo",
+		CreatedAt:   models.NewTimestamp(created),
+	})
+	require.NoError(t, err)
+
+	notifier := streams.NewMockNotifier(t)
+	notifier.On("Notify", mock.Anything, mock.MatchedBy(notification(identity.DefaultTenant, "hugo", id))).Return(errors.New("unavailable")).Once()
+	engine := NewEngine(rules, repo, notifier)
+
+	err = engine.Evaluate(context.Background(), created.Add(48*time.Hour))
+	assert.Error(t, err)
+
+	// the rule was recorded first, the notification is not retried
+	require.NoError(t, engine.Evaluate(context.Background(), created.Add(72*time.Hour)))
+	ticket, err := repo.GetTicket(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusClosed, ticket.Status)
+	assert.Equal(t, []string{"close-stale"}, ticket.Escalations)
+}
+
+func TestEvaluateWithoutRules(t *testing.T) {
+	tickets := repositories.NewMockTicketRepository(t)
+	engine := NewEngine(&Rules{}, tickets, streams.NewMockNotifier(t))
+	assert.NoError(t, engine.Evaluate(context.Background(), time.Now()))
+}
+
+func TestRaisePriority(t *testing.T) {
+	assert.Equal(t, models.PriorityMedium, raisePriority(""))
+	assert.Equal(t, models.PriorityMedium, raisePriority(models.PriorityLow))
+	assert.Equal(t, models.PriorityUrgent, raisePriority(models.PriorityHigh))
+	assert.Equal(t, models.PriorityUrgent, raisePriority(models.PriorityUrgent))
+}
+
+func TestNewRejectsInvalidRules(t *testing.T) {
+	action := ActionDefinition{Assign: "oncall-lead"}
+	tests := []struct {
+		name  string
+		rules []RuleDefinition
+	}{
+		{"missing name", []RuleDefinition{{Actions: action}}},
+		{"duplicate name", []RuleDefinition{{Name: "r", Actions: action}, {Name: "r", Actions: action}}},
+		{"no action", []RuleDefinition{{Name: "r"}}},
+		{"bad duration", []RuleDefinition{{Name: "r", When: ConditionDefinition{OlderThan: "soon"}, Actions: action}}},
+		{"unknown priority", []RuleDefinition{{Name: "r", Actions: ActionDefinition{Priority: "CRITICAL"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(Definition{Rules: tt.rules})
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.toml")
+	require.NoError(t, os.WriteFile(path, []byte(""), 0o644))
+	_, err := Load(path)
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+}
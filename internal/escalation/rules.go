@@ -0,0 +1,251 @@
+// Package escalation runs the escalation rules against the active tickets
+// of every tenant. A rule matches tickets on their status, priority,
+// assignee, age and SLA, and acts on them by assigning them, changing their
+// status or priority, tagging them and notifying users. Each rule fires at
+// most once per ticket.
+package escalation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Priority action raising the priority by one level
+const RaisePriority models.TicketPriority = "raise"
+
+// Notify recipients standing for the people of the ticket
+const (
+	RecipientAssignee  = "@assignee"
+	RecipientRequester = "@requester"
+)
+
+var ErrInvalidDefinition = errors.New("invalid escalation rules")
+
+// Tickets in these statuses are evaluated by rules that do not name statuses
+var activeStatuses = []models.TicketStatus{
+	models.StatusOpen,
+	models.StatusInProgress,
+	models.StatusPendingCustomer,
+	models.StatusReopened,
+}
+
+// Definition is the serializable form of the rules, loaded from JSON or
+// YAML. Durations use the time.ParseDuration syntax.
+type Definition struct {
+	Rules []RuleDefinition `json:"rules" yaml:"rules"`
+}
+
+type RuleDefinition struct {
+	// recorded on the tickets the rule fired for, renaming a rule fires it again
+	Name    string              `json:"name" yaml:"name"`
+	When    ConditionDefinition `json:"when" yaml:"when"`
+	Actions ActionDefinition    `json:"actions" yaml:"actions"`
+}
+
+// Every condition set must hold for the rule to match
+type ConditionDefinition struct {
+	Statuses   []models.TicketStatus   `json:"statuses,omitempty" yaml:"statuses,omitempty"`
+	Priorities []models.TicketPriority `json:"priorities,omitempty" yaml:"priorities,omitempty"`
+	Unassigned bool                    `json:"unassigned,omitempty" yaml:"unassigned,omitempty"`
+	// time since the ticket was created
+	OlderThan string `json:"olderThan,omitempty" yaml:"olderThan,omitempty"`
+	// a first-response or resolution deadline passed without being met
+	SLABreached bool `json:"slaBreached,omitempty" yaml:"slaBreached,omitempty"`
+}
+
+type ActionDefinition struct {
+	Assign string              `json:"assign,omitempty" yaml:"assign,omitempty"`
+	Status models.TicketStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	// a priority, or "raise" for the next one up
+	Priority models.TicketPriority `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags     []string              `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// user names, @assignee or @requester
+	Notify []string `json:"notify,omitempty" yaml:"notify,omitempty"`
+}
+
+func (a *ActionDefinition) empty() bool {
+	return a.Assign == "" && a.Status == "" && a.Priority == "" && len(a.Tags) == 0 && len(a.Notify) == 0
+}
+
+type Rule struct {
+	Name        string
+	statuses    map[models.TicketStatus]bool
+	priorities  map[models.TicketPriority]bool
+	unassigned  bool
+	olderThan   time.Duration
+	slaBreached bool
+	Actions     ActionDefinition
+}
+
+// Matches reports whether the rule applies to the ticket at now. A rule
+// that already fired for the ticket does not match again.
+func (r *Rule) Matches(ticket *models.Ticket, now time.Time) bool {
+	for _, name := range ticket.Escalations {
+		if name == r.Name {
+			return false
+		}
+	}
+	if !r.statuses[ticket.Status] {
+		return false
+	}
+	if len(r.priorities) > 0 && !r.priorities[ticket.Priority] {
+		return false
+	}
+	if r.unassigned && ticket.AssignedTo != "" && ticket.AssignedTo != "None" {
+		return false
+	}
+	if r.olderThan > 0 {
+		if ticket.CreatedAt.IsZero() || now.Sub(ticket.CreatedAt.Time) < r.olderThan {
+			return false
+		}
+	}
+	if r.slaBreached && !slaBreached(ticket.SLA, now) {
+		return false
+	}
+	return true
+}
+
+func slaBreached(sla *models.TicketSLA, now time.Time) bool {
+	if sla == nil {
+		return false
+	}
+	for _, target := range []models.SLATarget{sla.FirstResponse(now), sla.Resolution(now)} {
+		if target.MetAt == "" && target.Breached {
+			return true
+		}
+	}
+	return false
+}
+
+type Rules struct {
+	rules []Rule
+}
+
+// Builds the rules, checking names are unique and every rule has an action
+func New(def Definition) (*Rules, error) {
+	rules := &Rules{}
+	names := map[string]bool{}
+	for _, ruleDef := range def.Rules {
+		if ruleDef.Name == "" || names[ruleDef.Name] {
+			return nil, fmt.Errorf("%w - missing or duplicate name %q", ErrInvalidDefinition, ruleDef.Name)
+		}
+		names[ruleDef.Name] = true
+		if ruleDef.Actions.empty() {
+			return nil, fmt.Errorf("%w - rule %s has no action", ErrInvalidDefinition, ruleDef.Name)
+		}
+		if priority := ruleDef.Actions.Priority; priority != "" && priority != RaisePriority && !priority.Valid() {
+			return nil, fmt.Errorf("%w - rule %s: unknown priority %s", ErrInvalidDefinition, ruleDef.Name, priority)
+		}
+
+		rule := Rule{
+			Name:        ruleDef.Name,
+			statuses:    make(map[models.TicketStatus]bool),
+			priorities:  make(map[models.TicketPriority]bool),
+			unassigned:  ruleDef.When.Unassigned,
+			slaBreached: ruleDef.When.SLABreached,
+			Actions:     ruleDef.Actions,
+		}
+		statuses := ruleDef.When.Statuses
+		if len(statuses) == 0 {
+			statuses = activeStatuses
+		}
+		for _, status := range statuses {
+			rule.statuses[status] = true
+		}
+		for _, priority := range ruleDef.When.Priorities {
+			if !priority.Valid() {
+				return nil, fmt.Errorf("%w - rule %s: unknown priority %s", ErrInvalidDefinition, ruleDef.Name, priority)
+			}
+			rule.priorities[priority] = true
+		}
+		if ruleDef.When.OlderThan != "" {
+			olderThan, err := time.ParseDuration(ruleDef.When.OlderThan)
+			if err != nil || olderThan <= 0 {
+				return nil, fmt.Errorf("%w - rule %s: invalid olderThan %q", ErrInvalidDefinition, ruleDef.Name, ruleDef.When.OlderThan)
+			}
+			rule.olderThan = olderThan
+		}
+		rules.rules = append(rules.rules, rule)
+	}
+	return rules, nil
+}
+
+// Reads the rules from a .json, .yaml or .yml file
+func Load(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+
+	var def Definition
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &def)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &def)
+	default:
+		err = fmt.Errorf("unsupported file type %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+	return New(def)
+}
+
+// Loads the rules file set in ESCALATION_RULES. Without it there are no
+// rules and nothing is escalated.
+func LoadFromEnv() (*Rules, error) {
+	if path := os.Getenv("ESCALATION_RULES"); path != "" {
+		return Load(path)
+	}
+	return &Rules{}, nil
+}
+
+// Statuses of the tickets at least one rule may match
+func (r *Rules) statuses() []models.TicketStatus {
+	seen := map[models.TicketStatus]bool{}
+	var statuses []models.TicketStatus
+	for _, rule := range r.rules {
+		for status := range rule.statuses {
+			if !seen[status] {
+				seen[status] = true
+				statuses = append(statuses, status)
+			}
+		}
+	}
+	return statuses
+}
+
+// Applies the field changes of the actions to the ticket
+func (a *ActionDefinition) apply(ticket *models.Ticket) {
+	switch a.Priority {
+	case "":
+	case RaisePriority:
+		ticket.Priority = raisePriority(ticket.Priority)
+	default:
+		ticket.Priority = a.Priority
+	}
+	ticket.AddTags(a.Tags...)
+}
+
+// Moves one step up models.Priorities, a ticket without priority counts as
+// LOW
+func raisePriority(priority models.TicketPriority) models.TicketPriority {
+	levels := models.Priorities
+	for i, level := range levels {
+		if level == priority && i+1 < len(levels) {
+			return levels[i+1]
+		}
+		if level == priority {
+			return priority
+		}
+	}
+	return levels[1]
+}
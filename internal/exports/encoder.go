@@ -0,0 +1,147 @@
+// Package exports writes tickets in the formats offered by the export
+// endpoint. Encoders write one ticket at a time so an export never holds
+// more than a page of tickets in memory.
+package exports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strings"
+
+	"example.com/ticket-system/internal/models"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+type Encoder interface {
+	ContentType() string
+	Begin() error
+	Encode(ticket *models.Ticket) error
+	// completes the document, it is not called when the export fails
+	End() error
+}
+
+// NewEncoder returns the encoder for format, JSON for unknown formats
+func NewEncoder(format string, w io.Writer) Encoder {
+	switch format {
+	case FormatCSV:
+		return &csvEncoder{writer: csv.NewWriter(w)}
+	case FormatNDJSON:
+		return &ndjsonEncoder{encoder: json.NewEncoder(w)}
+	default:
+		return &jsonEncoder{w: w}
+	}
+}
+
+// FormatFromAccept picks the export format from an Accept header, taking
+// the first supported media type. JSON is used when the header is empty or
+// accepts anything; ok is false when no listed type is supported.
+func FormatFromAccept(accept string) (format string, ok bool) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return FormatCSV, true
+		case "application/x-ndjson", "application/ndjson":
+			return FormatNDJSON, true
+		case "application/json", "application/*", "*/*":
+			return FormatJSON, true
+		}
+	}
+	return "", false
+}
+
+// Rows use the bulk import layout, an export can be imported again as is
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvEncoder) ContentType() string {
+	return "text/csv"
+}
+
+func (e *csvEncoder) Begin() error {
+	return e.writer.Write(models.ImportColumnNames())
+}
+
+func (e *csvEncoder) Encode(ticket *models.Ticket) error {
+	record := models.NewBulkImportRecord(ticket)
+	if err := e.writer.Write(record.ToRecord()); err != nil {
+		return err
+	}
+	// flushed per row so rows reach the client as pages are read
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvEncoder) End() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (e *jsonEncoder) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder) Encode(ticket *models.Ticket) error {
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) End() error {
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (e *ndjsonEncoder) Begin() error {
+	return nil
+}
+
+// json.Encoder terminates every value with a newline
+func (e *ndjsonEncoder) Encode(ticket *models.Ticket) error {
+	return e.encoder.Encode(ticket)
+}
+
+func (e *ndjsonEncoder) End() error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package exports
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTickets() []models.Ticket {
+	return []models.Ticket{
+		{
+			TicketID:    "1234",
+			Description: "printer, 2nd floor",
+			Status:      models.StatusOpen,
+			CreatedBy:   "hugo",
+			AssignedTo:  "andrew",
+			Priority:    models.PriorityHigh,
+			Severity:    models.SeverityMajor,
+			Category:    models.CategoryIncident,
+			Tags:        []string{"hardware", "office"},
+			CreatedAt:   models.NewTimestamp(time.Date(2024, 3, 1, 10, 0, 0, 123000000, time.UTC)),
+			Version:     4,
+		},
+		{
+			TicketID:    "1235",
+			Description: "vpn \"drops\"",
+			Status:      models.StatusResolved,
+			CreatedBy:   "hugo",
+			AssignedTo:  "david",
+			CreatedAt:   models.NewTimestamp(time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC)),
+			Version:     2,
+		},
+	}
+}
+
+func encode(t *testing.T, format string, tickets []models.Ticket) string {
+	var buf bytes.Buffer
+	encoder := NewEncoder(format, &buf)
+	require.NoError(t, encoder.Begin())
+	for i := range tickets {
+		require.NoError(t, encoder.Encode(&tickets[i]))
+	}
+	require.NoError(t, encoder.End())
+	return buf.String()
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	tickets := exportTickets()
+	output := encode(t, FormatCSV, tickets)
+
+	assert.Equal(t, "id,description,status,assignedTo,createdBy,priority,severity,category,tags,createdAt\n"+
+		"1234,\"printer, 2nd floor\",OPEN,andrew,hugo,HIGH,MAJOR,INCIDENT,hardware;office,2024-03-01T10:00:00.123Z\n"+
+		"1235,\"vpn \"\"drops\"\"\",RESOLVED,david,hugo,,,,,2024-03-02T09:30:00Z\n", output)
+
+	entries, rejected, _, err := imports.ParseCSV(bytes.NewBufferString(output), nil, workflow.Default())
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+	require.Len(t, entries, 2)
+	for i, entry := range entries {
+		expected := tickets[i]
+		expected.Version = 1
+		assert.Equal(t, expected, entry.Ticket)
+	}
+}
+
+func TestJSONEncoders(t *testing.T) {
+	tickets := exportTickets()
+
+	var decoded []models.Ticket
+	require.NoError(t, json.Unmarshal([]byte(encode(t, FormatJSON, tickets)), &decoded))
+	assert.Equal(t, tickets, decoded)
+	assert.Equal(t, "[]", encode(t, FormatJSON, nil))
+
+	decoder := json.NewDecoder(bytes.NewBufferString(encode(t, FormatNDJSON, tickets)))
+	for _, expected := range tickets {
+		var ticket models.Ticket
+		require.NoError(t, decoder.Decode(&ticket))
+		assert.Equal(t, expected, ticket)
+	}
+	assert.False(t, decoder.More())
+}
+
+func TestFormatFromAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		format string
+		ok     bool
+	}{
+		{"", FormatJSON, true},
+		{"*/*", FormatJSON, true},
+		{"text/csv", FormatCSV, true},
+		{"application/x-ndjson", FormatNDJSON, true},
+		{"text/html, text/csv;q=0.9", FormatCSV, true},
+		{"text/html", "", false},
+	}
+	for _, tt := range tests {
+		format, ok := FormatFromAccept(tt.accept)
+		assert.Equal(t, tt.format, format, tt.accept)
+		assert.Equal(t, tt.ok, ok, tt.accept)
+	}
+}
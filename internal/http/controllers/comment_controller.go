@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"log/slog"
+
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type commentController struct {
+	repo repositories.CommentRepository
+}
+
+func NewCommentController(repo repositories.CommentRepository) commentController {
+	return commentController{
+		repo: repo,
+	}
+}
+
+func (cc *commentController) AddComment(ctx context.Context, c *gin.Context) {
+	var req types.AddCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	id, err := cc.repo.AddComment(ctx, req.ToComment(c.Param("id"), identity.Actor(ctx)))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to add comment", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, types.CreateTicketResponse{
+		Id: id,
+	})
+}
+
+// Lists the ticket comments in chronological order
+func (cc *commentController) ListComments(ctx context.Context, c *gin.Context) {
+	var req types.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+
+	page, err := cc.repo.ListComments(ctx, c.Param("id"), req.ToPageRequest())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list comments", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, types.CommentPageResponse{
+		Comments:   page.Comments,
+		NextCursor: page.NextCursor,
+	})
+}
+
+func (cc *commentController) UpdateComment(ctx context.Context, c *gin.Context) {
+	var req types.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	comment, err := cc.repo.UpdateComment(ctx, c.Param("id"), c.Param("commentId"), req.Body)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update comment", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"comment": comment})
+}
+
+func (cc *commentController) DeleteComment(ctx context.Context, c *gin.Context) {
+	err := cc.repo.DeleteComment(ctx, c.Param("id"), c.Param("commentId"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete comment", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "comment deleted"})
+}
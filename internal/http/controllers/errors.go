@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const problemContentType = "application/problem+json"
+
+var kindStatuses = map[error]int{
+	repositories.ErrNotFound:    http.StatusNotFound,
+	repositories.ErrConflict:    http.StatusConflict,
+	repositories.ErrValidation:  http.StatusUnprocessableEntity,
+	repositories.ErrUnavailable: http.StatusServiceUnavailable,
+}
+
+// Failures of the request itself. They are checked before the kinds, a
+// stale If-Match is a conflict answered with 412.
+var requestStatuses = []struct {
+	err    error
+	status int
+}{
+	{repositories.ErrPreconditionFailed, http.StatusPreconditionFailed},
+	{ErrInvalidIfMatch, http.StatusBadRequest},
+	{ErrUnsupportedPatch, http.StatusUnsupportedMediaType},
+	{ErrUnsupportedFormat, http.StatusNotAcceptable},
+	{ErrBadRequest, http.StatusBadRequest},
+}
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		// problems name the fields the way clients send them
+		v.RegisterTagNameFunc(fieldName)
+		v.RegisterValidation("username", func(field validator.FieldLevel) bool {
+			return identity.ValidUsername(field.Field().String())
+		})
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// HandleErrors answers with a problem for the last error the handlers
+// recorded with c.Error, unless they wrote a response already
+func HandleErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeProblem(c, newProblem(c.Errors.Last().Err))
+	}
+}
+
+// AbortWithProblem answers with a problem right away, for the failures that
+// have a status but no place in the repositories taxonomy
+func AbortWithProblem(c *gin.Context, status int, err error) {
+	c.Abort()
+	writeProblem(c, problem(status, statusCode(status), err.Error()))
+}
+
+// Records a request that could not be read. Fields breaking their binding
+// rules are reported one by one.
+func badRequest(c *gin.Context, err error) {
+	c.Error(fmt.Errorf("%w - %w", ErrBadRequest, err))
+}
+
+// Maps bound fields breaking their rules and validation errors to 422, the
+// other kinds of repository errors to 404, 409 and 503, and failures of the
+// request to their own status. Anything else is a fault of the service,
+// whose details stay in the logs.
+func newProblem(err error) types.ProblemResponse {
+	var fields validator.ValidationErrors
+	if errors.As(err, &fields) {
+		p := problem(http.StatusUnprocessableEntity, "invalid_request", "the request has invalid fields")
+		for _, field := range fields {
+			p.Errors = append(p.Errors, types.FieldProblem{Field: field.Field(), Code: field.Tag()})
+		}
+		return p
+	}
+	for _, request := range requestStatuses {
+		if errors.Is(err, request.err) {
+			return problem(request.status, statusCode(request.status), err.Error())
+		}
+	}
+	typed, ok := repositories.AsError(err)
+	if !ok {
+		return problem(http.StatusInternalServerError, statusCode(http.StatusInternalServerError), "")
+	}
+	detail := err.Error()
+	if typed.Kind == repositories.ErrUnavailable {
+		detail = typed.Message
+	}
+	p := problem(kindStatuses[typed.Kind], typed.Code, detail)
+	if typed.Field != "" {
+		p.Errors = []types.FieldProblem{{Field: typed.Field, Code: typed.Code}}
+	}
+	return p
+}
+
+func problem(status int, code string, detail string) types.ProblemResponse {
+	return types.ProblemResponse{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Code of the failures without one of their own, e.g. precondition_failed
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func writeProblem(c *gin.Context, problem types.ProblemResponse) {
+	c.Header("Content-Type", problemContentType)
+	c.JSON(problem.Status, problem)
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidIfMatch = errors.New("invalid If-Match header")
+
+// Tickets are versioned, the ETag is the quoted version number
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// Returns the version sent in If-Match, 0 when the header is absent or "*".
+// A header that is not a quoted ETag is ErrInvalidIfMatch, an ETag that is
+// not a version can match no ticket and fails the precondition.
+func ifMatchVersion(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	header = strings.TrimPrefix(header, "W/")
+	value, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, ErrInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w - no version matches %s", repositories.ErrPreconditionFailed, header)
+	}
+	return version, nil
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"context"
+	"log/slog"
+
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type historyController struct {
+	repo repositories.HistoryRepository
+}
+
+func NewHistoryController(repo repositories.HistoryRepository) historyController {
+	return historyController{
+		repo: repo,
+	}
+}
+
+// Lists the changes made to a ticket, oldest first
+func (hc *historyController) GetTicketHistory(ctx context.Context, c *gin.Context) {
+	var req types.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+
+	page, err := hc.repo.GetTicketHistory(ctx, c.Param("id"), req.ToPageRequest())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get ticket history", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, types.HistoryPageResponse{
+		History:    page.Entries,
+		NextCursor: page.NextCursor,
+	})
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type importController struct {
+	imports imports.Submitter
+	jobs    repositories.ImportJobRepository
+}
+
+func NewImportController(submitter imports.Submitter, jobs repositories.ImportJobRepository) importController {
+	return importController{
+		imports: submitter,
+		jobs:    jobs,
+	}
+}
+
+// Starts an import job for the uploaded CSV, the tickets are written in the
+// background. With dryRun the file is only validated and the report is
+// returned right away.
+func (ic *importController) SubmitImport(ctx context.Context, c *gin.Context) {
+	var request types.SubmitImportRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+	request.Mapping = c.PostForm("mapping")
+	mapping, err := request.ColumnMapping()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to read column mapping", "error", err)
+		badRequest(c, err)
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to receive file", "error", err)
+		badRequest(c, err)
+		return
+	}
+	defer file.Close()
+
+	if request.DryRun {
+		report, err := ic.imports.DryRun(ctx, file, mapping)
+		if err != nil {
+			writeImportError(ctx, c, err)
+			return
+		}
+		c.JSON(200, report)
+		return
+	}
+
+	job, err := ic.imports.Submit(ctx, file, mapping)
+	if err != nil {
+		writeImportError(ctx, c, err)
+		return
+	}
+
+	c.Header("Location", importJobPath(job.JobID))
+	c.JSON(http.StatusAccepted, types.SubmitImportResponse{
+		JobID:  job.JobID,
+		Status: job.Status,
+	})
+}
+
+func (ic *importController) GetImportJob(ctx context.Context, c *gin.Context) {
+	job, err := ic.jobs.GetImportJob(ctx, c.Param("jobId"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get import job", "error", err)
+		c.Error(err)
+		return
+	}
+
+	response := types.ImportJobResponse{ImportJob: *job}
+	if job.Rejected+job.Failed > 0 {
+		response.ErrorReport = importJobPath(job.JobID) + "/errors"
+	}
+	c.JSON(200, response)
+}
+
+// Downloads the rejected and failed lines of a job as CSV
+func (ic *importController) GetImportErrors(ctx context.Context, c *gin.Context) {
+	jobID := c.Param("jobId")
+	if _, err := ic.jobs.GetImportJob(ctx, jobID); err != nil {
+		slog.ErrorContext(ctx, "Failed to get import job", "error", err)
+		c.Error(err)
+		return
+	}
+	results, err := ic.jobs.ListImportErrors(ctx, jobID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get import errors", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "import-"+jobID+"-errors.csv"))
+	c.Status(200)
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"line", "ticketId", "result", "error"})
+	for _, result := range results {
+		writer.Write([]string{strconv.Itoa(result.Line), result.TicketID, result.Result, result.Error})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		slog.ErrorContext(ctx, "Failed to write import errors", "error", err)
+	}
+}
+
+// A file without the expected header is reported back to the caller
+func writeImportError(ctx context.Context, c *gin.Context, err error) {
+	slog.ErrorContext(ctx, "Failed to import file", "error", err)
+	if errors.Is(err, imports.ErrInvalidHeader) {
+		badRequest(c, err)
+		return
+	}
+	c.Error(fmt.Errorf("%w - %w", ErrImportError, err))
+}
+
+func importJobPath(jobID string) string {
+	return "/ticket/bulk-import/" + jobID
+}
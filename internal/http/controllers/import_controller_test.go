@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSubmitImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	submitter := imports.NewMockSubmitter(t)
+	controller := NewImportController(submitter, repositories.NewMockImportJobRepository(t))
+
+	csvBody := "Ticket #,description,status,assignedTo,createdBy\n1234,ticket A description,OPEN,andrew,hugo\n"
+	submitter.EXPECT().Submit(mock.Anything, mock.MatchedBy(func(r io.Reader) bool {
+		content, _ := io.ReadAll(r)
+		return string(content) == csvBody
+	}), models.ColumnMapping{"id": "Ticket #"}).Return(&models.ImportJob{JobID: "job-1", Status: models.ImportJobPending}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = importUpload("/ticket/bulk-import", csvBody, `{"id": "Ticket #"}`)
+
+	controller.SubmitImport(context.Background(), c)
+	HandleErrors()(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/ticket/bulk-import/job-1", w.Header().Get("Location"))
+	assert.JSONEq(t, `{"jobId": "job-1", "status": "PENDING"}`, w.Body.String())
+}
+
+func TestSubmitImportDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	submitter := imports.NewMockSubmitter(t)
+	controller := NewImportController(submitter, repositories.NewMockImportJobRepository(t))
+
+	submitter.EXPECT().DryRun(mock.Anything, mock.Anything, models.ColumnMapping(nil)).Return(&models.ImportReport{
+		Rejected: 1,
+		Valid:    1,
+		Results: []models.ImportLineResult{
+			{Line: 2, TicketID: "1234", Result: models.ImportValid},
+			{Line: 3, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - status"},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = importUpload("/ticket/bulk-import?dryRun=true", "id,description,status,assignedTo,createdBy\n", "")
+
+	controller.SubmitImport(context.Background(), c)
+	HandleErrors()(c)
+
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `{
+		"created": 0,
+		"rejected": 1,
+		"failed": 0,
+		"valid": 1,
+		"results": [
+			{"line": 2, "ticketId": "1234", "result": "valid"},
+			{"line": 3, "ticketId": "1235", "result": "rejected", "error": "wrong column - status"}
+		]
+	}`, w.Body.String())
+}
+
+func TestSubmitImportInvalidHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	submitter := imports.NewMockSubmitter(t)
+	controller := NewImportController(submitter, repositories.NewMockImportJobRepository(t))
+
+	submitter.EXPECT().Submit(mock.Anything, mock.Anything, models.ColumnMapping(nil)).
+		Return(nil, fmt.Errorf("%w - missing column \"id\"", imports.ErrInvalidHeader))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = importUpload("/ticket/bulk-import", "1234,ticket A description,OPEN,andrew,hugo\n", "")
+
+	controller.SubmitImport(context.Background(), c)
+	HandleErrors()(c)
+
+	assert.Equal(t, 400, w.Code)
+	assert.JSONEq(t, `{"type": "about:blank", "title": "Bad Request", "status": 400, "code": "bad_request",
+		"detail": "bad request - invalid import header - missing column \"id\""}`, w.Body.String())
+}
+
+// builds a multipart bulk import request, mapping is sent when not empty
+func importUpload(target string, csvBody string, mapping string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "import.csv")
+	part.Write([]byte(csvBody))
+	if mapping != "" {
+		writer.WriteField("mapping", mapping)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestGetImportJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*repositories.MockImportJobRepository)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "job with errors",
+			mockSetup: func(jobs *repositories.MockImportJobRepository) {
+				jobs.EXPECT().GetImportJob(mock.Anything, "job-1").Return(&models.ImportJob{
+					JobID:       "job-1",
+					Status:      models.ImportJobRunning,
+					SubmittedBy: "hugo",
+					SubmittedAt: "2024-01-01T00:00:00.000000000Z",
+					TotalLines:  120,
+					Processed:   51,
+					Created:     49,
+					Rejected:    1,
+					Failed:      1,
+				}, nil)
+			},
+			expectedStatus: 200,
+			expectedBody: `{
+				"jobId": "job-1",
+				"status": "RUNNING",
+				"submittedBy": "hugo",
+				"submittedAt": "2024-01-01T00:00:00.000000000Z",
+				"totalLines": 120,
+				"processed": 51,
+				"created": 49,
+				"rejected": 1,
+				"failed": 1,
+				"errorReport": "/ticket/bulk-import/job-1/errors"
+			}`,
+		},
+		{
+			name: "unknown job",
+			mockSetup: func(jobs *repositories.MockImportJobRepository) {
+				jobs.EXPECT().GetImportJob(mock.Anything, "job-1").Return(nil, repositories.ErrImportJobNotFound)
+			},
+			expectedStatus: 404,
+			expectedBody:   `{"type": "about:blank", "title": "Not Found", "status": 404, "code": "import_job_not_found", "detail": "import job not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := repositories.NewMockImportJobRepository(t)
+			tt.mockSetup(jobs)
+			controller := NewImportController(imports.NewMockSubmitter(t), jobs)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/ticket/bulk-import/job-1", nil)
+			c.Params = gin.Params{{Key: "jobId", Value: "job-1"}}
+
+			controller.GetImportJob(context.Background(), c)
+			HandleErrors()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetImportErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jobs := repositories.NewMockImportJobRepository(t)
+	controller := NewImportController(imports.NewMockSubmitter(t), jobs)
+
+	jobs.EXPECT().GetImportJob(mock.Anything, "job-1").Return(&models.ImportJob{JobID: "job-1"}, nil)
+	jobs.EXPECT().ListImportErrors(mock.Anything, "job-1").Return([]models.ImportLineResult{
+		{Line: 2, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - status"},
+		{Line: 3, TicketID: "1236", Result: models.ImportFailed, Error: "not written after 5 attempts"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/ticket/bulk-import/job-1/errors", nil)
+	c.Params = gin.Params{{Key: "jobId", Value: "job-1"}}
+
+	controller.GetImportErrors(context.Background(), c)
+	HandleErrors()(c)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "line,ticketId,result,error\n"+
+		"2,1235,rejected,wrong column - status\n"+
+		"3,1236,failed,not written after 5 attempts\n", w.Body.String())
+}
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"log/slog"
+
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type teamController struct {
+	teams   repositories.TeamRepository
+	tickets repositories.TicketRepository
+}
+
+func NewTeamController(teams repositories.TeamRepository, tickets repositories.TicketRepository) teamController {
+	return teamController{
+		teams:   teams,
+		tickets: tickets,
+	}
+}
+
+func (tc *teamController) CreateTeam(ctx context.Context, c *gin.Context) {
+	var req types.TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	team := req.ToTeam(uuid.NewString())
+	if err := tc.teams.CreateTeam(ctx, team); err != nil {
+		slog.ErrorContext(ctx, "Failed to create team", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"team": team})
+}
+
+func (tc *teamController) ListTeams(ctx context.Context, c *gin.Context) {
+	teams, err := tc.teams.ListTeams(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list teams", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"teams": teams})
+}
+
+func (tc *teamController) GetTeam(ctx context.Context, c *gin.Context) {
+	team, err := tc.teams.GetTeam(ctx, c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get team", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"team": team})
+}
+
+// Replaces the name and members of the team
+func (tc *teamController) UpdateTeam(ctx context.Context, c *gin.Context) {
+	var req types.TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	team := req.ToTeam(c.Param("id"))
+	if err := tc.teams.UpdateTeam(ctx, team); err != nil {
+		slog.ErrorContext(ctx, "Failed to update team", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"team": team})
+}
+
+func (tc *teamController) DeleteTeam(ctx context.Context, c *gin.Context) {
+	if err := tc.teams.DeleteTeam(ctx, c.Param("id")); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete team", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "team deleted"})
+}
+
+func (tc *teamController) CreateQueue(ctx context.Context, c *gin.Context) {
+	var req types.QueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	queue := req.ToQueue(uuid.NewString())
+	if err := tc.teams.CreateQueue(ctx, queue); err != nil {
+		slog.ErrorContext(ctx, "Failed to create queue", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"queue": queue})
+}
+
+func (tc *teamController) ListQueues(ctx context.Context, c *gin.Context) {
+	queues, err := tc.teams.ListQueues(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list queues", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"queues": queues})
+}
+
+func (tc *teamController) GetQueue(ctx context.Context, c *gin.Context) {
+	queue, err := tc.teams.GetQueue(ctx, c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get queue", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"queue": queue})
+}
+
+// Replaces the name and team of the queue. Tickets already claimed keep
+// their assignee.
+func (tc *teamController) UpdateQueue(ctx context.Context, c *gin.Context) {
+	var req types.QueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	queue := req.ToQueue(c.Param("id"))
+	if err := tc.teams.UpdateQueue(ctx, queue); err != nil {
+		slog.ErrorContext(ctx, "Failed to update queue", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"queue": queue})
+}
+
+func (tc *teamController) DeleteQueue(ctx context.Context, c *gin.Context) {
+	if err := tc.teams.DeleteQueue(ctx, c.Param("id")); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete queue", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "queue deleted"})
+}
+
+// Lists the tickets of the queue, the oldest first
+func (tc *teamController) ListQueueTickets(ctx context.Context, c *gin.Context) {
+	var req types.ListQueueTicketsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+	queue, err := tc.teams.GetQueue(ctx, c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get queue", "error", err)
+		c.Error(err)
+		return
+	}
+
+	tickets, err := tc.tickets.ListQueueTickets(ctx, queue.QueueID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list queue tickets", "error", err)
+		c.Error(err)
+		return
+	}
+	if req.Unclaimed {
+		unclaimed := tickets[:0]
+		for _, ticket := range tickets {
+			if ticket.AssignedTo == "" || ticket.AssignedTo == "None" {
+				unclaimed = append(unclaimed, ticket)
+			}
+		}
+		tickets = unclaimed
+	}
+	c.JSON(200, gin.H{"queue": queue, "tickets": tickets})
+}
@@ -2,24 +2,37 @@ package controllers
 
 import (
 	"context"
-	"encoding/csv"
 	"errors"
 	"io"
 	"log/slog"
-	"net/http"
+	"time"
 
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/exports"
 	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/identity"
 	"example.com/ticket-system/internal/models"
 	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/sla"
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	ErrBadRequest     = errors.New("bad request")
-	ErrImportError    = errors.New("import failure")
-	ErrCreatingTicket = errors.New("error creating ticket")
+	ErrBadRequest        = errors.New("bad request")
+	ErrImportError       = errors.New("import failure")
+	ErrCreatingTicket    = errors.New("error creating ticket")
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	ErrNotQueued         = &repositories.Error{Kind: repositories.ErrValidation, Code: "not_queued", Field: "queue", Message: "ticket is not in a queue"}
+	ErrAlreadyClaimed    = &repositories.Error{Kind: repositories.ErrConflict, Code: "already_claimed", Message: "ticket already claimed"}
+	ErrUnsupportedPatch  = errors.New("unsupported patch format, use application/merge-patch+json")
 )
 
+// Tickets read per repository call during an export
+const exportPageSize = 100
+
+// Window of the at-risk listing when the request does not set one
+const defaultAtRiskWindow = time.Hour
+
 type TicketController interface {
 	CreateTicket(ctx context.Context, c *gin.Context)
 	GetTicketDetails(ctx context.Context, c *gin.Context)
@@ -29,26 +42,38 @@ type TicketController interface {
 type ticketController struct {
 	// Note: in a larger system I would use a service that uses the repository
 	// For the POC the controller will directly call repository methods
-	repo repositories.TicketRepository
+	repo     repositories.TicketRepository
+	comments repositories.CommentRepository
+	policies *sla.Policies
+	assigner *assignment.Assigner
 }
 
-func NewTicketController(repo repositories.TicketRepository) ticketController {
+func NewTicketController(repo repositories.TicketRepository, comments repositories.CommentRepository, policies *sla.Policies, assigner *assignment.Assigner) ticketController {
 	return ticketController{
-		repo: repo,
+		repo:     repo,
+		comments: comments,
+		policies: policies,
+		assigner: assigner,
 	}
 }
 
 func (tc *ticketController) CreateTicket(ctx context.Context, c *gin.Context) {
 	var req types.CreateTicketRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		badRequest(c, err)
 		return
 	}
-	id, err := tc.repo.CreateTicket(ctx, req.ToTicket())
+	ticket := req.ToTicket(identity.Actor(ctx))
+	tc.policies.Apply(identity.Tenant(ctx), ticket, time.Now())
+	// queued tickets wait for a member of the queue to claim them
+	if ticket.Queue == "" {
+		tc.assigner.Assign(ctx, ticket)
+	}
+	id, err := tc.repo.CreateTicket(ctx, ticket)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to create ticket", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		c.Error(err)
 		return
 	}
 	slog.InfoContext(ctx, "Ticket created with", "id", id)
@@ -59,17 +84,46 @@ func (tc *ticketController) CreateTicket(ctx context.Context, c *gin.Context) {
 
 func (tc *ticketController) GetTicketDetails(ctx context.Context, c *gin.Context) {
 	id := c.Param("id")
+	var request types.GetTicketDetailsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
 
 	ticket, err := tc.repo.GetTicket(ctx, id)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get ticket", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(200, gin.H{
+	response := gin.H{
 		"ticket": ticket,
-	})
+	}
+	if ticket.SLA != nil {
+		response["sla"] = types.NewTicketSLAResponse(ticket.SLA, time.Now())
+	}
+	if request.Comments > 0 {
+		page, err := tc.comments.ListComments(ctx, id, models.PageRequest{
+			Limit:      request.Comments,
+			Descending: true,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get ticket comments", "error", err)
+			c.Error(err)
+			return
+		}
+		// most recent comments, shown in chronological order
+		comments := page.Comments
+		for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+			comments[i], comments[j] = comments[j], comments[i]
+		}
+		response["comments"] = comments
+	}
+
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, response)
 
 }
 
@@ -77,14 +131,14 @@ func (tc *ticketController) GetTicketsAssignedToSupportUser(ctx context.Context,
 	var request types.GetTicketsAssignedToRequest
 	if err := c.ShouldBindQuery(&request); err != nil {
 		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		badRequest(c, err)
 		return
 	}
 
 	tickets, err := tc.repo.GetTicketsAssignedTo(ctx, request.UserName)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get tickets", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -94,97 +148,388 @@ func (tc *ticketController) GetTicketsAssignedToSupportUser(ctx context.Context,
 
 }
 
+// Lists tickets matching the query filters, one page at a time
+func (tc *ticketController) ListTickets(ctx context.Context, c *gin.Context) {
+	var request types.ListTicketsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+
+	filter := request.ToFilter()
+	// requesters only see their own tickets
+	if caller, ok := identity.FromContext(ctx); ok && !caller.HasRole(identity.RoleAgent) {
+		filter.CreatedBy = caller.Subject
+	}
+	page, err := tc.repo.ListTickets(ctx, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list tickets", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(200, types.TicketPageResponse{
+		Tickets:    page.Tickets,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// Lists the tickets whose next SLA deadline falls within the requested
+// window, breached ones included, the most urgent first
+func (tc *ticketController) ListAtRiskTickets(ctx context.Context, c *gin.Context) {
+	var request types.ListAtRiskTicketsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+	if request.Within == 0 {
+		request.Within = defaultAtRiskWindow
+	}
+
+	now := time.Now()
+	tickets, err := tc.repo.ListTicketsDueBefore(ctx, now.Add(request.Within))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list tickets at risk", "error", err)
+		c.Error(err)
+		return
+	}
+
+	response := types.AtRiskTicketsResponse{Tickets: []types.AtRiskTicket{}}
+	for _, ticket := range tickets {
+		response.Tickets = append(response.Tickets, types.AtRiskTicket{
+			Ticket: ticket,
+			SLA:    types.NewTicketSLAResponse(ticket.SLA, now),
+		})
+	}
+	c.JSON(200, response)
+}
+
+// Lists the open tickets of a priority, the oldest first
+func (tc *ticketController) ListOpenTickets(ctx context.Context, c *gin.Context) {
+	var request types.ListOpenTicketsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+
+	tickets, err := tc.repo.ListOpenTickets(ctx, request.Priority)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list open tickets", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"tickets": tickets})
+}
+
+// Streams every ticket matching the filters as CSV, JSON or NDJSON. Tickets
+// are read one page at a time and written as they arrive.
+func (tc *ticketController) ExportTickets(ctx context.Context, c *gin.Context) {
+	var request types.ExportTicketsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+	format := request.Format
+	if format == "" {
+		var ok bool
+		if format, ok = exports.FormatFromAccept(c.GetHeader("Accept")); !ok {
+			c.Error(ErrUnsupportedFormat)
+			return
+		}
+	}
+
+	filter := request.ToFilter()
+	filter.Page.Limit = exportPageSize
+	page, err := tc.repo.ListTickets(ctx, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to export tickets", "error", err)
+		c.Error(err)
+		return
+	}
+
+	// once the first page is written the status can no longer change, a
+	// failure after that leaves the document incomplete
+	encoder := exports.NewEncoder(format, c.Writer)
+	c.Header("Content-Type", encoder.ContentType())
+	if format == exports.FormatCSV {
+		c.Header("Content-Disposition", `attachment; filename="tickets.csv"`)
+	}
+	c.Status(200)
+	if err := encoder.Begin(); err != nil {
+		slog.ErrorContext(ctx, "Failed to write export", "error", err)
+		return
+	}
+	for {
+		for i := range page.Tickets {
+			if err := encoder.Encode(&page.Tickets[i]); err != nil {
+				slog.ErrorContext(ctx, "Failed to write export", "error", err)
+				return
+			}
+		}
+		c.Writer.Flush()
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Page.Cursor = page.NextCursor
+		if page, err = tc.repo.ListTickets(ctx, filter); err != nil {
+			slog.ErrorContext(ctx, "Failed to export tickets", "error", err)
+			return
+		}
+	}
+	if err := encoder.End(); err != nil {
+		slog.ErrorContext(ctx, "Failed to write export", "error", err)
+	}
+}
+
 func (tc *ticketController) UpdateStatus(ctx context.Context, c *gin.Context) error {
 	id := c.Param("id")
 	var req struct {
 		Status string `json:"status" binding:"required"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return err
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
 		return err
 	}
 
-	err := tc.repo.UpdateStatus(ctx, id, req.Status)
+	ticket, err := tc.repo.UpdateStatus(ctx, id, req.Status, expectedVersion)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to update ticket status", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		c.Error(err)
 		return err
 	}
 
+	c.Header("ETag", etag(ticket.Version))
 	c.JSON(200, gin.H{"message": "status updated"})
 	return nil
 }
 
+// Applies a JSON merge patch to the ticket as returned by GetTicketDetails,
+// e.g. {"Description": "...", "Tags": null}. Fields managed by the system
+// cannot be changed.
+func (tc *ticketController) PatchTicket(ctx context.Context, c *gin.Context) {
+	switch c.ContentType() {
+	case "application/merge-patch+json", "application/json":
+	default:
+		c.Error(ErrUnsupportedPatch)
+		return
+	}
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ticket, err := tc.repo.PatchTicket(ctx, c.Param("id"), patch, expectedVersion)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to patch ticket", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"ticket": ticket})
+}
+
 func (tc *ticketController) UpdateAssignTo(ctx context.Context, c *gin.Context) {
 
 	var request types.AssignToRequest
 	request.TicketID = c.Param("id")
 
-	if err := c.BindJSON(&request); err != nil {
+	if err := c.ShouldBindJSON(&request); err != nil {
 		slog.ErrorContext(ctx, "Failed to assign ticket", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		badRequest(c, err)
+		return
+	}
+	if request.Assignee == "" {
+		request.Assignee = identity.Actor(ctx)
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	err := tc.repo.UpdateAssignTo(ctx, request.TicketID, request.Assignee)
+	ticket, err := tc.repo.UpdateAssignTo(ctx, request.TicketID, request.Assignee, expectedVersion)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to assign ticket", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		c.Error(err)
 		return
 	}
 
+	c.Header("ETag", etag(ticket.Version))
 	c.JSON(200, gin.H{"message": "assignee updated"})
 }
 
-func (tc *ticketController) BulkCreate(ctx context.Context, c *gin.Context) {
-	file, _, err := c.Request.FormFile("file")
+// Moves the ticket to a queue, where it waits for a member of the queue's
+// team to claim it
+func (tc *ticketController) MoveToQueue(ctx context.Context, c *gin.Context) {
+	var request types.MoveToQueueRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		badRequest(c, err)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to receive file", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+		c.Error(err)
 		return
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = ','
-	reader.TrimLeadingSpace = true
+	ticket, err := tc.repo.MoveTicketToQueue(ctx, c.Param("id"), request.Queue, expectedVersion)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to move ticket to queue", "error", err)
+		c.Error(err)
+		return
+	}
 
-	// returns lines numbers of failed records
-	badRecords := make(map[int]string)
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"message": "queue updated"})
+}
 
-	lineNumber := 0
-	var entries []models.Ticket
+// Assigns a queued ticket nobody claimed yet to the caller, who must be a
+// member of the queue's team
+func (tc *ticketController) ClaimTicket(ctx context.Context, c *gin.Context) {
+	id := c.Param("id")
+	ticket, err := tc.repo.GetTicket(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get ticket", "error", err)
+		c.Error(err)
+		return
+	}
+	if ticket.Queue == "" {
+		c.Error(ErrNotQueued)
+		return
+	}
+	if ticket.AssignedTo != "" && ticket.AssignedTo != "None" {
+		c.Error(ErrAlreadyClaimed)
+		return
+	}
 
-	//NOTE: this should be part of a service
-	for {
-		lineNumber += 1
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			slog.ErrorContext(ctx, "Error parsing ticket from CSV", "error", err)
-			continue
-		}
+	// a concurrent claim changes the version, the second one conflicts
+	ticket, err = tc.repo.UpdateAssignTo(ctx, id, identity.Actor(ctx), ticket.Version)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to claim ticket", "error", err)
+		c.Error(err)
+		return
+	}
 
-		ticketRecord := models.BulkImportRecord{}
-		err = ticketRecord.LoadFromRecord(record)
-		if err != nil {
-			slog.ErrorContext(ctx, "Error parsing ticket from CSV", "error", err)
-			badRecords[lineNumber] = err.Error()
-			continue
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"message": "ticket claimed"})
+}
 
-		}
-		entries = append(entries, ticketRecord.ToTicket())
+func (tc *ticketController) UpdatePriority(ctx context.Context, c *gin.Context) {
+	var request types.UpdatePriorityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		badRequest(c, err)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	err = tc.repo.BulkImport(ctx, entries)
+	ticket, err := tc.repo.UpdatePriority(ctx, c.Param("id"), request.Priority, expectedVersion)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to import records", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrImportError.Error()})
+		slog.ErrorContext(ctx, "Failed to update ticket priority", "error", err)
+		c.Error(err)
 		return
 	}
 
-	c.JSON(200, gin.H{"message": "OK", "entries": entries})
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"message": "priority updated"})
+}
 
+// Adds the tags the ticket does not have yet
+func (tc *ticketController) AddTags(ctx context.Context, c *gin.Context) {
+	var request types.TagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		badRequest(c, err)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ticket, err := tc.repo.UpdateTags(ctx, c.Param("id"), request.Tags, nil, expectedVersion)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to tag ticket", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"tags": ticket.Tags})
+}
+
+// Removing a tag the ticket does not have still writes a new version
+func (tc *ticketController) RemoveTag(ctx context.Context, c *gin.Context) {
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ticket, err := tc.repo.UpdateTags(ctx, c.Param("id"), nil, []string{c.Param("tag")}, expectedVersion)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to untag ticket", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"tags": ticket.Tags})
+}
+
+// Soft deletes the ticket, it can be restored until an admin purges it
+func (tc *ticketController) DeleteTicket(ctx context.Context, c *gin.Context) {
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if _, err := tc.repo.DeleteTicket(ctx, c.Param("id"), expectedVersion); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete ticket", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "ticket deleted"})
+}
+
+func (tc *ticketController) RestoreTicket(ctx context.Context, c *gin.Context) {
+	ticket, err := tc.repo.RestoreTicket(ctx, c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to restore ticket", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(ticket.Version))
+	c.JSON(200, gin.H{"ticket": ticket})
+}
+
+// Removes the ticket with its comments and history for good
+func (tc *ticketController) PurgeTicket(ctx context.Context, c *gin.Context) {
+	if err := tc.repo.PurgeTicket(ctx, c.Param("id")); err != nil {
+		slog.ErrorContext(ctx, "Failed to purge ticket", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "ticket purged"})
 }
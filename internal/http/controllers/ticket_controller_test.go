@@ -4,13 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"example.com/ticket-system/internal/assignment"
 	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/identity"
 	"example.com/ticket-system/internal/models"
 	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/sla"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/smithy-go"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,7 +38,6 @@ func TestCreateTicket(t *testing.T) {
 			name: "successful ticket creation",
 			requestBody: types.CreateTicketRequest{
 				Description: "Test ticket",
-				CreatedBy:   "testuser",
 			},
 			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
 				mockRepo.EXPECT().CreateTicket(mock.Anything, mock.MatchedBy(func(ticket *models.Ticket) bool {
@@ -43,24 +50,84 @@ func TestCreateTicket(t *testing.T) {
 			expectedStatus: 200,
 			expectedBody:   `{"id":"ticket-123"}`,
 		},
+		{
+			name: "priority selects the SLA policy",
+			requestBody: types.CreateTicketRequest{
+				Description: "Test ticket",
+				Priority:    "URGENT",
+			},
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().CreateTicket(mock.Anything, mock.MatchedBy(func(ticket *models.Ticket) bool {
+					return ticket.Priority == "URGENT" &&
+						ticket.SLA != nil &&
+						ticket.SLA.Policy == "urgent" &&
+						ticket.SLA.FirstResponseDue < ticket.SLA.ResolutionDue
+				})).Return("ticket-123", nil)
+			},
+			expectedStatus: 200,
+			expectedBody:   `{"id":"ticket-123"}`,
+		},
+		{
+			name: "unknown priority",
+			requestBody: types.CreateTicketRequest{
+				Description: "Test ticket",
+				Priority:    "SOON",
+			},
+			mockSetup:      func(mockRepo *repositories.MockTicketRepository) {},
+			expectedStatus: 422,
+			expectedBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"code":"invalid_request",
+				"detail":"the request has invalid fields","errors":[{"field":"priority","code":"oneof"}]}`,
+		},
+		{
+			name:           "no description",
+			requestBody:    types.CreateTicketRequest{},
+			mockSetup:      func(mockRepo *repositories.MockTicketRepository) {},
+			expectedStatus: 422,
+			expectedBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"code":"invalid_request",
+				"detail":"the request has invalid fields","errors":[{"field":"description","code":"required"}]}`,
+		},
+		{
+			name: "description too long",
+			requestBody: types.CreateTicketRequest{
+				Description: strings.Repeat("a", 4001),
+				Tags:        []string{"a;b"},
+			},
+			mockSetup:      func(mockRepo *repositories.MockTicketRepository) {},
+			expectedStatus: 422,
+			expectedBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"code":"invalid_request",
+				"detail":"the request has invalid fields","errors":[{"field":"description","code":"max"},{"field":"tags[0]","code":"excludesall"}]}`,
+		},
+		{
+			name: "unknown queue",
+			requestBody: types.CreateTicketRequest{
+				Description: "Test ticket",
+				Queue:       "billing",
+			},
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().CreateTicket(mock.Anything, mock.Anything).
+					Return("", fmt.Errorf("%w - %w", repositories.ErrUnknownQueue, repositories.ErrQueueNotFound))
+			},
+			expectedStatus: 422,
+			expectedBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"code":"unknown_queue",
+				"detail":"ticket queue does not exist - queue not found","errors":[{"field":"queue","code":"unknown_queue"}]}`,
+		},
 		{
 			name: "repository error",
 			requestBody: types.CreateTicketRequest{
 				Description: "Test ticket",
-				CreatedBy:   "testuser",
 			},
 			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
 				mockRepo.EXPECT().CreateTicket(mock.Anything, mock.Anything).Return("", assert.AnError)
 			},
-			expectedStatus: 400,
-			expectedBody:   `{"error":"bad request"}`,
+			expectedStatus: 500,
+			expectedBody:   `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_server_error"}`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := repositories.NewMockTicketRepository(t)
-			controller := NewTicketController(mockRepo)
+			controller := NewTicketController(mockRepo, repositories.NewMockCommentRepository(t), sla.Default(), &assignment.Assigner{})
 
 			tt.mockSetup(mockRepo)
 
@@ -72,7 +139,10 @@ func TestCreateTicket(t *testing.T) {
 			c, _ := gin.CreateTestContext(w)
 			c.Request = req
 
-			controller.CreateTicket(context.Background(), c)
+			// the creator comes from the caller identity, not the body
+			ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "testuser", Roles: []string{identity.RoleRequester}})
+			controller.CreateTicket(ctx, c)
+			HandleErrors()(c)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			assert.JSONEq(t, tt.expectedBody, w.Body.String())
@@ -89,6 +159,7 @@ func TestGetTicketDetails(t *testing.T) {
 		mockSetup      func(*repositories.MockTicketRepository)
 		expectedStatus int
 		expectedBody   string
+		expectedETag   string
 	}{
 		{
 			name:     "successful ticket retrieval",
@@ -100,7 +171,8 @@ func TestGetTicketDetails(t *testing.T) {
 					Status:      models.StatusOpen,
 					CreatedBy:   "testuser",
 					AssignedTo:  "None",
-					CreatedAt:   "2023-01-01T00:00:00Z",
+					CreatedAt:   models.NewTimestamp(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+					Version:     3,
 				}
 				mockRepo.EXPECT().GetTicket(mock.Anything, "ticket-123").Return(ticket, nil)
 			},
@@ -112,9 +184,11 @@ func TestGetTicketDetails(t *testing.T) {
 					"Status": "OPEN",
 					"CreatedBy": "testuser",
 					"CreatedAt": "2023-01-01T00:00:00Z",
-					"AssignedTo": "None"
+					"AssignedTo": "None",
+					"Version": 3
 				}
 			}`,
+			expectedETag: `"3"`,
 		},
 		{
 			name:     "repository error",
@@ -122,15 +196,38 @@ func TestGetTicketDetails(t *testing.T) {
 			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
 				mockRepo.EXPECT().GetTicket(mock.Anything, "ticket-456").Return(nil, assert.AnError)
 			},
-			expectedStatus: 400,
-			expectedBody:   `{"error":"bad request"}`,
+			expectedStatus: 500,
+			expectedBody:   `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_server_error"}`,
+		},
+		{
+			name:     "ticket not found",
+			ticketID: "ticket-456",
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().GetTicket(mock.Anything, "ticket-456").
+					Return(nil, fmt.Errorf("%w - %w", repositories.ErrLoadingTicket, repositories.ErrTicketNotFound))
+			},
+			expectedStatus: 404,
+			expectedBody: `{"type":"about:blank","title":"Not Found","status":404,"code":"ticket_not_found",
+				"detail":"error loading ticket from database - ticket not found"}`,
+		},
+		{
+			name:     "storage unavailable",
+			ticketID: "ticket-456",
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				err := &smithy.OperationError{ServiceID: "DynamoDB", OperationName: "GetItem", Err: assert.AnError}
+				mockRepo.EXPECT().GetTicket(mock.Anything, "ticket-456").
+					Return(nil, fmt.Errorf("%w - %w", repositories.ErrLoadingTicket, err))
+			},
+			expectedStatus: 503,
+			expectedBody: `{"type":"about:blank","title":"Service Unavailable","status":503,"code":"storage_unavailable",
+				"detail":"storage unavailable"}`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := repositories.NewMockTicketRepository(t)
-			controller := NewTicketController(mockRepo)
+			controller := NewTicketController(mockRepo, repositories.NewMockCommentRepository(t), sla.Default(), &assignment.Assigner{})
 
 			tt.mockSetup(mockRepo)
 
@@ -141,9 +238,219 @@ func TestGetTicketDetails(t *testing.T) {
 			c.Params = gin.Params{{Key: "id", Value: tt.ticketID}}
 
 			controller.GetTicketDetails(context.Background(), c)
+			HandleErrors()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestGetTicketDetailsWithComments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := repositories.NewMockTicketRepository(t)
+	mockComments := repositories.NewMockCommentRepository(t)
+	controller := NewTicketController(mockRepo, mockComments, sla.Default(), &assignment.Assigner{})
+
+	mockRepo.EXPECT().GetTicket(mock.Anything, "ticket-123").Return(&models.Ticket{TicketID: "ticket-123", Version: 1}, nil)
+	mockComments.EXPECT().ListComments(mock.Anything, "ticket-123", models.PageRequest{Limit: 2, Descending: true}).
+		Return(&models.CommentPage{Comments: []models.Comment{
+			{CommentID: "c2", Body: "second"},
+			{CommentID: "c1", Body: "first"},
+		}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/ticket/ticket-123?comments=2", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "ticket-123"}}
+
+	controller.GetTicketDetails(context.Background(), c)
+	HandleErrors()(c)
+
+	assert.Equal(t, 200, w.Code)
+	var body struct {
+		Comments []models.Comment `json:"comments"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	if assert.Len(t, body.Comments, 2) {
+		assert.Equal(t, "first", body.Comments[0].Body)
+		assert.Equal(t, "second", body.Comments[1].Body)
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		mockSetup      func(*repositories.MockTicketRepository)
+		expectedStatus int
+		expectedBody   string
+		expectedETag   string
+	}{
+		{
+			name:    "successful update with If-Match",
+			ifMatch: `"2"`,
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().UpdateStatus(mock.Anything, "ticket-123", "CLOSED", int64(2)).
+					Return(&models.Ticket{TicketID: "ticket-123", Status: models.StatusClosed, Version: 3}, nil)
+			},
+			expectedStatus: 200,
+			expectedBody:   `{"message":"status updated"}`,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:    "stale If-Match",
+			ifMatch: `"1"`,
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().UpdateStatus(mock.Anything, "ticket-123", "CLOSED", int64(1)).
+					Return(nil, repositories.ErrPreconditionFailed)
+			},
+			expectedStatus: 412,
+			expectedBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"code":"precondition_failed",
+				"detail":"ticket version does not match"}`,
+		},
+		{
+			name: "concurrent write",
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().UpdateStatus(mock.Anything, "ticket-123", "CLOSED", int64(0)).
+					Return(nil, repositories.ErrVersionConflict)
+			},
+			expectedStatus: 409,
+			expectedBody: `{"type":"about:blank","title":"Conflict","status":409,"code":"version_conflict",
+				"detail":"ticket was modified concurrently"}`,
+		},
+		{
+			name: "transition not allowed",
+			mockSetup: func(mockRepo *repositories.MockTicketRepository) {
+				mockRepo.EXPECT().UpdateStatus(mock.Anything, "ticket-123", "CLOSED", int64(0)).
+					Return(nil, fmt.Errorf("%w - %w", repositories.ErrInvalidStatus,
+						&workflow.TransitionError{From: models.StatusResolved, To: models.StatusClosed, Reason: "ticket must be assigned"}))
+			},
+			expectedStatus: 422,
+			expectedBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"code":"invalid_status",
+				"detail":"invalid status - cannot change status from RESOLVED to CLOSED: ticket must be assigned",
+				"errors":[{"field":"status","code":"invalid_status"}]}`,
+		},
+		{
+			name:           "malformed If-Match",
+			ifMatch:        "abc",
+			mockSetup:      func(mockRepo *repositories.MockTicketRepository) {},
+			expectedStatus: 400,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"code":"bad_request",
+				"detail":"invalid If-Match header"}`,
+		},
+		{
+			name:           "If-Match of no version",
+			ifMatch:        `"abc"`,
+			mockSetup:      func(mockRepo *repositories.MockTicketRepository) {},
+			expectedStatus: 412,
+			expectedBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"code":"precondition_failed",
+				"detail":"ticket version does not match - no version matches \"abc\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockTicketRepository(t)
+			controller := NewTicketController(mockRepo, repositories.NewMockCommentRepository(t), sla.Default(), &assignment.Assigner{})
+
+			tt.mockSetup(mockRepo)
+
+			req := httptest.NewRequest(http.MethodPatch, "/ticket/ticket-123/status", bytes.NewBufferString(`{"status":"CLOSED"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "id", Value: "ticket-123"}}
+
+			controller.UpdateStatus(context.Background(), c)
+			HandleErrors()(c)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestExportTickets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	first := models.Ticket{TicketID: "1234", Description: "ticket A", Status: models.StatusOpen, CreatedBy: "hugo", AssignedTo: "andrew", CreatedAt: models.NewTimestamp(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))}
+	second := models.Ticket{TicketID: "1235", Description: "ticket B", Status: models.StatusOpen, CreatedBy: "hugo", AssignedTo: "andrew", CreatedAt: models.NewTimestamp(time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC))}
+
+	tests := []struct {
+		name           string
+		target         string
+		accept         string
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "csv from format parameter",
+			target:         "/tickets/export?assignee=andrew&format=csv",
+			accept:         "application/json",
+			expectedStatus: 200,
+			expectedType:   "text/csv",
+			expectedBody: "id,description,status,assignedTo,createdBy,priority,severity,category,tags,createdAt\n" +
+				"1234,ticket A,OPEN,andrew,hugo,,,,,2024-03-01T10:00:00Z\n" +
+				"1235,ticket B,OPEN,andrew,hugo,,,,,2024-03-02T10:00:00Z\n",
+		},
+		{
+			name:           "ndjson from accept header",
+			target:         "/tickets/export?assignee=andrew",
+			accept:         "application/x-ndjson",
+			expectedStatus: 200,
+			expectedType:   "application/x-ndjson",
+			expectedBody: `{"TicketID":"1234","Description":"ticket A","Status":"OPEN","CreatedBy":"hugo","CreatedAt":"2024-03-01T10:00:00Z","AssignedTo":"andrew","Version":0}` + "\n" +
+				`{"TicketID":"1235","Description":"ticket B","Status":"OPEN","CreatedBy":"hugo","CreatedAt":"2024-03-02T10:00:00Z","AssignedTo":"andrew","Version":0}` + "\n",
+		},
+		{
+			name:           "unsupported accept header",
+			target:         "/tickets/export?assignee=andrew",
+			accept:         "text/html",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedType:   "application/problem+json",
+			expectedBody:   `{"type":"about:blank","title":"Not Acceptable","status":406,"detail":"unsupported export format","code":"not_acceptable"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockTicketRepository(t)
+			controller := NewTicketController(mockRepo, repositories.NewMockCommentRepository(t), sla.Default(), &assignment.Assigner{})
+
+			if tt.expectedStatus == 200 {
+				// two pages, the second requested with the cursor of the first
+				mockRepo.EXPECT().ListTickets(mock.Anything, mock.MatchedBy(func(filter models.TicketFilter) bool {
+					return filter.AssignedTo == "andrew" && filter.Page.Limit == 100 && filter.Page.Cursor == ""
+				})).Return(&models.TicketPage{Tickets: []models.Ticket{first}, NextCursor: "next"}, nil).Once()
+				mockRepo.EXPECT().ListTickets(mock.Anything, mock.MatchedBy(func(filter models.TicketFilter) bool {
+					return filter.Page.Cursor == "next"
+				})).Return(&models.TicketPage{Tickets: []models.Ticket{second}}, nil).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			controller.ExportTickets(context.Background(), c)
+			HandleErrors()(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
 		})
 	}
 }
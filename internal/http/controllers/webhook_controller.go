@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrInvalidWebhookURL   = &repositories.Error{Kind: repositories.ErrValidation, Code: "invalid_webhook_url", Field: "url", Message: "webhook url must be http or https"}
+	ErrUnknownWebhookEvent = &repositories.Error{Kind: repositories.ErrValidation, Code: "unknown_webhook_event", Field: "events", Message: "unknown webhook event type"}
+)
+
+type webhookController struct {
+	webhooks repositories.WebhookRepository
+}
+
+func NewWebhookController(webhooks repositories.WebhookRepository) webhookController {
+	return webhookController{
+		webhooks: webhooks,
+	}
+}
+
+// Subscribes a URL to events of the caller's tenant
+func (wc *webhookController) RegisterWebhook(ctx context.Context, c *gin.Context) {
+	var req types.RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if target, err := url.Parse(req.URL); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		c.Error(ErrInvalidWebhookURL)
+		return
+	}
+	for _, eventType := range req.Events {
+		if !eventType.Valid() {
+			c.Error(fmt.Errorf("%w - %s", ErrUnknownWebhookEvent, eventType))
+			return
+		}
+	}
+
+	webhook := req.ToSubscription(identity.Actor(ctx))
+	if err := wc.webhooks.CreateWebhook(ctx, webhook); err != nil {
+		slog.ErrorContext(ctx, "Failed to register webhook", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"webhook": webhook})
+}
+
+func (wc *webhookController) ListWebhooks(ctx context.Context, c *gin.Context) {
+	webhooks, err := wc.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list webhooks", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"webhooks": webhooks})
+}
+
+func (wc *webhookController) DeleteWebhook(ctx context.Context, c *gin.Context) {
+	err := wc.webhooks.DeleteWebhook(ctx, c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete webhook", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "webhook deleted"})
+}
+
+// Lists the deliveries that failed every attempt, most recent first
+func (wc *webhookController) ListDeadLetters(ctx context.Context, c *gin.Context) {
+	var req types.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		slog.ErrorContext(ctx, "Failed to read parameters", "error", err)
+		badRequest(c, err)
+		return
+	}
+
+	page := req.ToPageRequest()
+	page.Descending = true
+	letters, err := wc.webhooks.ListDeadLetters(ctx, page)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list webhook dead letters", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(200, types.WebhookDeadLetterPageResponse{
+		DeadLetters: letters.DeadLetters,
+		NextCursor:  letters.NextCursor,
+	})
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"example.com/ticket-system/internal/auth"
+	"example.com/ticket-system/internal/http/controllers"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// Identifies the caller and its tenant, requests without a valid token are
+// rejected
+func authenticate(authenticator auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := authenticator.Authenticate(c.Request)
+		if err == nil && !identity.ValidUsername(id.Subject) {
+			// the subject becomes the creator and assignee of tickets
+			err = fmt.Errorf("invalid subject %q", id.Subject)
+		}
+		if err != nil {
+			slog.InfoContext(c.Request.Context(), "Rejected request", "error", err)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			controllers.AbortWithProblem(c, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+		id, err = auth.ResolveTenant(id, c.GetHeader(auth.TenantHeader))
+		if err != nil {
+			slog.InfoContext(c.Request.Context(), "Rejected tenant", "subject", id.Subject, "error", err)
+			controllers.AbortWithProblem(c, http.StatusForbidden, err)
+			return
+		}
+		c.Request = c.Request.WithContext(identity.WithIdentity(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, _ := identity.FromContext(c.Request.Context())
+		if !id.HasRole(role) {
+			controllers.AbortWithProblem(c, http.StatusForbidden, ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Requesters only reach the tickets they created, agents reach all of them
+func requireTicketAccess(tickets repositories.TicketRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		id, _ := identity.FromContext(ctx)
+		if id.HasRole(identity.RoleAgent) {
+			c.Next()
+			return
+		}
+		if !id.HasRole(identity.RoleRequester) {
+			controllers.AbortWithProblem(c, http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		ticket, err := tickets.GetTicket(ctx, c.Param("id"))
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get ticket", "error", err)
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if ticket.CreatedBy != id.Subject {
+			controllers.AbortWithProblem(c, http.StatusForbidden, ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,239 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/auth"
+	"example.com/ticket-system/internal/http/controllers"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/sla"
+	"github.com/gin-gonic/gin"
+)
+
+var ErrRouteNotFound = errors.New("route not found")
+
+// New builds the gin engine serving the ticket API. It is shared by the
+// Lambda entrypoint and the standalone HTTP server. Bulk imports are handed
+// to importer and run in the background. Every route requires a caller
+// identified by authenticator. New tickets get the deadlines of the first
+// matching SLA policy and an assignee picked by assigner.
+func New(repos repositories.Repositories, importer imports.Submitter, authenticator auth.Authenticator, policies *sla.Policies, assigner *assignment.Assigner) *gin.Engine {
+	if os.Getenv("GIN_MODE") != "" {
+		gin.SetMode(os.Getenv("GIN_MODE"))
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+	router.Use(gin.Logger())
+	router.Use(gin.Recovery())
+	// answers with a problem for the errors the handlers record
+	router.Use(controllers.HandleErrors())
+
+	controller := controllers.NewTicketController(repos.Tickets, repos.Comments, policies, assigner)
+	commentController := controllers.NewCommentController(repos.Comments)
+	historyController := controllers.NewHistoryController(repos.History)
+	importController := controllers.NewImportController(importer, repos.ImportJobs)
+	webhookController := controllers.NewWebhookController(repos.Webhooks)
+	teamController := controllers.NewTeamController(repos.Teams, repos.Tickets)
+
+	// Add CORS
+	router.Use(func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, X-Tenant-ID")
+		c.Header("Access-Control-Expose-Headers", "ETag, Location")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+		c.Next()
+	})
+
+	// Identifies the caller, for authorization and the audit history
+	router.Use(authenticate(authenticator))
+
+	requester := requireRole(identity.RoleRequester)
+	agent := requireRole(identity.RoleAgent)
+	admin := requireRole(identity.RoleAdmin)
+	ticketAccess := requireTicketAccess(repos.Tickets)
+
+	router.PUT("/ticket", requester, func(c *gin.Context) {
+		controller.CreateTicket(c.Request.Context(), c)
+	})
+
+	router.GET("/ticket/:id", ticketAccess, func(c *gin.Context) {
+		controller.GetTicketDetails(c.Request.Context(), c)
+	})
+
+	router.GET("/ticket/assigned", agent, func(c *gin.Context) {
+		controller.GetTicketsAssignedToSupportUser(c.Request.Context(), c)
+	})
+
+	router.GET("/tickets", requester, func(c *gin.Context) {
+		controller.ListTickets(c.Request.Context(), c)
+	})
+
+	router.GET("/tickets/export", agent, func(c *gin.Context) {
+		controller.ExportTickets(c.Request.Context(), c)
+	})
+
+	router.GET("/tickets/at-risk", agent, func(c *gin.Context) {
+		controller.ListAtRiskTickets(c.Request.Context(), c)
+	})
+
+	router.GET("/tickets/open", agent, func(c *gin.Context) {
+		controller.ListOpenTickets(c.Request.Context(), c)
+	})
+
+	router.PATCH("/ticket/:id", agent, func(c *gin.Context) {
+		controller.PatchTicket(c.Request.Context(), c)
+	})
+
+	router.DELETE("/ticket/:id", agent, func(c *gin.Context) {
+		controller.DeleteTicket(c.Request.Context(), c)
+	})
+
+	router.POST("/ticket/:id/restore", agent, func(c *gin.Context) {
+		controller.RestoreTicket(c.Request.Context(), c)
+	})
+
+	router.DELETE("/ticket/:id/purge", admin, func(c *gin.Context) {
+		controller.PurgeTicket(c.Request.Context(), c)
+	})
+
+	router.PATCH("/ticket/:id/status", agent, func(c *gin.Context) {
+		controller.UpdateStatus(c.Request.Context(), c)
+	})
+
+	router.PATCH("/ticket/:id/assignto", agent, func(c *gin.Context) {
+		controller.UpdateAssignTo(c.Request.Context(), c)
+	})
+
+	router.PATCH("/ticket/:id/queue", agent, func(c *gin.Context) {
+		controller.MoveToQueue(c.Request.Context(), c)
+	})
+
+	router.POST("/ticket/:id/claim", agent, func(c *gin.Context) {
+		controller.ClaimTicket(c.Request.Context(), c)
+	})
+
+	router.PATCH("/ticket/:id/priority", agent, func(c *gin.Context) {
+		controller.UpdatePriority(c.Request.Context(), c)
+	})
+
+	router.POST("/ticket/:id/tags", agent, func(c *gin.Context) {
+		controller.AddTags(c.Request.Context(), c)
+	})
+
+	router.DELETE("/ticket/:id/tags/:tag", agent, func(c *gin.Context) {
+		controller.RemoveTag(c.Request.Context(), c)
+	})
+
+	router.POST("/ticket/bulk-import", admin, func(c *gin.Context) {
+		importController.SubmitImport(c.Request.Context(), c)
+	})
+
+	router.GET("/ticket/bulk-import/:jobId", admin, func(c *gin.Context) {
+		importController.GetImportJob(c.Request.Context(), c)
+	})
+
+	router.GET("/ticket/bulk-import/:jobId/errors", admin, func(c *gin.Context) {
+		importController.GetImportErrors(c.Request.Context(), c)
+	})
+
+	router.POST("/ticket/:id/comments", ticketAccess, func(c *gin.Context) {
+		commentController.AddComment(c.Request.Context(), c)
+	})
+
+	router.GET("/ticket/:id/comments", ticketAccess, func(c *gin.Context) {
+		commentController.ListComments(c.Request.Context(), c)
+	})
+
+	router.PATCH("/ticket/:id/comments/:commentId", agent, func(c *gin.Context) {
+		commentController.UpdateComment(c.Request.Context(), c)
+	})
+
+	router.DELETE("/ticket/:id/comments/:commentId", agent, func(c *gin.Context) {
+		commentController.DeleteComment(c.Request.Context(), c)
+	})
+
+	router.GET("/ticket/:id/history", ticketAccess, func(c *gin.Context) {
+		historyController.GetTicketHistory(c.Request.Context(), c)
+	})
+
+	router.POST("/webhooks", admin, func(c *gin.Context) {
+		webhookController.RegisterWebhook(c.Request.Context(), c)
+	})
+
+	router.GET("/webhooks", admin, func(c *gin.Context) {
+		webhookController.ListWebhooks(c.Request.Context(), c)
+	})
+
+	router.DELETE("/webhooks/:id", admin, func(c *gin.Context) {
+		webhookController.DeleteWebhook(c.Request.Context(), c)
+	})
+
+	router.GET("/webhooks/dead-letters", admin, func(c *gin.Context) {
+		webhookController.ListDeadLetters(c.Request.Context(), c)
+	})
+
+	router.POST("/teams", admin, func(c *gin.Context) {
+		teamController.CreateTeam(c.Request.Context(), c)
+	})
+
+	router.GET("/teams", agent, func(c *gin.Context) {
+		teamController.ListTeams(c.Request.Context(), c)
+	})
+
+	router.GET("/teams/:id", agent, func(c *gin.Context) {
+		teamController.GetTeam(c.Request.Context(), c)
+	})
+
+	router.PUT("/teams/:id", admin, func(c *gin.Context) {
+		teamController.UpdateTeam(c.Request.Context(), c)
+	})
+
+	router.DELETE("/teams/:id", admin, func(c *gin.Context) {
+		teamController.DeleteTeam(c.Request.Context(), c)
+	})
+
+	router.POST("/queues", admin, func(c *gin.Context) {
+		teamController.CreateQueue(c.Request.Context(), c)
+	})
+
+	router.GET("/queues", agent, func(c *gin.Context) {
+		teamController.ListQueues(c.Request.Context(), c)
+	})
+
+	router.GET("/queues/:id", agent, func(c *gin.Context) {
+		teamController.GetQueue(c.Request.Context(), c)
+	})
+
+	router.PUT("/queues/:id", admin, func(c *gin.Context) {
+		teamController.UpdateQueue(c.Request.Context(), c)
+	})
+
+	router.DELETE("/queues/:id", admin, func(c *gin.Context) {
+		teamController.DeleteQueue(c.Request.Context(), c)
+	})
+
+	router.GET("/queues/:id/tickets", agent, func(c *gin.Context) {
+		teamController.ListQueueTickets(c.Request.Context(), c)
+	})
+
+	router.NoRoute(func(c *gin.Context) {
+		slog.InfoContext(c.Request.Context(), "No route found", "method", c.Request.Method, "path", c.Request.URL.Path)
+		controllers.AbortWithProblem(c, http.StatusNotFound, fmt.Errorf("%w - %s %s", ErrRouteNotFound, c.Request.Method, c.Request.URL.Path))
+	})
+
+	return router
+}
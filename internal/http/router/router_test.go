@@ -0,0 +1,617 @@
+package router
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/auth"
+	types "example.com/ticket-system/internal/http"
+	"example.com/ticket-system/internal/imports"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/outbox"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/sla"
+	"example.com/ticket-system/internal/webhooks"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test-secret")
+
+func token(t *testing.T, subject string, roles ...string) string {
+	return tenantToken(t, subject, "", roles...)
+}
+
+func tenantToken(t *testing.T, subject string, tenant string, roles ...string) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(map[string]any{
+		"sub":    subject,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"roles":  roles,
+		"tenant": tenant,
+	})
+	mac := hmac.New(sha256.New, testSecret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	router, _ := newTestRouterWithRepos(t)
+	return router
+}
+
+func newTestRouterWithRepos(t *testing.T) (*gin.Engine, repositories.Repositories) {
+	t.Setenv("GIN_MODE", gin.TestMode)
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	repos := repositories.Repositories{Tickets: repo, Comments: repo, History: repo, ImportJobs: repo, Webhooks: repo, Outbox: repo, Assignment: repo, Teams: repo}
+	return New(repos, imports.NewMockSubmitter(t), auth.NewVerifier(auth.WithHMACSecret(testSecret)), sla.Default(), &assignment.Assigner{}), repos
+}
+
+func serve(router *gin.Engine, method string, target string, bearer string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthentication(t *testing.T) {
+	router := newTestRouter(t)
+
+	w := serve(router, http.MethodGet, "/tickets", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+
+	w = serve(router, http.MethodGet, "/tickets", "garbage", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// preflight requests carry no credentials
+	w = serve(router, http.MethodOptions, "/tickets", "", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRoleAuthorization(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	bob := token(t, "bob", "requester")
+	agent := token(t, "andrew", "agent")
+	nobody := token(t, "nobody")
+
+	// the creator comes from the token, not the body
+	w := serve(router, http.MethodPut, "/ticket", alice, `{"description": "printer broken", "createdBy": "mallory"}`)
+	require.Equal(t, 200, w.Code)
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = serve(router, http.MethodGet, "/ticket/"+created.Id, alice, "")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"CreatedBy":"alice"`)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		bearer         string
+		body           string
+		expectedStatus int
+	}{
+		{"no role", http.MethodPut, "/ticket", nobody, `{"description": "x"}`, http.StatusForbidden},
+		{"requester reads ticket of someone else", http.MethodGet, "/ticket/" + created.Id, bob, "", http.StatusForbidden},
+		{"requester reads missing ticket", http.MethodGet, "/ticket/missing", alice, "", http.StatusNotFound},
+		{"requester comments on ticket of someone else", http.MethodPost, "/ticket/" + created.Id + "/comments", bob, `{"body": "me too"}`, http.StatusForbidden},
+		{"agent reads any ticket", http.MethodGet, "/ticket/" + created.Id, agent, "", 200},
+		{"requester updates status", http.MethodPatch, "/ticket/" + created.Id + "/status", alice, `{"status": "IN_PROGRESS"}`, http.StatusForbidden},
+		{"agent updates status", http.MethodPatch, "/ticket/" + created.Id + "/status", agent, `{"status": "IN_PROGRESS"}`, 200},
+		{"requester assigns", http.MethodPatch, "/ticket/" + created.Id + "/assignto", alice, `{}`, http.StatusForbidden},
+		{"agent assigns to self", http.MethodPatch, "/ticket/" + created.Id + "/assignto", agent, `{}`, 200},
+		{"requester exports", http.MethodGet, "/tickets/export", alice, "", http.StatusForbidden},
+		{"agent runs bulk import", http.MethodPost, "/ticket/bulk-import", agent, "", http.StatusForbidden},
+		{"agent reads import job", http.MethodGet, "/ticket/bulk-import/job-1", agent, "", http.StatusForbidden},
+		{"agent registers webhook", http.MethodPost, "/webhooks", agent, `{}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.method, tt.target, tt.bearer, tt.body)
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+
+	w = serve(router, http.MethodGet, "/ticket/"+created.Id, agent, "")
+	assert.Contains(t, w.Body.String(), `"AssignedTo":"andrew"`)
+}
+
+func TestRequesterListsOwnTickets(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	bob := token(t, "bob", "requester")
+
+	require.Equal(t, 200, serve(router, http.MethodPut, "/ticket", alice, `{"description": "a"}`).Code)
+	require.Equal(t, 200, serve(router, http.MethodPut, "/ticket", bob, `{"description": "b"}`).Code)
+
+	// a creator filter for someone else is ignored
+	w := serve(router, http.MethodGet, "/tickets?creator=bob", alice, "")
+	require.Equal(t, 200, w.Code)
+	var page struct{ Tickets []struct{ CreatedBy string } }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Tickets, 1)
+	assert.Equal(t, "alice", page.Tickets[0].CreatedBy)
+
+	w = serve(router, http.MethodGet, "/tickets", token(t, "andrew", "agent"), "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Tickets, 2)
+}
+
+func TestTenantIsolation(t *testing.T) {
+	router := newTestRouter(t)
+	acme := tenantToken(t, "alice", "acme", "agent")
+	globex := tenantToken(t, "bob", "globex", "admin")
+
+	w := serve(router, http.MethodPut, "/ticket", acme, `{"description": "acme printer"}`)
+	require.Equal(t, 200, w.Code)
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	assert.Equal(t, 200, serve(router, http.MethodGet, "/ticket/"+created.Id, acme, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/ticket/"+created.Id, globex, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/status", globex, `{"status": "IN_PROGRESS"}`).Code)
+
+	// a token bound to a tenant cannot switch with the header
+	req := httptest.NewRequest(http.MethodGet, "/ticket/"+created.Id, nil)
+	req.Header.Set("Authorization", "Bearer "+globex)
+	req.Header.Set("X-Tenant-ID", "acme")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// an operator without a tenant claim can
+	req = httptest.NewRequest(http.MethodGet, "/ticket/"+created.Id, nil)
+	req.Header.Set("Authorization", "Bearer "+token(t, "ops", "admin"))
+	req.Header.Set("X-Tenant-ID", "acme")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestWebhooks(t *testing.T) {
+	router, repos := newTestRouterWithRepos(t)
+	relay := outbox.NewRelay(repos.Outbox, webhooks.NewNotifier(repos.Webhooks, webhooks.WithRetries(1, 0, 0)))
+	admin := tenantToken(t, "root", "acme", "admin")
+	alice := tenantToken(t, "alice", "acme", "requester")
+
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan delivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{header: r.Header, body: body}
+	}))
+	defer receiver.Close()
+
+	w := serve(router, http.MethodPost, "/webhooks", admin, `{"url": "ftp://example.com", "secret": "0123456789abcdef", "events": ["ticket.created"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w = serve(router, http.MethodPost, "/webhooks", admin, `{"url": "`+receiver.URL+`", "secret": "0123456789abcdef", "events": ["ticket.deleted"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = serve(router, http.MethodPost, "/webhooks", admin, `{"url": "`+receiver.URL+`", "secret": "0123456789abcdef", "events": ["ticket.created"]}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "0123456789abcdef")
+
+	// other tenants do not see the subscription nor trigger it
+	w = serve(router, http.MethodGet, "/webhooks", tenantToken(t, "bob", "globex", "admin"), "")
+	assert.JSONEq(t, `{"webhooks": []}`, w.Body.String())
+	require.Equal(t, 200, serve(router, http.MethodPut, "/ticket", tenantToken(t, "bob", "globex", "requester"), `{"description": "globex printer"}`).Code)
+
+	require.Equal(t, 200, serve(router, http.MethodPut, "/ticket", alice, `{"description": "acme printer"}`).Code)
+	require.NoError(t, relay.Drain(context.Background()))
+	select {
+	case got := <-deliveries:
+		assert.Equal(t, "ticket.created", got.header.Get(webhooks.HeaderEventType))
+		timestamp, err := strconv.ParseInt(got.header.Get(webhooks.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, webhooks.Sign("0123456789abcdef", timestamp, got.body), got.header.Get(webhooks.HeaderSignature))
+
+		var event struct {
+			Type   string
+			Tenant string
+			Actor  string
+			Data   struct{ Ticket struct{ Description string } }
+		}
+		require.NoError(t, json.Unmarshal(got.body, &event))
+		assert.Equal(t, "acme", event.Tenant)
+		assert.Equal(t, "alice", event.Actor)
+		assert.Equal(t, "acme printer", event.Data.Ticket.Description)
+	default:
+		t.Fatal("webhook not delivered")
+	}
+	select {
+	case <-deliveries:
+		t.Fatal("event of another tenant delivered")
+	default:
+	}
+}
+
+func TestSLA(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	agent := token(t, "andrew", "agent")
+
+	w := serve(router, http.MethodPut, "/ticket", alice, `{"description": "site is down", "priority": "URGENT"}`)
+	require.Equal(t, 200, w.Code)
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.Equal(t, 200, serve(router, http.MethodPut, "/ticket", alice, `{"description": "new mouse"}`).Code)
+
+	type details struct {
+		SLA types.TicketSLAResponse `json:"sla"`
+	}
+	getSLA := func() types.TicketSLAResponse {
+		w := serve(router, http.MethodGet, "/ticket/"+created.Id, agent, "")
+		require.Equal(t, 200, w.Code)
+		var body details
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.SLA
+	}
+
+	state := getSLA()
+	assert.Equal(t, "urgent", state.Policy)
+	assert.False(t, state.Paused)
+	assert.False(t, state.FirstResponse.Breached)
+	assert.InDelta(t, 30*60, state.FirstResponse.RemainingSeconds, 5)
+	assert.InDelta(t, 4*60*60, state.Resolution.RemainingSeconds, 5)
+
+	// only the urgent ticket is due within the hour
+	listAtRisk := func(within string) []types.AtRiskTicket {
+		w := serve(router, http.MethodGet, "/tickets/at-risk?within="+within, agent, "")
+		require.Equal(t, 200, w.Code)
+		var body types.AtRiskTicketsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Tickets
+	}
+	atRisk := listAtRisk("1h")
+	require.Len(t, atRisk, 1)
+	assert.Equal(t, created.Id, atRisk[0].Ticket.TicketID)
+	assert.Empty(t, listAtRisk("10m"))
+	assert.Len(t, listAtRisk("100h"), 2)
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodGet, "/tickets/at-risk", alice, "").Code)
+
+	// the requester's own comment is not a response
+	require.Equal(t, 200, serve(router, http.MethodPost, "/ticket/"+created.Id+"/comments", alice, `{"body": "still down"}`).Code)
+	assert.Empty(t, getSLA().FirstResponse.MetAt)
+	require.Equal(t, 200, serve(router, http.MethodPost, "/ticket/"+created.Id+"/comments", agent, `{"body": "looking"}`).Code)
+	state = getSLA()
+	assert.NotEmpty(t, state.FirstResponse.MetAt)
+	assert.False(t, state.FirstResponse.Breached)
+
+	// waiting on the customer pauses the clock, the ticket is no longer at risk
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/status", agent, `{"status": "PENDING_CUSTOMER"}`).Code)
+	assert.True(t, getSLA().Paused)
+	assert.Empty(t, listAtRisk("5h"))
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/status", agent, `{"status": "IN_PROGRESS"}`).Code)
+	assert.False(t, getSLA().Paused)
+	assert.Len(t, listAtRisk("5h"), 1)
+}
+
+func TestTeamsAndQueues(t *testing.T) {
+	router := newTestRouter(t)
+	admin := token(t, "root", "admin")
+	alice := token(t, "alice", "requester")
+	maria := token(t, "maria", "agent")
+	sofia := token(t, "sofia", "agent")
+	andrew := token(t, "andrew", "agent")
+
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodPost, "/teams", maria, `{"name": "support"}`).Code)
+	w := serve(router, http.MethodPost, "/teams", admin, `{"name": "support", "members": ["maria", "sofia", "maria"]}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var team struct{ Team models.Team }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &team))
+	assert.Equal(t, []string{"maria", "sofia"}, team.Team.Members)
+
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodPost, "/queues", admin, `{"name": "billing", "teamId": "missing"}`).Code)
+	w = serve(router, http.MethodPost, "/queues", admin, `{"name": "billing", "teamId": "`+team.Team.TeamID+`"}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var queue struct{ Queue models.Queue }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &queue))
+	queueID := queue.Queue.QueueID
+
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPut, "/ticket", alice, `{"description": "refund", "queue": "missing"}`).Code)
+	w = serve(router, http.MethodPut, "/ticket", alice, `{"description": "refund", "queue": "`+queueID+`"}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	listQueue := func(query string) []models.Ticket {
+		w := serve(router, http.MethodGet, "/queues/"+queueID+"/tickets"+query, maria, "")
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var body struct{ Tickets []models.Ticket }
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Tickets
+	}
+	assert.Len(t, listQueue("?unclaimed=true"), 1)
+
+	// only members of the queue's team take its tickets
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPost, "/ticket/"+created.Id+"/claim", andrew, "").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/assignto", maria, `{"assignee": "andrew"}`).Code)
+	require.Equal(t, 200, serve(router, http.MethodPost, "/ticket/"+created.Id+"/claim", maria, "").Code)
+	assert.Equal(t, http.StatusConflict, serve(router, http.MethodPost, "/ticket/"+created.Id+"/claim", sofia, "").Code)
+	assert.Empty(t, listQueue("?unclaimed=true"))
+	claimed := listQueue("")
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "maria", claimed[0].AssignedTo)
+
+	assert.Equal(t, http.StatusConflict, serve(router, http.MethodDelete, "/teams/"+team.Team.TeamID, admin, "").Code)
+	assert.Equal(t, http.StatusConflict, serve(router, http.MethodDelete, "/queues/"+queueID, admin, "").Code)
+
+	// maria leaves the team, moving the ticket to the queue again unassigns it
+	require.Equal(t, 200, serve(router, http.MethodPut, "/teams/"+team.Team.TeamID, admin, `{"name": "support", "members": ["sofia"]}`).Code)
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/queue", sofia, `{"queue": "`+queueID+`"}`).Code)
+	assert.Len(t, listQueue("?unclaimed=true"), 1)
+
+	// out of the queue the ticket can go to anyone
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/queue", sofia, `{"queue": ""}`).Code)
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/assignto", sofia, `{"assignee": "andrew"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPost, "/ticket/"+created.Id+"/claim", sofia, "").Code)
+
+	require.Equal(t, 200, serve(router, http.MethodDelete, "/queues/"+queueID, admin, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/queues/"+queueID, maria, "").Code)
+	require.Equal(t, 200, serve(router, http.MethodDelete, "/teams/"+team.Team.TeamID, admin, "").Code)
+	w = serve(router, http.MethodGet, "/teams", maria, "")
+	assert.JSONEq(t, `{"teams": []}`, w.Body.String())
+}
+
+func TestTriage(t *testing.T) {
+	router, repos := newTestRouterWithRepos(t)
+	alice := token(t, "alice", "requester")
+	maria := token(t, "maria", "agent")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPut, "/ticket", alice, `{"description": "outage", "severity": "SEV1"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPut, "/ticket", alice, `{"description": "outage", "tags": ["a;b"]}`).Code)
+	w := serve(router, http.MethodPut, "/ticket", alice, `{"description": "outage", "priority": "HIGH", "severity": "CRITICAL", "category": "INCIDENT", "tags": ["vpn", " vpn", ""]}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	listOpen := func(priority string) []models.Ticket {
+		w := serve(router, http.MethodGet, "/tickets/open?priority="+priority, maria, "")
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var body struct{ Tickets []models.Ticket }
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Tickets
+	}
+	assert.Len(t, listOpen("HIGH"), 1)
+	assert.Empty(t, listOpen("URGENT"))
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodGet, "/tickets/open?priority=P1", maria, "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodGet, "/tickets/open?priority=HIGH", alice, "").Code)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/priority", maria, `{"priority": "P1"}`).Code)
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/priority", maria, `{"priority": "URGENT"}`).Code)
+	assert.Empty(t, listOpen("HIGH"))
+	assert.Len(t, listOpen("URGENT"), 1)
+
+	w = serve(router, http.MethodPost, "/ticket/"+created.Id+"/tags", maria, `{"tags": ["network", "vpn"]}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"tags": ["vpn", "network"]}`, w.Body.String())
+	w = serve(router, http.MethodDelete, "/ticket/"+created.Id+"/tags/vpn", maria, "")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"tags": ["network"]}`, w.Body.String())
+
+	// the changes are part of the ticket history
+	page, err := repos.History.GetTicketHistory(context.Background(), created.Id, models.PageRequest{})
+	require.NoError(t, err)
+	var actions []string
+	for _, entry := range page.Entries {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{models.ActionCreated, models.ActionPriorityChanged, models.ActionTagged, models.ActionTagged}, actions)
+
+	// closed tickets leave the open listing
+	require.Equal(t, 200, serve(router, http.MethodPatch, "/ticket/"+created.Id+"/status", maria, `{"status": "CLOSED"}`).Code)
+	assert.Empty(t, listOpen("URGENT"))
+}
+
+func TestPatchTicket(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	maria := token(t, "maria", "agent")
+
+	w := serve(router, http.MethodPut, "/ticket", alice, `{"description": "printer", "tags": ["hardware"]}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	target := "/ticket/" + created.Id
+
+	patch := func(body string, contentType string, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+maria)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodPatch, target, alice, `{"Description": "scanner"}`).Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, patch(`[{"op": "remove", "path": "/Tags"}]`, "application/json-patch+json", "").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, patch(`{"Owner": "maria"}`, "application/merge-patch+json", "").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, patch(`{"CreatedAt": "yesterday"}`, "application/merge-patch+json", "").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, patch(`{"Status": "RESOLVED"}`, "application/merge-patch+json", "").Code)
+	assert.Equal(t, http.StatusPreconditionFailed, patch(`{"Description": "scanner"}`, "application/merge-patch+json", `"7"`).Code)
+
+	w = patch(`{"Description": "scanner", "Priority": "HIGH", "Tags": null, "AssignedTo": "maria", "Status": "RESOLVED"}`, "application/merge-patch+json", `"1"`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	var body struct{ Ticket models.Ticket }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, created.Id, body.Ticket.TicketID)
+	assert.Equal(t, "scanner", body.Ticket.Description)
+	assert.Equal(t, models.PriorityHigh, body.Ticket.Priority)
+	assert.Equal(t, models.StatusResolved, body.Ticket.Status)
+	assert.Empty(t, body.Ticket.Tags)
+	assert.Equal(t, "alice", body.Ticket.CreatedBy)
+
+	w = serve(router, http.MethodGet, target+"/history", maria, "")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), models.ActionStatusChanged)
+}
+
+func TestDeleteTicket(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	maria := token(t, "maria", "agent")
+	root := token(t, "root", "admin")
+
+	w := serve(router, http.MethodPut, "/ticket", alice, `{"description": "printer", "assignedTo": "maria"}`)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var created struct{ Id string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	target := "/ticket/" + created.Id
+	require.Equal(t, 200, serve(router, http.MethodPost, target+"/comments", alice, `{"body": "still broken"}`).Code)
+
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodDelete, target, alice, "").Code)
+	assert.Equal(t, http.StatusConflict, serve(router, http.MethodPost, target+"/restore", maria, "").Code)
+	w = serve(router, http.MethodDelete, target, maria, "")
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, target, maria, "").Code)
+	w = serve(router, http.MethodGet, "/ticket/assigned?username=maria", maria, "")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), created.Id)
+	w = serve(router, http.MethodGet, "/tickets", maria, "")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), created.Id)
+
+	w = serve(router, http.MethodPost, target+"/restore", maria, "")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var body struct{ Ticket models.Ticket }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.False(t, body.Ticket.IsDeleted())
+	assert.Equal(t, 200, serve(router, http.MethodGet, target, maria, "").Code)
+	w = serve(router, http.MethodGet, target+"/history", maria, "")
+	assert.Contains(t, w.Body.String(), models.ActionDeleted)
+	assert.Contains(t, w.Body.String(), models.ActionRestored)
+
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodDelete, target+"/purge", maria, "").Code)
+	require.Equal(t, 200, serve(router, http.MethodDelete, target+"/purge", root, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodPost, target+"/restore", maria, "").Code)
+	w = serve(router, http.MethodGet, target+"/comments", maria, "")
+	assert.NotContains(t, w.Body.String(), "still broken")
+	w = serve(router, http.MethodGet, target+"/history", maria, "")
+	assert.NotContains(t, w.Body.String(), models.ActionCreated)
+}
+
+func TestProblems(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	maria := token(t, "maria", "agent")
+
+	w := serve(router, http.MethodGet, "/tickets", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type": "about:blank", "title": "Unauthorized", "status": 401, "code": "unauthorized", "detail": "unauthorized"}`, w.Body.String())
+
+	w = serve(router, http.MethodGet, "/ticket/missing", maria, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var problem types.ProblemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "ticket_not_found", problem.Code)
+
+	w = serve(router, http.MethodGet, "/nowhere", maria, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404, "code": "not_found", "detail": "route not found - GET /nowhere"}`, w.Body.String())
+
+	w = serve(router, http.MethodPut, "/ticket", alice, `{"description": "outage", "priority": "P1", "tags": ["a;b"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "invalid_request", problem.Code)
+	assert.ElementsMatch(t, []types.FieldProblem{{Field: "priority", Code: "oneof"}, {Field: "tags[0]", Code: "excludesall"}}, problem.Errors)
+
+	w = serve(router, http.MethodPut, "/ticket", alice, `{"description": `)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "bad_request", problem.Code)
+}
+
+func TestRequestValidation(t *testing.T) {
+	router := newTestRouter(t)
+	alice := token(t, "alice", "requester")
+	maria := token(t, "maria", "agent")
+
+	// the subject becomes the creator of the tickets
+	assert.Equal(t, http.StatusUnauthorized, serve(router, http.MethodGet, "/tickets", token(t, "alice#admin", "agent"), "").Code)
+
+	w := serve(router, http.MethodPut, "/ticket", alice, `{"description": ""}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var problem types.ProblemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, []types.FieldProblem{{Field: "description", Code: "required"}}, problem.Errors)
+
+	w = serve(router, http.MethodPut, "/ticket", alice, `{"description": "printer"}`)
+	require.Equal(t, 200, w.Code)
+	var created types.CreateTicketResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected types.FieldProblem
+	}{
+		{"no status", http.MethodPatch, "/ticket/" + created.Id + "/status", `{}`, types.FieldProblem{Field: "status", Code: "required"}},
+		{"assignee with spaces", http.MethodPatch, "/ticket/" + created.Id + "/assignto", `{"assignee": "maria lopez"}`, types.FieldProblem{Field: "assignee", Code: "username"}},
+		{"no username", http.MethodGet, "/ticket/assigned", "", types.FieldProblem{Field: "username", Code: "required"}},
+		{"filtered creator", http.MethodGet, "/tickets?creator=a%23b", "", types.FieldProblem{Field: "creator", Code: "username"}},
+		{"long comment", http.MethodPost, "/ticket/" + created.Id + "/comments", `{"body": "` + strings.Repeat("a", 10001) + `"}`, types.FieldProblem{Field: "body", Code: "max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.method, tt.target, maria, tt.body)
+			require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+			var problem types.ProblemResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, []types.FieldProblem{tt.expected}, problem.Errors)
+		})
+	}
+
+	// statuses are left to the workflow, which may define its own
+	w = serve(router, http.MethodPatch, "/ticket/"+created.Id+"/status", maria, `{"status": "DONE"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "invalid_status", problem.Code)
+	assert.Equal(t, 200, serve(router, http.MethodGet, "/tickets?status=DONE", maria, "").Code)
+
+	// createdAt is RFC 3339
+	w = serve(router, http.MethodGet, "/ticket/"+created.Id, maria, "")
+	require.Equal(t, 200, w.Code)
+	var details struct {
+		Ticket struct{ CreatedAt string }
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	_, err := time.Parse(time.RFC3339, details.Ticket.CreatedAt)
+	assert.NoError(t, err)
+}
@@ -1,36 +1,306 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
 
 	"example.com/ticket-system/internal/models"
+	"github.com/google/uuid"
 )
 
 type GetTicketsAssignedToRequest struct {
-	UserName string `form:"username"`
+	UserName string `form:"username" binding:"required,username"`
+}
+
+// Query parameters selecting tickets, shared by listing and export
+type TicketFilterRequest struct {
+	Status      string    `form:"status"`
+	Assignee    string    `form:"assignee" binding:"omitempty,username"`
+	Creator     string    `form:"creator" binding:"omitempty,username"`
+	CreatedFrom time.Time `form:"createdFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time `form:"createdTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	Order       string    `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+func (fr *TicketFilterRequest) ToFilter() models.TicketFilter {
+	return models.TicketFilter{
+		Status:      models.TicketStatus(fr.Status),
+		AssignedTo:  fr.Assignee,
+		CreatedBy:   fr.Creator,
+		CreatedFrom: fr.CreatedFrom,
+		CreatedTo:   fr.CreatedTo,
+		Page:        models.PageRequest{Descending: fr.Order == "desc"},
+	}
+}
+
+type ListTicketsRequest struct {
+	PageRequest
+	TicketFilterRequest
+}
+
+func (lr *ListTicketsRequest) ToFilter() models.TicketFilter {
+	filter := lr.TicketFilterRequest.ToFilter()
+	filter.Page.Limit = lr.Limit
+	filter.Page.Cursor = lr.Cursor
+	return filter
+}
+
+type ExportTicketsRequest struct {
+	TicketFilterRequest
+	// overrides the Accept header
+	Format string `form:"format" binding:"omitempty,oneof=csv json ndjson"`
+}
+
+type TicketPageResponse struct {
+	Tickets    []models.Ticket `json:"tickets"`
+	NextCursor string          `json:"nextCursor,omitempty"`
 }
 
 type CreateTicketRequest struct {
-	Description string `json:"description"`
-	CreatedBy   string `json:"createdBy"`
+	Description string                `json:"description" binding:"required,max=4000"`
+	Priority    models.TicketPriority `json:"priority" binding:"omitempty,oneof=LOW MEDIUM HIGH URGENT"`
+	Severity    models.TicketSeverity `json:"severity" binding:"omitempty,oneof=CRITICAL MAJOR MINOR TRIVIAL"`
+	Category    models.TicketCategory `json:"category" binding:"omitempty,oneof=INCIDENT SERVICE_REQUEST PROBLEM QUESTION"`
+	Tags        []string              `json:"tags" binding:"dive,max=64,excludesall=;"`
+	// id of the queue the ticket waits in until a member claims it
+	Queue string `json:"queue" binding:"max=64"`
 }
 
 type CreateTicketResponse struct {
 	Id string `json:"id"`
 }
 
-// Generates a ticket that has just created
-func (tr *CreateTicketRequest) ToTicket() *models.Ticket {
+// Generates a ticket that has just created, createdBy is the caller
+func (tr *CreateTicketRequest) ToTicket(createdBy string) *models.Ticket {
 	return &models.Ticket{
 		Description: tr.Description,
-		CreatedBy:   tr.CreatedBy,
-		CreatedAt:   time.Now().UTC().String(),
+		Priority:    tr.Priority,
+		Severity:    tr.Severity,
+		Category:    tr.Category,
+		Tags:        models.NormalizeTags(tr.Tags),
+		CreatedBy:   createdBy,
+		CreatedAt:   models.NewTimestamp(time.Now()),
 		Status:      models.StatusOpen,
 		AssignedTo:  "None",
+		Queue:       tr.Queue,
+		Version:     1,
+	}
+}
+
+type SLATargetResponse struct {
+	Due string `json:"due"`
+	// when the target was met
+	MetAt string `json:"metAt,omitempty"`
+	// negative once the deadline passed, frozen while the SLA is paused
+	RemainingSeconds int64 `json:"remainingSeconds"`
+	Breached         bool  `json:"breached"`
+}
+
+type TicketSLAResponse struct {
+	Policy        string            `json:"policy"`
+	Paused        bool              `json:"paused"`
+	FirstResponse SLATargetResponse `json:"firstResponse"`
+	Resolution    SLATargetResponse `json:"resolution"`
+}
+
+// The state of the SLA deadlines at now
+func NewTicketSLAResponse(sla *models.TicketSLA, now time.Time) *TicketSLAResponse {
+	return &TicketSLAResponse{
+		Policy:        sla.Policy,
+		Paused:        sla.Paused(),
+		FirstResponse: newSLATargetResponse(sla.FirstResponse(now)),
+		Resolution:    newSLATargetResponse(sla.Resolution(now)),
+	}
+}
+
+func newSLATargetResponse(target models.SLATarget) SLATargetResponse {
+	return SLATargetResponse{
+		Due:              target.Due,
+		MetAt:            target.MetAt,
+		RemainingSeconds: int64(target.Remaining / time.Second),
+		Breached:         target.Breached,
 	}
 }
 
+type ListAtRiskTicketsRequest struct {
+	// tickets with a deadline within this duration, e.g. 30m. Breached
+	// tickets are always included.
+	Within time.Duration `form:"within" binding:"min=0"`
+}
+
+type AtRiskTicket struct {
+	Ticket models.Ticket      `json:"ticket"`
+	SLA    *TicketSLAResponse `json:"sla"`
+}
+
+type AtRiskTicketsResponse struct {
+	Tickets []AtRiskTicket `json:"tickets"`
+}
+
 type AssignToRequest struct {
 	TicketID string
-	Assignee string `json:"assignee" binding:"required"`
+	// defaults to the caller
+	Assignee string `json:"assignee" binding:"omitempty,username"`
+}
+
+type UpdatePriorityRequest struct {
+	Priority models.TicketPriority `json:"priority" binding:"required,oneof=LOW MEDIUM HIGH URGENT"`
+}
+
+// Tags added to or removed from a ticket
+type TagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,dive,required,max=64,excludesall=;"`
+}
+
+type ListOpenTicketsRequest struct {
+	Priority models.TicketPriority `form:"priority" binding:"required,oneof=LOW MEDIUM HIGH URGENT"`
+}
+
+type GetTicketDetailsRequest struct {
+	// number of most recent comments to embed, 0 omits them
+	Comments int `form:"comments" binding:"min=0,max=100"`
+}
+
+type AddCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// author is the caller
+func (cr *AddCommentRequest) ToComment(ticketID string, author string) *models.Comment {
+	return &models.Comment{
+		TicketID: ticketID,
+		Author:   author,
+		Body:     cr.Body,
+	}
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// Query parameters of paginated list endpoints
+type PageRequest struct {
+	Limit  int    `form:"limit" binding:"min=0,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+func (pr *PageRequest) ToPageRequest() models.PageRequest {
+	return models.PageRequest{
+		Limit:  pr.Limit,
+		Cursor: pr.Cursor,
+	}
+}
+
+type CommentPageResponse struct {
+	Comments   []models.Comment `json:"comments"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+type HistoryPageResponse struct {
+	History    []models.HistoryEntry `json:"history"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+type SubmitImportRequest struct {
+	DryRun bool `form:"dryRun"`
+	// JSON object renaming columns, e.g. {"id": "Ticket #"}. Read from the
+	// multipart form next to the file.
+	Mapping string `form:"-"`
+}
+
+func (r *SubmitImportRequest) ColumnMapping() (models.ColumnMapping, error) {
+	if r.Mapping == "" {
+		return nil, nil
+	}
+	var mapping models.ColumnMapping
+	if err := json.Unmarshal([]byte(r.Mapping), &mapping); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+type SubmitImportResponse struct {
+	JobID  string                 `json:"jobId"`
+	Status models.ImportJobStatus `json:"status"`
+}
+
+type ImportJobResponse struct {
+	models.ImportJob
+	// path of the CSV report listing rejected and failed lines
+	ErrorReport string `json:"errorReport,omitempty"`
+}
+
+type RegisterWebhookRequest struct {
+	URL string `json:"url" binding:"required,url"`
+	// shared with the receiver to verify the signatures
+	Secret string             `json:"secret" binding:"required,min=16"`
+	Events []models.EventType `json:"events" binding:"required,min=1"`
+}
+
+// registeredBy is the caller
+func (r *RegisterWebhookRequest) ToSubscription(registeredBy string) *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		WebhookID:    uuid.NewString(),
+		URL:          r.URL,
+		Secret:       r.Secret,
+		Events:       r.Events,
+		RegisteredBy: registeredBy,
+		RegisteredAt: models.FormatSortableTime(time.Now()),
+	}
+}
+
+type WebhookDeadLetterPageResponse struct {
+	DeadLetters []models.WebhookDeadLetter `json:"deadLetters"`
+	NextCursor  string                     `json:"nextCursor,omitempty"`
+}
+
+type TeamRequest struct {
+	Name    string   `json:"name" binding:"required,max=128"`
+	Members []string `json:"members" binding:"dive,required,username"`
+}
+
+// Members are kept once, in the order given
+func (r *TeamRequest) ToTeam(teamID string) *models.Team {
+	team := &models.Team{TeamID: teamID, Name: r.Name, Members: []string{}}
+	for _, member := range r.Members {
+		if !team.HasMember(member) {
+			team.Members = append(team.Members, member)
+		}
+	}
+	return team
+}
+
+type QueueRequest struct {
+	Name   string `json:"name" binding:"required,max=128"`
+	TeamID string `json:"teamId" binding:"required,max=64"`
+}
+
+func (r *QueueRequest) ToQueue(queueID string) *models.Queue {
+	return &models.Queue{QueueID: queueID, Name: r.Name, TeamID: r.TeamID}
+}
+
+type ListQueueTicketsRequest struct {
+	// only the tickets no member claimed yet
+	Unclaimed bool `form:"unclaimed"`
+}
+
+type MoveToQueueRequest struct {
+	// empty takes the ticket out of its queue
+	Queue string `json:"queue" binding:"max=64"`
+}
+
+// Failed requests are answered with an RFC 7807 problem, extended with a
+// code clients can rely on and the fields at fault
+type ProblemResponse struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail,omitempty"`
+	Code   string         `json:"code"`
+	Errors []FieldProblem `json:"errors,omitempty"`
+}
+
+type FieldProblem struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
 }
@@ -0,0 +1,87 @@
+package identity
+
+import (
+	"context"
+	"regexp"
+)
+
+// Actor recorded for changes made outside of an HTTP request
+const SystemActor = "system"
+
+// Tenant of callers that do not belong to an organization. Its tickets keep
+// the keys used before tenants were introduced.
+const DefaultTenant = "default"
+
+// tenant ids become part of partition keys, they may not contain '#'
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// usernames end up in index keys and CSV exports, e.g. maria, agent-1,
+// maria@example.com or auth0|5f7c
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._@|+-]{1,128}$`)
+
+// Roles granted by the token. Each role includes the ones before it: agents
+// can do what requesters do and admins can do everything.
+const (
+	RoleRequester = "requester"
+	RoleAgent     = "agent"
+	RoleAdmin     = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleRequester: 1,
+	RoleAgent:     2,
+	RoleAdmin:     3,
+}
+
+// Identity is the caller of the current request
+type Identity struct {
+	Subject string
+	Roles   []string
+	// organization whose tickets the caller works on, empty for the default
+	Tenant string
+}
+
+// Reports whether the identity has role or a role including it
+func (id Identity) HasRole(role string) bool {
+	for _, granted := range id.Roles {
+		if roleRanks[granted] >= roleRanks[role] && roleRanks[granted] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
+
+// Returns the subject of the caller, or SystemActor when there is none
+func Actor(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok && id.Subject != "" {
+		return id.Subject
+	}
+	return SystemActor
+}
+
+// Returns the tenant of the caller, or DefaultTenant when there is none
+func Tenant(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok && id.Tenant != "" {
+		return id.Tenant
+	}
+	return DefaultTenant
+}
+
+func ValidTenant(tenant string) bool {
+	return tenantPattern.MatchString(tenant)
+}
+
+func ValidUsername(username string) bool {
+	return usernamePattern.MatchString(username)
+}
@@ -0,0 +1,78 @@
+package imports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
+)
+
+var ErrInvalidHeader = errors.New("invalid import header")
+
+// ParseCSV reads the bulk import file. The first line is the header, its
+// columns are matched by name using mapping. Lines that pass validation,
+// with a status of the workflow, are returned as entries, the others as
+// rejected results. lines is the number of records read after the header,
+// the line numbers reported are those of the file, where a quoted field
+// may span several lines.
+func ParseCSV(r io.Reader, mapping models.ColumnMapping, wf *workflow.Workflow) (entries []models.ImportEntry, rejected []models.ImportLineResult, lines int, err error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ','
+	reader.TrimLeadingSpace = true
+	// rows shorter than the header are reported by LoadFromRecord
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, 0, fmt.Errorf("%w - empty file", ErrInvalidHeader)
+	}
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("%w - %w", ErrInvalidHeader, err)
+	}
+	columns, err := mapping.Resolve(header)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("%w - %w", ErrInvalidHeader, err)
+	}
+
+	lineNumber, _ := reader.FieldPos(0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		lines++
+		var parseErr *csv.ParseError
+		switch {
+		case err == nil:
+			lineNumber, _ = reader.FieldPos(0)
+		case errors.As(err, &parseErr):
+			lineNumber = parseErr.StartLine
+		default:
+			lineNumber++
+		}
+		if err != nil {
+			rejected = append(rejected, models.ImportLineResult{Line: lineNumber, Result: models.ImportRejected, Error: err.Error()})
+			// parse errors only affect their own line, anything else means
+			// the rest of the upload cannot be read
+			if parseErr == nil {
+				break
+			}
+			continue
+		}
+
+		ticketRecord := models.BulkImportRecord{}
+		err = ticketRecord.LoadFromRecord(record, columns)
+		if err == nil {
+			err = ticketRecord.Validate(wf)
+		}
+		if err != nil {
+			rejected = append(rejected, models.ImportLineResult{Line: lineNumber, TicketID: ticketRecord.ID, Result: models.ImportRejected, Error: err.Error()})
+			continue
+		}
+		entries = append(entries, models.ImportEntry{Line: lineNumber, Ticket: ticketRecord.ToTicket()})
+	}
+	return entries, rejected, lines, nil
+}
@@ -0,0 +1,25 @@
+package imports
+
+import (
+	"context"
+	"os"
+	"runtime"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+)
+
+// NewServiceFromEnv dispatches chunks to the SQS queue named by
+// IMPORT_QUEUE_URL, or to a local worker pool when it is not set. The
+// returned pool is nil when SQS is used, otherwise it is already started
+// and the caller stops it on shutdown.
+func NewServiceFromEnv(ctx context.Context, repos repositories.Repositories, assigner *assignment.Assigner, wf *workflow.Workflow) (*Service, *WorkerPool) {
+	if queueURL := os.Getenv("IMPORT_QUEUE_URL"); queueURL != "" {
+		return NewService(repos.ImportJobs, repos.Tickets, NewSQSDispatcher(ctx, queueURL), assigner, wf), nil
+	}
+	pool := NewWorkerPool(runtime.NumCPU(), 100)
+	service := NewService(repos.ImportJobs, repos.Tickets, pool, assigner, wf)
+	pool.Start(service)
+	return service, pool
+}
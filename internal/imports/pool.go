@@ -0,0 +1,73 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+var ErrPoolStopped = errors.New("import worker pool stopped")
+
+// WorkerPool processes chunks on goroutines of the current process. It is
+// used when the API runs outside AWS.
+type WorkerPool struct {
+	workers int
+	tasks   chan Task
+	wg      sync.WaitGroup
+
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func NewWorkerPool(workers int, queueSize int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &WorkerPool{
+		workers: workers,
+		tasks:   make(chan Task, queueSize),
+	}
+}
+
+// Start runs the workers until Stop is called
+func (p *WorkerPool) Start(processor Processor) {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for task := range p.tasks {
+				ctx := context.Background()
+				if err := processor.ProcessChunk(ctx, task); err != nil {
+					slog.ErrorContext(ctx, "Failed to process import chunk", "jobId", task.JobID, "chunk", task.Chunk, "error", err)
+				}
+			}
+		}()
+	}
+}
+
+// Dispatch queues the task, it blocks while the queue is full
+func (p *WorkerPool) Dispatch(ctx context.Context, task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return ErrPoolStopped
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop waits for the queued chunks to be processed
+func (p *WorkerPool) Stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
@@ -0,0 +1,183 @@
+// Package imports runs bulk CSV imports as background jobs. The upload is
+// validated and split into chunks that a Dispatcher hands to workers, each
+// chunk is written and counted once.
+package imports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/google/uuid"
+)
+
+// Entries per chunk, a chunk is processed by a single worker
+const ChunkSize = 50
+
+var ErrDispatchingChunk = errors.New("could not dispatch import chunk")
+
+// Task identifies one chunk of an import job
+type Task struct {
+	JobID  string `json:"jobId"`
+	Chunk  int    `json:"chunk"`
+	Tenant string `json:"tenant"`
+}
+
+// Dispatcher hands chunks to the workers
+type Dispatcher interface {
+	Dispatch(ctx context.Context, task Task) error
+}
+
+// Processor writes the tickets of a chunk
+type Processor interface {
+	ProcessChunk(ctx context.Context, task Task) error
+}
+
+// Submitter starts import jobs
+type Submitter interface {
+	Submit(ctx context.Context, r io.Reader, mapping models.ColumnMapping) (*models.ImportJob, error)
+	// validates every line without writing anything
+	DryRun(ctx context.Context, r io.Reader, mapping models.ColumnMapping) (*models.ImportReport, error)
+}
+
+type Service struct {
+	jobs       repositories.ImportJobRepository
+	tickets    repositories.TicketRepository
+	dispatcher Dispatcher
+	assigner   *assignment.Assigner
+	// the statuses of imported tickets must be part of the workflow
+	workflow *workflow.Workflow
+}
+
+// dispatcher may be nil for workers that only process chunks
+func NewService(jobs repositories.ImportJobRepository, tickets repositories.TicketRepository, dispatcher Dispatcher, assigner *assignment.Assigner, wf *workflow.Workflow) *Service {
+	return &Service{
+		jobs:       jobs,
+		tickets:    tickets,
+		dispatcher: dispatcher,
+		assigner:   assigner,
+		workflow:   wf,
+	}
+}
+
+// Submit validates the upload, stores the job and dispatches its chunks.
+// Rejected lines are recorded right away, the tickets are written by the
+// workers.
+func (s *Service) Submit(ctx context.Context, r io.Reader, mapping models.ColumnMapping) (*models.ImportJob, error) {
+	entries, rejected, lines, err := ParseCSV(r, mapping, s.workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		JobID:       uuid.NewString(),
+		Status:      models.ImportJobPending,
+		SubmittedBy: identity.Actor(ctx),
+		SubmittedAt: models.FormatSortableTime(time.Now()),
+		TotalLines:  lines,
+		Processed:   len(rejected),
+		Rejected:    len(rejected),
+	}
+
+	var chunks []models.ImportChunk
+	for i := 0; i < len(entries); i += ChunkSize {
+		end := i + ChunkSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		chunks = append(chunks, models.ImportChunk{
+			JobID:   job.JobID,
+			Index:   len(chunks),
+			Entries: entries[i:end],
+		})
+	}
+	if len(chunks) == 0 {
+		job.Status = models.ImportJobCompleted
+		job.CompletedAt = job.SubmittedAt
+	}
+
+	if err := s.jobs.CreateImportJob(ctx, job, chunks, rejected); err != nil {
+		return nil, err
+	}
+	for _, chunk := range chunks {
+		if err := s.dispatcher.Dispatch(ctx, Task{JobID: job.JobID, Chunk: chunk.Index, Tenant: identity.Tenant(ctx)}); err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrDispatchingChunk, err)
+		}
+	}
+	slog.InfoContext(ctx, "Import job submitted", "jobId", job.JobID, "lines", lines, "chunks", len(chunks))
+	return job, nil
+}
+
+// DryRun reports what an import of the file would do, lines that pass
+// validation are reported as valid
+func (s *Service) DryRun(ctx context.Context, r io.Reader, mapping models.ColumnMapping) (*models.ImportReport, error) {
+	entries, rejected, _, err := ParseCSV(r, mapping, s.workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	results := rejected
+	for _, entry := range entries {
+		results = append(results, models.ImportLineResult{Line: entry.Line, TicketID: entry.Ticket.TicketID, Result: models.ImportValid})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+	report := models.NewImportReport(results)
+	return &report, nil
+}
+
+// ProcessChunk writes the tickets of a chunk and records the outcome on the
+// job. Chunks delivered more than once are only counted the first time.
+func (s *Service) ProcessChunk(ctx context.Context, task Task) error {
+	ctx = identity.WithIdentity(ctx, identity.Identity{Tenant: task.Tenant})
+	job, err := s.jobs.GetImportJob(ctx, task.JobID)
+	if err != nil {
+		return err
+	}
+	chunk, err := s.jobs.GetImportChunk(ctx, task.JobID, task.Chunk)
+	if err != nil {
+		return err
+	}
+	if chunk.Processed {
+		return nil
+	}
+
+	// history entries are attributed to the user who uploaded the file
+	ctx = identity.WithIdentity(ctx, identity.Identity{Subject: job.SubmittedBy, Tenant: task.Tenant})
+
+	tickets := make([]models.Ticket, len(chunk.Entries))
+	assigned := make([]*models.Ticket, len(chunk.Entries))
+	for i, entry := range chunk.Entries {
+		tickets[i] = entry.Ticket
+		assigned[i] = &tickets[i]
+	}
+	s.assigner.Assign(ctx, assigned...)
+	failures, err := s.tickets.BulkImport(ctx, tickets)
+	if err != nil {
+		return err
+	}
+	reasons := make(map[string]string, len(failures))
+	for _, failure := range failures {
+		reasons[failure.TicketID] = failure.Reason
+	}
+
+	results := make([]models.ImportLineResult, len(chunk.Entries))
+	for i, entry := range chunk.Entries {
+		results[i] = models.ImportLineResult{Line: entry.Line, TicketID: entry.Ticket.TicketID, Result: models.ImportCreated}
+		if reason, failed := reasons[entry.Ticket.TicketID]; failed {
+			results[i].Result = models.ImportFailed
+			results[i].Error = reason
+		}
+	}
+	return s.jobs.CompleteImportChunk(ctx, task.JobID, task.Chunk, results)
+}
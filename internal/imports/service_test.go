@@ -0,0 +1,287 @@
+package imports
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/assignment"
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importHeader = "id,description,status,assignedTo,createdBy\n"
+
+func TestParseCSV(t *testing.T) {
+	entries, rejected, lines, err := ParseCSV(strings.NewReader(importHeader+
+		"1234,ticket A description,OPEN,andrew,hugo\n"+
+		"1235,ticket B description,UNKNOWN,david,hugo\n"+
+		"1236,ticket C description,OPEN,david,hugo\n"+
+		"1237,ticket D description,OPEN,david\n"), nil, workflow.Default())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, lines)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].Line)
+	assert.Equal(t, "1234", entries[0].Ticket.TicketID)
+	assert.Equal(t, models.StatusOpen, entries[0].Ticket.Status)
+	assert.Equal(t, "andrew", entries[0].Ticket.AssignedTo)
+	assert.Equal(t, "hugo", entries[0].Ticket.CreatedBy)
+	assert.Equal(t, 4, entries[1].Line)
+	assert.Equal(t, []models.ImportLineResult{
+		{Line: 3, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - status"},
+		{Line: 5, Result: models.ImportRejected, Error: "bulk import - record should have 5 columns"},
+	}, rejected)
+}
+
+func TestParseCSVCustomWorkflow(t *testing.T) {
+	wf, err := workflow.New(workflow.Definition{Statuses: []models.TicketStatus{"TRIAGE", models.StatusOpen}})
+	require.NoError(t, err)
+
+	entries, rejected, _, err := ParseCSV(strings.NewReader(importHeader+
+		"1234,ticket A description,TRIAGE,andrew,hugo\n"+
+		"1235,ticket B description,RESOLVED,david,hugo\n"), nil, wf)
+	require.NoError(t, err)
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.TicketStatus("TRIAGE"), entries[0].Ticket.Status)
+	assert.Equal(t, []models.ImportLineResult{
+		{Line: 3, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - status"},
+	}, rejected)
+}
+
+func TestParseCSVUsers(t *testing.T) {
+	entries, rejected, _, err := ParseCSV(strings.NewReader(importHeader+
+		"1234,ticket A description,OPEN,,hugo\n"+
+		"1235,ticket B description,OPEN,andrew,\n"+
+		"1236,ticket C description,OPEN,andrew,hugo lopez\n"+
+		"1237,ticket D description,OPEN,andrew#admin,hugo\n"), nil, workflow.Default())
+	require.NoError(t, err)
+
+	// without an assignee the ticket is unassigned, like one created by the API
+	require.Len(t, entries, 1)
+	assert.Equal(t, "None", entries[0].Ticket.AssignedTo)
+	assert.Equal(t, []models.ImportLineResult{
+		{Line: 3, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - createdBy"},
+		{Line: 4, TicketID: "1236", Result: models.ImportRejected, Error: "wrong column - createdBy"},
+		{Line: 5, TicketID: "1237", Result: models.ImportRejected, Error: "wrong column - assignedTo"},
+	}, rejected)
+}
+
+func TestParseCSVMultilineField(t *testing.T) {
+	entries, rejected, lines, err := ParseCSV(strings.NewReader(importHeader+
+		"1234,\"printer jammed\nsince monday\n\nthird floor\",OPEN,andrew,hugo\n"+
+		"1235,ticket B description,UNKNOWN,david,hugo\n"+
+		"1236,ticket C description,OPEN,david,hugo\n"), nil, workflow.Default())
+	require.NoError(t, err)
+
+	// the numbers are those of the lines in the file, not of the records
+	assert.Equal(t, 3, lines)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].Line)
+	assert.Equal(t, "printer jammed\nsince monday\n\nthird floor", entries[0].Ticket.Description)
+	assert.Equal(t, 7, entries[1].Line)
+	assert.Equal(t, []models.ImportLineResult{
+		{Line: 6, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - status"},
+	}, rejected)
+}
+
+func TestParseCSVColumns(t *testing.T) {
+	t.Run("optional columns in any order", func(t *testing.T) {
+		entries, rejected, _, err := ParseCSV(strings.NewReader(
+			"Tags,createdBy,ID,Description,status,assignedTo,priority,createdAt,Severity,category\n"+
+				"billing; vip; billing,hugo,1234,ticket A,OPEN,andrew,HIGH,2024-03-01T10:00:00Z,MAJOR,INCIDENT\n"+
+				",hugo,1235,ticket B,OPEN,andrew,SOMEDAY,,,\n"+
+				",hugo,1236,ticket C,OPEN,andrew,,yesterday,,\n"+
+				",hugo,1237,ticket D,OPEN,andrew,,,SEV1,\n"+
+				",hugo,1238,ticket E,OPEN,andrew,,,,FEATURE\n"), nil, workflow.Default())
+		require.NoError(t, err)
+
+		require.Len(t, entries, 1)
+		ticket := entries[0].Ticket
+		assert.Equal(t, "1234", ticket.TicketID)
+		assert.Equal(t, models.PriorityHigh, ticket.Priority)
+		assert.Equal(t, models.SeverityMajor, ticket.Severity)
+		assert.Equal(t, models.CategoryIncident, ticket.Category)
+		assert.Equal(t, []string{"billing", "vip"}, ticket.Tags)
+		assert.Equal(t, models.NewTimestamp(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)), ticket.CreatedAt)
+		assert.Equal(t, []models.ImportLineResult{
+			{Line: 3, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - priority"},
+			{Line: 4, TicketID: "1236", Result: models.ImportRejected, Error: "wrong column - createdAt"},
+			{Line: 5, TicketID: "1237", Result: models.ImportRejected, Error: "wrong column - severity"},
+			{Line: 6, TicketID: "1238", Result: models.ImportRejected, Error: "wrong column - category"},
+		}, rejected)
+	})
+
+	t.Run("custom mapping", func(t *testing.T) {
+		entries, rejected, _, err := ParseCSV(strings.NewReader(
+			"Ticket #,Summary,State,Agent,Reporter\n"+
+				"1234,ticket A,OPEN,andrew,hugo\n"), models.ColumnMapping{
+			models.ColumnID:          "Ticket #",
+			models.ColumnDescription: "summary",
+			models.ColumnStatus:      "State",
+			models.ColumnAssignedTo:  "Agent",
+			models.ColumnCreatedBy:   "Reporter",
+		}, workflow.Default())
+		require.NoError(t, err)
+		assert.Empty(t, rejected)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "ticket A", entries[0].Ticket.Description)
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		_, _, _, err := ParseCSV(strings.NewReader("1234,ticket A,OPEN,andrew,hugo\n"), nil, workflow.Default())
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+
+	t.Run("unknown mapped column", func(t *testing.T) {
+		_, _, _, err := ParseCSV(strings.NewReader(importHeader), models.ColumnMapping{"owner": "Owner"}, workflow.Default())
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	// no expectations, a dry run must not touch the repositories or dispatch
+	service := NewService(repositories.NewMockImportJobRepository(t), repositories.NewMockTicketRepository(t), NewMockDispatcher(t), &assignment.Assigner{}, workflow.Default())
+
+	report, err := service.DryRun(context.Background(), strings.NewReader(importHeader+
+		"1234,ticket A description,OPEN,andrew,hugo\n"+
+		"1235,ticket B description,UNKNOWN,david,hugo\n"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ImportReport{
+		Rejected: 1,
+		Valid:    1,
+		Results: []models.ImportLineResult{
+			{Line: 2, TicketID: "1234", Result: models.ImportValid},
+			{Line: 3, TicketID: "1235", Result: models.ImportRejected, Error: "wrong column - status"},
+		},
+	}, report)
+}
+
+func TestImportJob(t *testing.T) {
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	pool := NewWorkerPool(4, 10)
+	service := NewService(repo, repo, pool, &assignment.Assigner{}, workflow.Default())
+	pool.Start(service)
+
+	var csvBody strings.Builder
+	csvBody.WriteString(importHeader)
+	for i := 0; i < 2*ChunkSize+10; i++ {
+		fmt.Fprintf(&csvBody, "t-%03d,ticket description,OPEN,andrew,hugo\n", i)
+	}
+	csvBody.WriteString("bad,ticket description,UNKNOWN,andrew,hugo\n")
+
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo"})
+	job, err := service.Submit(ctx, strings.NewReader(csvBody.String()), nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobPending, job.Status)
+	pool.Stop()
+
+	stored, err := repo.GetImportJob(ctx, job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobCompleted, stored.Status)
+	assert.Equal(t, "hugo", stored.SubmittedBy)
+	assert.Equal(t, 2*ChunkSize+11, stored.TotalLines)
+	assert.Equal(t, 2*ChunkSize+11, stored.Processed)
+	assert.Equal(t, 2*ChunkSize+10, stored.Created)
+	assert.Equal(t, 1, stored.Rejected)
+	assert.NotEmpty(t, stored.CompletedAt)
+
+	errs, err := repo.ListImportErrors(ctx, job.JobID)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, 2*ChunkSize+12, errs[0].Line)
+
+	history, err := repo.GetTicketHistory(ctx, "t-000", models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 1)
+	assert.Equal(t, "hugo", history.Entries[0].Actor)
+}
+
+func TestImportAssignsTickets(t *testing.T) {
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	assigner, err := assignment.New(assignment.Definition{
+		Strategy: assignment.StrategyRoundRobin,
+		Agents:   []assignment.AgentDefinition{{Name: "maria"}, {Name: "sofia"}},
+	}, repo, repo)
+	require.NoError(t, err)
+	pool := NewWorkerPool(1, 10)
+	service := NewService(repo, repo, pool, assigner, workflow.Default())
+	pool.Start(service)
+
+	csvBody := importHeader +
+		"t-1,ticket description,OPEN,,hugo\n" +
+		"t-2,ticket description,OPEN,andrew,hugo\n" +
+		"t-3,ticket description,OPEN,,hugo\n" +
+		"t-4,ticket description,CLOSED,,hugo\n"
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo"})
+	_, err = service.Submit(ctx, strings.NewReader(csvBody), nil)
+	require.NoError(t, err)
+	pool.Stop()
+
+	for id, expected := range map[string][2]string{
+		"t-1": {"maria", assignment.StrategyRoundRobin},
+		"t-2": {"andrew", ""},
+		"t-3": {"sofia", assignment.StrategyRoundRobin},
+		"t-4": {"None", ""},
+	} {
+		ticket, err := repo.GetTicket(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, expected[0], ticket.AssignedTo, id)
+		assert.Equal(t, expected[1], ticket.AssignedBy, id)
+	}
+}
+
+func TestProcessChunkOnce(t *testing.T) {
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	dispatcher := NewMockDispatcher(t)
+	dispatcher.EXPECT().Dispatch(mock.Anything, mock.Anything).Return(nil)
+	service := NewService(repo, repo, dispatcher, &assignment.Assigner{}, workflow.Default())
+
+	ctx := context.Background()
+	job, err := service.Submit(ctx, strings.NewReader(importHeader+"1234,ticket A description,OPEN,andrew,hugo\n"), nil)
+	require.NoError(t, err)
+
+	task := Task{JobID: job.JobID, Chunk: 0}
+	require.NoError(t, service.ProcessChunk(ctx, task))
+	// a redelivered message must not be counted twice
+	require.NoError(t, repo.CompleteImportChunk(ctx, job.JobID, 0, []models.ImportLineResult{
+		{Line: 2, TicketID: "1234", Result: models.ImportCreated},
+	}))
+	require.NoError(t, service.ProcessChunk(ctx, task))
+
+	stored, err := repo.GetImportJob(ctx, job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobCompleted, stored.Status)
+	assert.Equal(t, 1, stored.Processed)
+	assert.Equal(t, 1, stored.Created)
+
+	// completion is announced once
+	entries, err := repo.ListOutbox(ctx, "", 0)
+	require.NoError(t, err)
+	var completed []models.Event
+	for _, entry := range entries {
+		if entry.Event.Type == models.EventBulkImportCompleted {
+			completed = append(completed, entry.Event)
+		}
+	}
+	require.Len(t, completed, 1)
+	assert.Equal(t, job.JobID, completed[0].Subject)
+}
+
+func TestSubmitWithoutValidLines(t *testing.T) {
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	service := NewService(repo, repo, NewMockDispatcher(t), &assignment.Assigner{}, workflow.Default())
+
+	job, err := service.Submit(context.Background(), strings.NewReader(importHeader+"1234,only,three\n"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobCompleted, job.Status)
+	assert.Equal(t, 1, job.Rejected)
+}
@@ -0,0 +1,60 @@
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// SQSDispatcher sends one message per chunk to the import queue, the
+// messages are consumed by the importworker Lambda
+type SQSDispatcher struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+func NewSQSDispatcher(ctx context.Context, queueURL string) *SQSDispatcher {
+	cfg, _ := config.LoadDefaultConfig(ctx)
+	return &SQSDispatcher{
+		client:   sqs.NewFromConfig(cfg),
+		queueURL: queueURL,
+	}
+}
+
+func (d *SQSDispatcher) Dispatch(ctx context.Context, task Task) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	_, err = d.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(d.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
+
+// NewSQSHandler returns the Lambda handler for the import queue. Messages
+// that fail are reported back so only they are retried.
+func NewSQSHandler(processor Processor) func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	return func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+		response := events.SQSEventResponse{BatchItemFailures: []events.SQSBatchItemFailure{}}
+		for _, message := range event.Records {
+			var task Task
+			if err := json.Unmarshal([]byte(message.Body), &task); err != nil {
+				// retrying will not fix a malformed message
+				slog.ErrorContext(ctx, "Dropping malformed import message", "messageId", message.MessageId, "error", err)
+				continue
+			}
+			if err := processor.ProcessChunk(ctx, task); err != nil {
+				slog.ErrorContext(ctx, "Failed to process import chunk", "jobId", task.JobID, "chunk", task.Chunk, "error", err)
+				response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: message.MessageId})
+			}
+		}
+		return response, nil
+	}
+}
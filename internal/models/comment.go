@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Timestamps used in sort keys need a fixed width so they sort lexicographically
+const SortableTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+type Comment struct {
+	CommentID string `dynamodbav:"comment_id"`
+	TicketID  string `dynamodbav:"ticket_id"`
+	Author    string `dynamodbav:"author"`
+	Body      string `dynamodbav:"body"`
+	CreatedAt string `dynamodbav:"createdAt"`
+	UpdatedAt string `dynamodbav:"updatedAt,omitempty"`
+}
+
+type CommentDbRecord struct {
+	Comment
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+type CommentPage struct {
+	Comments   []Comment
+	NextCursor string
+}
+
+// PageRequest controls paginated reads. Cursor is the opaque token returned
+// with the previous page.
+type PageRequest struct {
+	Limit      int
+	Cursor     string
+	Descending bool
+}
+
+func FormatSortableTime(t time.Time) string {
+	return t.UTC().Format(SortableTimeFormat)
+}
@@ -0,0 +1,65 @@
+package models
+
+import "encoding/json"
+
+type EventType string
+
+const (
+	EventTicketCreated       EventType = "ticket.created"
+	EventTicketStatusChanged EventType = "ticket.status_changed"
+	EventTicketAssigned      EventType = "ticket.assigned"
+	EventCommentAdded        EventType = "comment.added"
+	EventBulkImportCompleted EventType = "bulk_import.completed"
+)
+
+var eventTypes = map[EventType]bool{
+	EventTicketCreated:       true,
+	EventTicketStatusChanged: true,
+	EventTicketAssigned:      true,
+	EventCommentAdded:        true,
+	EventBulkImportCompleted: true,
+}
+
+func (t EventType) Valid() bool {
+	return eventTypes[t]
+}
+
+// Event is a domain event, written to the outbox in the same transaction as
+// the change it describes. Subject is the ticket or import job the event is
+// about, the events of a subject are relayed in Sequence order.
+type Event struct {
+	ID         string          `json:"id" dynamodbav:"event_id"`
+	Type       EventType       `json:"type" dynamodbav:"eventType"`
+	Tenant     string          `json:"tenant" dynamodbav:"tenant"`
+	Actor      string          `json:"actor" dynamodbav:"actor"`
+	OccurredAt string          `json:"occurredAt" dynamodbav:"occurredAt"`
+	Subject    string          `json:"subject" dynamodbav:"subject"`
+	Sequence   int64           `json:"sequence" dynamodbav:"sequence"`
+	Data       json.RawMessage `json:"data" dynamodbav:"data"`
+}
+
+// Data of the ticket.* events
+type TicketEventData struct {
+	Ticket *Ticket `json:"ticket"`
+}
+
+// Data of the comment.added event
+type CommentEventData struct {
+	Comment *Comment `json:"comment"`
+}
+
+// Data of the bulk_import.completed event
+type ImportJobEventData struct {
+	Job *ImportJob `json:"job"`
+}
+
+// OutboxEntry is an event waiting to be relayed. Keys sort in commit order.
+type OutboxEntry struct {
+	Key   string `dynamodbav:"SK"`
+	Event Event  `dynamodbav:"event"`
+}
+
+type OutboxDbRecord struct {
+	OutboxEntry
+	PK string `dynamodbav:"PK"`
+}
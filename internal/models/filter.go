@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// TicketFilter selects tickets for listing. Empty fields are not filtered on,
+// the created-at range is inclusive.
+type TicketFilter struct {
+	Status      TicketStatus
+	AssignedTo  string
+	CreatedBy   string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Page        PageRequest
+}
+
+// TenantTicket is a ticket along with its tenant, for the system processes
+// working across tenants
+type TenantTicket struct {
+	Tenant string
+	Ticket Ticket
+}
+
+type TicketPage struct {
+	Tickets    []Ticket
+	NextCursor string
+}
+
+func (f *TicketFilter) Matches(ticket *Ticket) bool {
+	if f.Status != "" && ticket.Status != f.Status {
+		return false
+	}
+	if f.AssignedTo != "" && ticket.AssignedTo != f.AssignedTo {
+		return false
+	}
+	if f.CreatedBy != "" && ticket.CreatedBy != f.CreatedBy {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && ticket.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && ticket.CreatedAt.After(f.CreatedTo) {
+		return false
+	}
+	return true
+}
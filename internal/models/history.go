@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	ActionCreated         = "created"
+	ActionStatusChanged   = "status_changed"
+	ActionAssigned        = "assigned"
+	ActionUpdated         = "updated"
+	ActionImported        = "imported"
+	ActionQueued          = "queued"
+	ActionPriorityChanged = "priority_changed"
+	ActionTagged          = "tagged"
+	ActionDeleted         = "deleted"
+	ActionRestored        = "restored"
+)
+
+type FieldChange struct {
+	Field  string `dynamodbav:"field"`
+	Before string `dynamodbav:"before"`
+	After  string `dynamodbav:"after"`
+}
+
+// HistoryEntry is an immutable record of a single change to a ticket
+type HistoryEntry struct {
+	EntryID   string        `dynamodbav:"entry_id"`
+	TicketID  string        `dynamodbav:"ticket_id"`
+	Action    string        `dynamodbav:"action"`
+	Actor     string        `dynamodbav:"actor"`
+	Timestamp string        `dynamodbav:"timestamp"`
+	Changes   []FieldChange `dynamodbav:"changes"`
+}
+
+type HistoryDbRecord struct {
+	HistoryEntry
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+type HistoryPage struct {
+	Entries    []HistoryEntry
+	NextCursor string
+}
+
+// DiffTickets returns the fields that differ between two versions of a
+// ticket, named after their stored attribute. A nil before is treated as an
+// empty ticket. The version counter is not part of the diff.
+func DiffTickets(before *Ticket, after *Ticket) []FieldChange {
+	if before == nil {
+		before = &Ticket{}
+	}
+	if after == nil {
+		after = &Ticket{}
+	}
+
+	changes := []FieldChange{}
+	beforeValue := reflect.ValueOf(*before)
+	afterValue := reflect.ValueOf(*after)
+	ticketType := beforeValue.Type()
+	for i := 0; i < ticketType.NumField(); i++ {
+		name := strings.Split(ticketType.Field(i).Tag.Get("dynamodbav"), ",")[0]
+		if name == "" || name == "-" || name == "version" {
+			continue
+		}
+		from := formatField(beforeValue.Field(i))
+		to := formatField(afterValue.Field(i))
+		if from != to {
+			changes = append(changes, FieldChange{Field: name, Before: from, After: to})
+		}
+	}
+	return changes
+}
+
+func formatField(value reflect.Value) string {
+	if value.Kind() == reflect.String {
+		return value.String()
+	}
+	if value.IsZero() {
+		return ""
+	}
+	if t, ok := value.Interface().(Timestamp); ok {
+		return FormatSortableTime(t.Time)
+	}
+	data, _ := json.Marshal(value.Interface())
+	return string(data)
+}
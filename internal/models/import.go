@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ImportCreated  = "created"
+	ImportRejected = "rejected"
+	ImportFailed   = "failed"
+	// dry runs only validate, lines that would be imported are reported as valid
+	ImportValid = "valid"
+)
+
+// Import columns, by default the header of a column is its name
+const (
+	ColumnID          = "id"
+	ColumnDescription = "description"
+	ColumnStatus      = "status"
+	ColumnAssignedTo  = "assignedTo"
+	ColumnCreatedBy   = "createdBy"
+	ColumnPriority    = "priority"
+	ColumnSeverity    = "severity"
+	ColumnCategory    = "category"
+	ColumnTags        = "tags"
+	ColumnCreatedAt   = "createdAt"
+)
+
+// Separates the tags inside the tags column
+const TagSeparator = ";"
+
+var requiredColumns = []string{ColumnID, ColumnDescription, ColumnStatus, ColumnAssignedTo, ColumnCreatedBy}
+var optionalColumns = []string{ColumnPriority, ColumnSeverity, ColumnCategory, ColumnTags, ColumnCreatedAt}
+
+// Import columns in the order they are exported
+func ImportColumnNames() []string {
+	return append(append([]string{}, requiredColumns...), optionalColumns...)
+}
+
+// ColumnMapping maps import columns to the header names used in the file.
+// Columns left out keep their default header.
+type ColumnMapping map[string]string
+
+// ImportColumns is the position of each import column found in the header
+type ImportColumns map[string]int
+
+// Resolve finds the position of every column in the header row. Header
+// names are matched ignoring case and surrounding spaces.
+func (m ColumnMapping) Resolve(header []string) (ImportColumns, error) {
+	known := make(map[string]bool)
+	for _, column := range ImportColumnNames() {
+		known[column] = true
+	}
+	for column := range m {
+		if !known[column] {
+			return nil, fmt.Errorf("unknown import column %q", column)
+		}
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, duplicate := positions[name]; !duplicate {
+			positions[name] = i
+		}
+	}
+
+	columns := make(ImportColumns)
+	for column := range known {
+		name := column
+		if mapped, ok := m[column]; ok {
+			name = mapped
+		}
+		if i, ok := positions[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[column] = i
+		}
+	}
+	for _, column := range requiredColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+	return columns, nil
+}
+
+func (c ImportColumns) value(record []string, column string) string {
+	i, ok := c[column]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// position of the right-most mapped column
+func (c ImportColumns) last() int {
+	last := -1
+	for _, i := range c {
+		if i > last {
+			last = i
+		}
+	}
+	return last
+}
+
+// ImportLineResult is the outcome of importing one CSV line
+type ImportLineResult struct {
+	Line     int    `json:"line" dynamodbav:"line"`
+	TicketID string `json:"ticketId,omitempty" dynamodbav:"ticketId,omitempty"`
+	Result   string `json:"result" dynamodbav:"result"`
+	Error    string `json:"error,omitempty" dynamodbav:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Created  int                `json:"created"`
+	Rejected int                `json:"rejected"`
+	Failed   int                `json:"failed"`
+	Valid    int                `json:"valid,omitempty"`
+	Results  []ImportLineResult `json:"results"`
+}
+
+func NewImportReport(results []ImportLineResult) ImportReport {
+	report := ImportReport{Results: results}
+	if report.Results == nil {
+		report.Results = []ImportLineResult{}
+	}
+	for _, result := range results {
+		switch result.Result {
+		case ImportCreated:
+			report.Created++
+		case ImportRejected:
+			report.Rejected++
+		case ImportFailed:
+			report.Failed++
+		case ImportValid:
+			report.Valid++
+		}
+	}
+	return report
+}
@@ -0,0 +1,59 @@
+package models
+
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "PENDING"
+	ImportJobRunning   ImportJobStatus = "RUNNING"
+	ImportJobCompleted ImportJobStatus = "COMPLETED"
+)
+
+// ImportJob tracks an asynchronous bulk import. Attribute names avoid the
+// ticket GSI keys so jobs never show up in ticket listings.
+type ImportJob struct {
+	JobID           string          `json:"jobId" dynamodbav:"job_id"`
+	Status          ImportJobStatus `json:"status" dynamodbav:"jobStatus"`
+	SubmittedBy     string          `json:"submittedBy" dynamodbav:"submittedBy"`
+	SubmittedAt     string          `json:"submittedAt" dynamodbav:"submittedAt"`
+	CompletedAt     string          `json:"completedAt,omitempty" dynamodbav:"completedAt,omitempty"`
+	TotalLines      int             `json:"totalLines" dynamodbav:"totalLines"`
+	Processed       int             `json:"processed" dynamodbav:"processed"`
+	Created         int             `json:"created" dynamodbav:"created"`
+	Rejected        int             `json:"rejected" dynamodbav:"rejected"`
+	Failed          int             `json:"failed" dynamodbav:"failed"`
+	Chunks          int             `json:"-" dynamodbav:"chunks"`
+	RemainingChunks int             `json:"-" dynamodbav:"remainingChunks"`
+}
+
+type ImportJobDbRecord struct {
+	ImportJob
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+// ImportEntry is a validated CSV line waiting to be written
+type ImportEntry struct {
+	Line   int    `dynamodbav:"line"`
+	Ticket Ticket `dynamodbav:"ticket"`
+}
+
+// ImportChunk is a slice of a job processed as one unit of work
+type ImportChunk struct {
+	JobID     string        `dynamodbav:"job_id"`
+	Index     int           `dynamodbav:"chunk"`
+	Entries   []ImportEntry `dynamodbav:"entries"`
+	Processed bool          `dynamodbav:"processed"`
+}
+
+type ImportChunkDbRecord struct {
+	ImportChunk
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+type ImportLineDbRecord struct {
+	ImportLineResult
+	JobID string `dynamodbav:"job_id"`
+	PK    string `dynamodbav:"PK"`
+	SK    string `dynamodbav:"SK"`
+}
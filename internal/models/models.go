@@ -2,29 +2,58 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"example.com/ticket-system/internal/identity"
 )
 
 type TicketStatus string
 
 const (
-	StatusOpen   TicketStatus = "OPEN"
-	StatusClosed TicketStatus = "CLOSED"
+	StatusOpen            TicketStatus = "OPEN"
+	StatusInProgress      TicketStatus = "IN_PROGRESS"
+	StatusPendingCustomer TicketStatus = "PENDING_CUSTOMER"
+	StatusResolved        TicketStatus = "RESOLVED"
+	StatusClosed          TicketStatus = "CLOSED"
+	StatusReopened        TicketStatus = "REOPENED"
 )
 
 type Ticket struct {
-	TicketID    string       `dynamodbav:"ticket_id"`
-	Description string       `dynamodbav:"description"`
-	Status      TicketStatus `dynamodbav:"status"`
-	CreatedBy   string       `dynamodbav:"createdBy"`
-	CreatedAt   string       `dynamodbav:"createdAt"`
-	AssignedTo  string       `dynamodbav:"assignedTo"`
+	TicketID    string         `dynamodbav:"ticket_id"`
+	Description string         `dynamodbav:"description"`
+	Status      TicketStatus   `dynamodbav:"status"`
+	CreatedBy   string         `dynamodbav:"createdBy"`
+	CreatedAt   Timestamp      `dynamodbav:"createdAt"`
+	AssignedTo  string         `dynamodbav:"assignedTo"`
+	AssignedBy  string         `json:",omitempty" dynamodbav:"assignedBy,omitempty"` // the strategy that picked AssignedTo, empty when a user did
+	Queue       string         `json:",omitempty" dynamodbav:"queue,omitempty"`      // id of the queue the ticket waits in, AssignedTo must be a member of its team
+	Priority    TicketPriority `json:",omitempty" dynamodbav:"priority,omitempty"`
+	Severity    TicketSeverity `json:",omitempty" dynamodbav:"severity,omitempty"`
+	Category    TicketCategory `json:",omitempty" dynamodbav:"category,omitempty"`
+	Tags        []string       `json:",omitempty" dynamodbav:"tags,omitempty"`
+	SLA         *TicketSLA     `json:",omitempty" dynamodbav:"sla,omitempty"`
+	Escalations []string       `json:",omitempty" dynamodbav:"escalations,omitempty"` // rules that already fired for the ticket
+	DeletedAt   string         `json:",omitempty" dynamodbav:"deletedAt,omitempty"`   // set while the ticket is soft deleted, SortableTimeFormat
+	DeletedBy   string         `json:",omitempty" dynamodbav:"deletedBy,omitempty"`
+	Version     int64          `dynamodbav:"version"` // incremented on every write, used for optimistic locking
+}
+
+// Soft deleted tickets are kept for restoring but hidden from every read
+func (m *Ticket) IsDeleted() bool {
+	return m.DeletedAt != ""
 }
 
 type TicketDbRecord struct {
 	Ticket
 	PK string `dynamodbav:"PK"`
 	SK string `dynamodbav:"SK"`
+	// keys of the sparse SLADue index, only set while an SLA deadline runs
+	SLAPartition string `dynamodbav:"slaPartition,omitempty"`
+	SLADue       string `dynamodbav:"slaDue,omitempty"`
+	// key of the sparse OpenPriority index, only set while the ticket is open
+	OpenPriority string `dynamodbav:"openPriority,omitempty"`
 }
 
 // Bulk import models
@@ -35,30 +64,74 @@ type BulkImportRecord struct {
 	Status      string
 	AssignedTo  string
 	CreatedBy   string
+	Priority    string
+	Severity    string
+	Category    string
+	Tags        []string
+	CreatedAt   string
+}
+
+// BulkWriteFailure is a ticket that could not be stored during a bulk import
+type BulkWriteFailure struct {
+	TicketID string
+	Reason   string
 }
 
-func (bi *BulkImportRecord) LoadFromRecord(record []string) error {
-	if len(record) < 4 {
-		return errors.New("bulk import - record should have 5 columns")
+// Reads the record using the column positions resolved from the header row
+func (bi *BulkImportRecord) LoadFromRecord(record []string, columns ImportColumns) error {
+	if len(record) <= columns.last() {
+		return fmt.Errorf("bulk import - record should have %d columns", columns.last()+1)
 	}
 
-	bi.ID = record[0]
-	bi.Description = record[1]
-	bi.Status = record[2]
-	bi.AssignedTo = record[3]
-	bi.CreatedBy = record[4]
+	bi.ID = columns.value(record, ColumnID)
+	bi.Description = columns.value(record, ColumnDescription)
+	bi.Status = columns.value(record, ColumnStatus)
+	bi.AssignedTo = columns.value(record, ColumnAssignedTo)
+	bi.CreatedBy = columns.value(record, ColumnCreatedBy)
+	bi.Priority = columns.value(record, ColumnPriority)
+	bi.Severity = columns.value(record, ColumnSeverity)
+	bi.Category = columns.value(record, ColumnCategory)
+	bi.CreatedAt = columns.value(record, ColumnCreatedAt)
+	bi.Tags = NormalizeTags(strings.Split(columns.value(record, ColumnTags), TagSeparator))
 	return nil
 }
 
-// returns the first validation error found for the record
-func (bi *BulkImportRecord) Validate() error {
-	validStatuses := map[TicketStatus]bool{
-		StatusClosed: true,
-		StatusOpen:   true,
+// Builds the import record of an existing ticket, the inverse of ToTicket
+func NewBulkImportRecord(ticket *Ticket) BulkImportRecord {
+	return BulkImportRecord{
+		ID:          ticket.TicketID,
+		Description: ticket.Description,
+		Status:      string(ticket.Status),
+		AssignedTo:  ticket.AssignedTo,
+		CreatedBy:   ticket.CreatedBy,
+		Priority:    string(ticket.Priority),
+		Severity:    string(ticket.Severity),
+		Category:    string(ticket.Category),
+		Tags:        ticket.Tags,
+		CreatedAt:   ticket.CreatedAt.Format(time.RFC3339Nano),
 	}
+}
+
+// Returns the record as a CSV row in ImportColumnNames order
+func (bi *BulkImportRecord) ToRecord() []string {
+	return []string{
+		bi.ID,
+		bi.Description,
+		bi.Status,
+		bi.AssignedTo,
+		bi.CreatedBy,
+		bi.Priority,
+		bi.Severity,
+		bi.Category,
+		strings.Join(bi.Tags, TagSeparator),
+		bi.CreatedAt,
+	}
+}
 
-	validStatus := validStatuses[TicketStatus(bi.Status)]
-	if !validStatus {
+// returns the first validation error found for the record, its status must
+// be one of statuses
+func (bi *BulkImportRecord) Validate(statuses Statuses) error {
+	if !statuses.IsValidStatus(TicketStatus(bi.Status)) {
 		return errors.New("wrong column - status")
 	}
 	if len(bi.ID) == 0 {
@@ -67,16 +140,56 @@ func (bi *BulkImportRecord) Validate() error {
 	if len(bi.Description) == 0 {
 		return errors.New("wrong column - description")
 	}
+	// both end up in index keys, DynamoDB would refuse the write
+	if !identity.ValidUsername(bi.CreatedBy) {
+		return errors.New("wrong column - createdBy")
+	}
+	if bi.AssignedTo != "" && !identity.ValidUsername(bi.AssignedTo) {
+		return errors.New("wrong column - assignedTo")
+	}
+	if bi.Priority != "" && !TicketPriority(bi.Priority).Valid() {
+		return errors.New("wrong column - priority")
+	}
+	if bi.Severity != "" && !TicketSeverity(bi.Severity).Valid() {
+		return errors.New("wrong column - severity")
+	}
+	if bi.Category != "" && !TicketCategory(bi.Category).Valid() {
+		return errors.New("wrong column - category")
+	}
+	if ValidateTags(bi.Tags) != nil {
+		return errors.New("wrong column - tags")
+	}
+	if bi.CreatedAt != "" {
+		if _, err := time.Parse(time.RFC3339, bi.CreatedAt); err != nil {
+			return errors.New("wrong column - createdAt")
+		}
+	}
 
 	return nil
 }
 
+// The record must have passed Validate
 func (bi *BulkImportRecord) ToTicket() Ticket {
+	createdAt := time.Now()
+	if bi.CreatedAt != "" {
+		createdAt, _ = time.Parse(time.RFC3339, bi.CreatedAt)
+	}
+	// unassigned tickets are assigned to "None", as when created by the API
+	assignedTo := bi.AssignedTo
+	if assignedTo == "" {
+		assignedTo = "None"
+	}
 	return Ticket{
 		TicketID:    bi.ID,
 		Description: bi.Description,
+		Status:      TicketStatus(bi.Status),
 		CreatedBy:   bi.CreatedBy,
-		AssignedTo:  bi.AssignedTo,
-		CreatedAt:   time.Now().UTC().String(),
+		AssignedTo:  assignedTo,
+		Priority:    TicketPriority(bi.Priority),
+		Severity:    TicketSeverity(bi.Severity),
+		Category:    TicketCategory(bi.Category),
+		Tags:        bi.Tags,
+		CreatedAt:   NewTimestamp(createdAt),
+		Version:     1,
 	}
 }
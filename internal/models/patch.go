@@ -0,0 +1,82 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrInvalidPatch   = errors.New("invalid patch")
+	ErrImmutableField = errors.New("field cannot be changed")
+)
+
+// Fields of the ticket representation managed by the system. A patch may
+// repeat their current value but not change it.
+var immutableTicketFields = []string{"TicketID", "CreatedBy", "CreatedAt", "AssignedBy", "SLA", "Escalations", "DeletedAt", "DeletedBy", "Version"}
+
+// MergePatch applies an RFC 7386 merge patch to the JSON representation of
+// the ticket and returns the patched copy, the ticket is left untouched.
+// Fields the representation does not have are rejected.
+func MergePatch(ticket *Ticket, patch []byte) (*Ticket, error) {
+	var changes map[string]any
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return nil, fmt.Errorf("%w - the patch must be a JSON object", ErrInvalidPatch)
+	}
+
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(mergeValue(document, changes))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var patched Ticket
+	if err := decoder.Decode(&patched); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidPatch, err)
+	}
+
+	before := reflect.ValueOf(*ticket)
+	after := reflect.ValueOf(patched)
+	for _, field := range immutableTicketFields {
+		if !reflect.DeepEqual(before.FieldByName(field).Interface(), after.FieldByName(field).Interface()) {
+			return nil, fmt.Errorf("%w - %s", ErrImmutableField, field)
+		}
+	}
+	return &patched, nil
+}
+
+// Merges patch into target: objects are merged member by member, null
+// removes a member and any other value replaces the target
+func mergeValue(target any, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	document, ok := target.(map[string]any)
+	if !ok {
+		document = map[string]any{}
+	}
+	merged := make(map[string]any, len(document))
+	for name, value := range document {
+		merged[name] = value
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = mergeValue(merged[name], value)
+	}
+	return merged
+}
@@ -0,0 +1,169 @@
+package models
+
+import "time"
+
+// Statuses that stop the SLA clock. Waiting on the customer pauses it,
+// resolving or closing the ticket stops it until the ticket is reopened.
+var slaStoppedStatuses = map[TicketStatus]bool{
+	StatusPendingCustomer: true,
+	StatusResolved:        true,
+	StatusClosed:          true,
+}
+
+// TicketSLA holds the deadlines set by the SLA policy when the ticket was
+// created, and the times they were met. Times use SortableTimeFormat so they
+// compare as strings.
+type TicketSLA struct {
+	Policy           string `dynamodbav:"policy"`
+	FirstResponseDue string `dynamodbav:"firstResponseDue"`
+	ResolutionDue    string `dynamodbav:"resolutionDue"`
+	RespondedAt      string `dynamodbav:"respondedAt,omitempty"`
+	ResolvedAt       string `dynamodbav:"resolvedAt,omitempty"`
+	// set while the clock is stopped, the pending deadlines move by the time
+	// spent stopped when it restarts
+	PausedAt string `dynamodbav:"pausedAt,omitempty"`
+}
+
+// SLATarget is the state of one deadline at a given time
+type SLATarget struct {
+	Due string
+	// when the target was met, empty while it is pending
+	MetAt string
+	// time left before the deadline, negative once it passed. Frozen while
+	// the clock is stopped.
+	Remaining time.Duration
+	Breached  bool
+}
+
+func ParseSortableTime(value string) (time.Time, error) {
+	return time.Parse(SortableTimeFormat, value)
+}
+
+func (s *TicketSLA) Paused() bool {
+	return s.PausedAt != ""
+}
+
+// Responded records the first response, it reports false when the ticket
+// already had one
+func (s *TicketSLA) Responded(now time.Time) bool {
+	if s.RespondedAt != "" {
+		return false
+	}
+	s.RespondedAt = FormatSortableTime(now)
+	return true
+}
+
+// StatusChanged follows the clock through a status change. Leaving OPEN is
+// a response to the requester.
+func (s *TicketSLA) StatusChanged(from TicketStatus, to TicketStatus, now time.Time) {
+	if from == to {
+		return
+	}
+	if from == StatusOpen {
+		s.Responded(now)
+	}
+	switch stopped := slaStoppedStatuses[to]; {
+	case stopped && !s.Paused():
+		s.PausedAt = FormatSortableTime(now)
+	case !stopped && s.Paused():
+		s.resume(now)
+	}
+	if to == StatusResolved || to == StatusClosed {
+		if s.ResolvedAt == "" {
+			s.ResolvedAt = FormatSortableTime(now)
+		}
+	} else {
+		s.ResolvedAt = ""
+	}
+}
+
+// Moves the pending deadlines by the time the clock was stopped
+func (s *TicketSLA) resume(now time.Time) {
+	pausedAt, err := ParseSortableTime(s.PausedAt)
+	s.PausedAt = ""
+	if err != nil || !now.After(pausedAt) {
+		return
+	}
+	paused := now.Sub(pausedAt)
+	if s.RespondedAt == "" {
+		s.FirstResponseDue = shiftSortableTime(s.FirstResponseDue, paused)
+	}
+	s.ResolutionDue = shiftSortableTime(s.ResolutionDue, paused)
+}
+
+func shiftSortableTime(value string, d time.Duration) string {
+	t, err := ParseSortableTime(value)
+	if err != nil {
+		return value
+	}
+	return FormatSortableTime(t.Add(d))
+}
+
+func (s *TicketSLA) FirstResponse(now time.Time) SLATarget {
+	return s.target(s.FirstResponseDue, s.RespondedAt, now)
+}
+
+func (s *TicketSLA) Resolution(now time.Time) SLATarget {
+	return s.target(s.ResolutionDue, s.ResolvedAt, now)
+}
+
+func (s *TicketSLA) target(due string, metAt string, now time.Time) SLATarget {
+	target := SLATarget{Due: due, MetAt: metAt}
+	dueTime, err := ParseSortableTime(due)
+	if err != nil {
+		return target
+	}
+	at := now
+	switch {
+	case metAt != "":
+		at, err = ParseSortableTime(metAt)
+	case s.Paused():
+		at, err = ParseSortableTime(s.PausedAt)
+	}
+	if err != nil {
+		at = now
+	}
+	target.Remaining = dueTime.Sub(at)
+	target.Breached = target.Remaining < 0
+	return target
+}
+
+// NextDue is the earliest deadline still running, empty when the clock is
+// stopped or every target was met
+func (s *TicketSLA) NextDue() string {
+	if s.Paused() {
+		return ""
+	}
+	next := ""
+	if s.RespondedAt == "" {
+		next = s.FirstResponseDue
+	}
+	if s.ResolvedAt == "" && (next == "" || s.ResolutionDue < next) {
+		next = s.ResolutionDue
+	}
+	return next
+}
+
+// SLAStatusChanged follows the SLA clock through a change from status from
+// to the current status. The SLA is copied before it changes, so copies of
+// the ticket taken earlier keep theirs.
+func (t *Ticket) SLAStatusChanged(from TicketStatus, now time.Time) {
+	if t.SLA == nil {
+		return
+	}
+	sla := *t.SLA
+	sla.StatusChanged(from, t.Status, now)
+	t.SLA = &sla
+}
+
+// SLAResponded records the first response to the requester, it reports
+// whether the ticket changed
+func (t *Ticket) SLAResponded(now time.Time) bool {
+	if t.SLA == nil || t.SLA.RespondedAt != "" {
+		return false
+	}
+	sla := *t.SLA
+	sla.Responded(now)
+	t.SLA = &sla
+	return true
+}
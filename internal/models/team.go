@@ -0,0 +1,38 @@
+package models
+
+// Team groups the agents working on the queues it is given
+type Team struct {
+	TeamID  string   `json:"id" dynamodbav:"team_id"`
+	Name    string   `json:"name" dynamodbav:"name"`
+	Members []string `json:"members" dynamodbav:"members"`
+}
+
+func (t *Team) HasMember(userName string) bool {
+	for _, member := range t.Members {
+		if member == userName {
+			return true
+		}
+	}
+	return false
+}
+
+type TeamDbRecord struct {
+	Team
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+// Queue holds tickets waiting for a member of its team to claim them. The
+// members of the team are the only individuals a queued ticket can be
+// assigned to.
+type Queue struct {
+	QueueID string `json:"id" dynamodbav:"queue_id"`
+	Name    string `json:"name" dynamodbav:"name"`
+	TeamID  string `json:"teamId" dynamodbav:"team_id"`
+}
+
+type QueueDbRecord struct {
+	Queue
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Layout of the createdAt values written before they were stored as RFC 3339
+const legacyTimeFormat = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// Timestamp is a time stored in SortableTimeFormat, an RFC 3339 layout of
+// fixed width, so the stored values sort in time order. It is rendered as
+// RFC 3339 in JSON.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp drops the location and the monotonic reading, the timestamp
+// then compares equal to itself once stored and read back
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t.UTC()}
+}
+
+func (t Timestamp) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return &types.AttributeValueMemberS{Value: FormatSortableTime(t.Time)}, nil
+}
+
+func (t *Timestamp) UnmarshalDynamoDBAttributeValue(value types.AttributeValue) error {
+	s, ok := value.(*types.AttributeValueMemberS)
+	if !ok {
+		return fmt.Errorf("timestamp must be a string, got %T", value)
+	}
+	parsed, err := ParseCreatedAt(s.Value)
+	if err != nil {
+		return err
+	}
+	*t = NewTimestamp(parsed)
+	return nil
+}
+
+// Formats a creation time the way it is stored on tickets, for the bounds
+// of the createdAt range key
+func FormatCreatedAt(t time.Time) string {
+	return FormatSortableTime(t)
+}
+
+// Parses a stored createdAt value. Items written before the values were
+// RFC 3339 are still read.
+func ParseCreatedAt(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(legacyTimeFormat, value)
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TicketPriority string
+
+const (
+	PriorityLow    TicketPriority = "LOW"
+	PriorityMedium TicketPriority = "MEDIUM"
+	PriorityHigh   TicketPriority = "HIGH"
+	PriorityUrgent TicketPriority = "URGENT"
+)
+
+// Priorities from lowest to highest
+var Priorities = []TicketPriority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// TicketSeverity is the impact of the issue, set apart from the priority the
+// support team works it with
+type TicketSeverity string
+
+const (
+	SeverityCritical TicketSeverity = "CRITICAL"
+	SeverityMajor    TicketSeverity = "MAJOR"
+	SeverityMinor    TicketSeverity = "MINOR"
+	SeverityTrivial  TicketSeverity = "TRIVIAL"
+)
+
+type TicketCategory string
+
+const (
+	CategoryIncident       TicketCategory = "INCIDENT"
+	CategoryServiceRequest TicketCategory = "SERVICE_REQUEST"
+	CategoryProblem        TicketCategory = "PROBLEM"
+	CategoryQuestion       TicketCategory = "QUESTION"
+)
+
+// Longest tag accepted, in bytes
+const MaxTagLength = 64
+
+func (p TicketPriority) Valid() bool {
+	return validPriorities[p]
+}
+
+func (s TicketSeverity) Valid() bool {
+	return validSeverities[s]
+}
+
+func (c TicketCategory) Valid() bool {
+	return validCategories[c]
+}
+
+// Trims the tags and drops the empty and repeated ones, keeping the order
+// they were given in
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// Tags are free-form, but must fit in the tags column of the CSV exports
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if len(tag) > MaxTagLength || strings.Contains(tag, TagSeparator) {
+			return fmt.Errorf("invalid tag %q", tag)
+		}
+	}
+	return nil
+}
+
+func (m *Ticket) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds the tags the ticket does not have yet
+func (m *Ticket) AddTags(tags ...string) {
+	m.Tags = NormalizeTags(append(append([]string{}, m.Tags...), tags...))
+}
+
+func (m *Ticket) RemoveTags(tags ...string) {
+	var kept []string
+	for _, tag := range m.Tags {
+		removed := false
+		for _, r := range tags {
+			if strings.TrimSpace(r) == tag {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, tag)
+		}
+	}
+	m.Tags = kept
+}
+
+// Resolved and closed tickets are no longer worked on
+func (m *Ticket) IsOpen() bool {
+	return m.Status != StatusResolved && m.Status != StatusClosed
+}
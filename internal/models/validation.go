@@ -1,9 +1,58 @@
 package models
 
-func (m *Ticket) ValidateStatus() bool {
-	validStatuses := map[TicketStatus]bool{
-		StatusClosed: true,
-		StatusOpen:   true,
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+var ErrInvalidTicket = errors.New("invalid ticket")
+
+// Longest description accepted, in characters. The create request has the
+// same limit in its binding.
+const MaxDescriptionLength = 4000
+
+// Statuses tells the statuses known to the configured workflow
+type Statuses interface {
+	IsValidStatus(status TicketStatus) bool
+}
+
+var validPriorities = map[TicketPriority]bool{
+	PriorityLow:    true,
+	PriorityMedium: true,
+	PriorityHigh:   true,
+	PriorityUrgent: true,
+}
+
+var validSeverities = map[TicketSeverity]bool{
+	SeverityCritical: true,
+	SeverityMajor:    true,
+	SeverityMinor:    true,
+	SeverityTrivial:  true,
+}
+
+var validCategories = map[TicketCategory]bool{
+	CategoryIncident:       true,
+	CategoryServiceRequest: true,
+	CategoryProblem:        true,
+	CategoryQuestion:       true,
+}
+
+// ValidateFields checks the description is set, the priority, severity and
+// category are known and the tags valid. The status is left to the workflow.
+func (m *Ticket) ValidateFields() error {
+	switch {
+	case m.Description == "" || utf8.RuneCountInString(m.Description) > MaxDescriptionLength:
+		return fmt.Errorf("%w - description must have 1 to %d characters", ErrInvalidTicket, MaxDescriptionLength)
+	case m.Priority != "" && !m.Priority.Valid():
+		return fmt.Errorf("%w - unknown priority %s", ErrInvalidTicket, m.Priority)
+	case m.Severity != "" && !m.Severity.Valid():
+		return fmt.Errorf("%w - unknown severity %s", ErrInvalidTicket, m.Severity)
+	case m.Category != "" && !m.Category.Valid():
+		return fmt.Errorf("%w - unknown category %s", ErrInvalidTicket, m.Category)
+	}
+	if err := ValidateTags(m.Tags); err != nil {
+		return fmt.Errorf("%w - %w", ErrInvalidTicket, err)
 	}
-	return validStatuses[m.Status]
+	return nil
 }
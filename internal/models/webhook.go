@@ -0,0 +1,52 @@
+package models
+
+// WebhookSubscription registers a URL for some event types of a tenant. The
+// secret signs the deliveries, it is never returned by the API.
+type WebhookSubscription struct {
+	WebhookID    string      `json:"id" dynamodbav:"webhook_id"`
+	URL          string      `json:"url" dynamodbav:"url"`
+	Secret       string      `json:"-" dynamodbav:"secret"`
+	Events       []EventType `json:"events" dynamodbav:"events"`
+	RegisteredBy string      `json:"registeredBy" dynamodbav:"registeredBy"`
+	RegisteredAt string      `json:"registeredAt" dynamodbav:"registeredAt"`
+}
+
+func (s *WebhookSubscription) Subscribes(eventType EventType) bool {
+	for _, subscribed := range s.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookSubscriptionDbRecord struct {
+	WebhookSubscription
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+// WebhookDeadLetter keeps a delivery that failed every attempt, with the
+// exact payload that was sent
+type WebhookDeadLetter struct {
+	DeadLetterID string    `json:"id" dynamodbav:"dead_letter_id"`
+	WebhookID    string    `json:"webhookId" dynamodbav:"webhook_id"`
+	URL          string    `json:"url" dynamodbav:"url"`
+	EventID      string    `json:"eventId" dynamodbav:"event_id"`
+	EventType    EventType `json:"eventType" dynamodbav:"eventType"`
+	Payload      string    `json:"payload" dynamodbav:"payload"`
+	Attempts     int       `json:"attempts" dynamodbav:"attempts"`
+	LastError    string    `json:"lastError" dynamodbav:"lastError"`
+	FailedAt     string    `json:"failedAt" dynamodbav:"failedAt"`
+}
+
+type WebhookDeadLetterDbRecord struct {
+	WebhookDeadLetter
+	PK string `dynamodbav:"PK"`
+	SK string `dynamodbav:"SK"`
+}
+
+type WebhookDeadLetterPage struct {
+	DeadLetters []WebhookDeadLetter
+	NextCursor  string
+}
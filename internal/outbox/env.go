@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/webhooks"
+)
+
+// NewSinkFromEnv picks the sink named by OUTBOX_SINK: "webhooks", the
+// default, posts the events to the webhook subscribers and "file" appends
+// them to the file named by OUTBOX_FILE.
+func NewSinkFromEnv(repos repositories.Repositories) (Sink, error) {
+	switch sink := os.Getenv("OUTBOX_SINK"); sink {
+	case "", "webhooks":
+		return webhooks.NewNotifier(repos.Webhooks), nil
+	case "file":
+		path := os.Getenv("OUTBOX_FILE")
+		if path == "" {
+			path = "outbox.ndjson"
+		}
+		return NewFileSink(path)
+	default:
+		return nil, fmt.Errorf("unknown outbox sink %q", sink)
+	}
+}
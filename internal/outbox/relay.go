@@ -0,0 +1,127 @@
+// Package outbox relays the events the repositories commit together with
+// the changes that raise them. Delivery is at least once: an entry is only
+// removed after its sink accepted it, so a relay stopped in between sends it
+// again. Events of the same ticket are delivered in order.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+)
+
+// Entries read per repository call
+const batchSize = 100
+
+var ErrSubjectsBlocked = errors.New("outbox events could not be delivered")
+
+// Sink receives the relayed events. An error leaves the event in the outbox
+// to be sent again on the next pass.
+type Sink interface {
+	Send(ctx context.Context, event models.Event) error
+}
+
+type Relay struct {
+	outbox repositories.OutboxRepository
+	sink   Sink
+}
+
+func NewRelay(outbox repositories.OutboxRepository, sink Sink) *Relay {
+	return &Relay{
+		outbox: outbox,
+		sink:   sink,
+	}
+}
+
+// Run drains the outbox every interval until ctx is done
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Drain(ctx); err != nil {
+			slog.ErrorContext(ctx, "Failed to relay outbox", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain makes a single pass over the outbox. When the sink rejects an event
+// the later events of its subject are held back until the next pass, so a
+// subject is never delivered out of order.
+func (r *Relay) Drain(ctx context.Context) error {
+	blocked := map[string]bool{}
+	after := ""
+	for {
+		entries, err := r.outbox.ListOutbox(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Key
+		orderBySequence(entries)
+
+		for _, entry := range entries {
+			subject := subjectKey(&entry.Event)
+			if blocked[subject] {
+				continue
+			}
+			if err := r.sink.Send(ctx, entry.Event); err != nil {
+				slog.WarnContext(ctx, "Outbox event not delivered", "eventId", entry.Event.ID, "type", entry.Event.Type, "subject", entry.Event.Subject, "error", err)
+				blocked[subject] = true
+				continue
+			}
+			if err := r.outbox.DeleteOutboxEntry(ctx, entry.Key); err != nil {
+				return err
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf("%w - %d subjects held back", ErrSubjectsBlocked, len(blocked))
+	}
+	return nil
+}
+
+func subjectKey(event *models.Event) string {
+	return event.Tenant + "/" + event.Subject
+}
+
+// Entries come in commit order, which follows the clocks of the writers.
+// Within a batch the events of each subject are reordered by sequence, in
+// the positions the subject already occupies.
+func orderBySequence(entries []models.OutboxEntry) {
+	positions := map[string][]int{}
+	for i := range entries {
+		subject := subjectKey(&entries[i].Event)
+		positions[subject] = append(positions[subject], i)
+	}
+	for _, indexes := range positions {
+		if len(indexes) < 2 {
+			continue
+		}
+		subjectEntries := make([]models.OutboxEntry, len(indexes))
+		for i, index := range indexes {
+			subjectEntries[i] = entries[index]
+		}
+		sort.SliceStable(subjectEntries, func(i, j int) bool {
+			return subjectEntries[i].Event.Sequence < subjectEntries[j].Event.Sequence
+		})
+		for i, index := range indexes {
+			entries[index] = subjectEntries[i]
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func entry(key string, subject string, sequence int64) models.OutboxEntry {
+	return models.OutboxEntry{
+		Key: key,
+		Event: models.Event{
+			ID:       key,
+			Type:     models.EventTicketStatusChanged,
+			Tenant:   "acme",
+			Subject:  subject,
+			Sequence: sequence,
+		},
+	}
+}
+
+func TestOrderBySequence(t *testing.T) {
+	entries := []models.OutboxEntry{
+		entry("1", "a", 3),
+		entry("2", "b", 1),
+		entry("3", "a", 2),
+		entry("4", "a", 4),
+		entry("5", "b", 2),
+	}
+	orderBySequence(entries)
+
+	var keys []string
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	assert.Equal(t, []string{"3", "2", "1", "4", "5"}, keys)
+}
+
+func TestDrain(t *testing.T) {
+	entries := []models.OutboxEntry{
+		entry("1", "a", 1),
+		entry("2", "b", 1),
+		entry("3", "a", 2),
+		entry("4", "b", 2),
+	}
+	outbox := repositories.NewMockOutboxRepository(t)
+	outbox.On("ListOutbox", mock.Anything, "", batchSize).Return(entries, nil).Once()
+	outbox.On("ListOutbox", mock.Anything, "4", batchSize).Return(nil, nil).Once()
+	outbox.On("DeleteOutboxEntry", mock.Anything, "1").Return(nil).Once()
+	outbox.On("DeleteOutboxEntry", mock.Anything, "3").Return(nil).Once()
+
+	sink := NewMockSink(t)
+	sink.On("Send", mock.Anything, entries[0].Event).Return(nil).Once()
+	sink.On("Send", mock.Anything, entries[1].Event).Return(errors.New("unavailable")).Once()
+	sink.On("Send", mock.Anything, entries[2].Event).Return(nil).Once()
+
+	err := NewRelay(outbox, sink).Drain(context.Background())
+	assert.ErrorIs(t, err, ErrSubjectsBlocked)
+	// the second event of b stays in the outbox behind the first one
+	sink.AssertNotCalled(t, "Send", mock.Anything, entries[3].Event)
+}
+
+func TestDrainWithMemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	_, err := repo.CreateTicket(ctx, &models.Ticket{Description: "description", Status: models.StatusOpen})
+	require.NoError(t, err)
+
+	sink := NewChannelSink(10)
+	require.NoError(t, NewRelay(repo, sink).Drain(ctx))
+
+	event := <-sink.Events()
+	assert.Equal(t, models.EventTicketCreated, event.Type)
+	left, err := repo.ListOutbox(ctx, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, left)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.ndjson")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), entry("1", "a", 1).Event))
+	require.NoError(t, sink.Send(context.Background(), entry("2", "a", 2).Event))
+	require.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event models.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"1", "2"}, ids)
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"example.com/ticket-system/internal/models"
+)
+
+// ChannelSink hands the events to a consumer in the same process
+type ChannelSink struct {
+	events chan models.Event
+}
+
+func NewChannelSink(size int) *ChannelSink {
+	return &ChannelSink{
+		events: make(chan models.Event, size),
+	}
+}
+
+func (s *ChannelSink) Events() <-chan models.Event {
+	return s.events
+}
+
+// Send blocks while the channel is full
+func (s *ChannelSink) Send(ctx context.Context, event models.Event) error {
+	select {
+	case s.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FileSink appends the events to a file, one JSON document per line
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Send returns once the line is synced, the entry is removed from the
+// outbox right after
+func (s *FileSink) Send(ctx context.Context, event models.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrReservingTurns = errors.New("error reserving assignment turns")
+
+// Counters of the round-robin assignment, one item per key in the
+// #assignment partition of the tenant. They are shared by every instance of
+// the API so turns are not handed out twice.
+type AssignmentRepository interface {
+	// Reserves n consecutive turns of the key and returns the first one,
+	// turns start at 0
+	ReserveAssignmentTurns(ctx context.Context, key string, n int) (int64, error)
+}
+
+func assignmentPK(ctx context.Context) string {
+	return fmt.Sprintf("%s#assignment", tenantPrefix(ctx))
+}
+
+func (tr *ticketRepository) ReserveAssignmentTurns(ctx context.Context, key string, n int) (int64, error) {
+	result, err := tr.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: assignmentPK(ctx)},
+			"SK": &types.AttributeValueMemberS{Value: key},
+		},
+		UpdateExpression: aws.String("ADD turns :n"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":n": &types.AttributeValueMemberN{Value: strconv.Itoa(n)},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w - %w", ErrReservingTurns, err)
+	}
+	turns, ok := result.Attributes["turns"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("%w - missing counter", ErrReservingTurns)
+	}
+	last, err := strconv.ParseInt(turns.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w - %w", ErrReservingTurns, err)
+	}
+	return last - int64(n), nil
+}
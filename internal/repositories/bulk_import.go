@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// BatchWriteItem accepts at most 25 requests
+	maxBatchWriteItems = 25
+
+	maxBatchAttempts = 5
+	baseBackoff      = 50 * time.Millisecond
+	maxBackoff       = 2 * time.Second
+)
+
+// ErrTicketExists is the reason reported for an imported ticket whose id is
+// already taken, the existing ticket is left as it is
+var ErrTicketExists = conflict("ticket_exists", "ticket already exists")
+
+type batchWriter interface {
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+type transactWriter interface {
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
+func (tr *ticketRepository) BulkImport(ctx context.Context, entries []models.Ticket) ([]models.BulkWriteFailure, error) {
+	failures := []models.BulkWriteFailure{}
+	for i := range entries {
+		transaction, err := importTransaction(ctx, &entries[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := writeTransaction(ctx, tr.client, transaction); err != nil {
+			slog.ErrorContext(ctx, "Error importing ticket", "error", err, "ticketID", entries[i].TicketID)
+			failures = append(failures, models.BulkWriteFailure{TicketID: entries[i].TicketID, Reason: err.Error()})
+		}
+	}
+	return failures, nil
+}
+
+// Every imported ticket is written along with its history entry in one
+// transaction. The condition keeps an import from replacing an existing
+// ticket.
+func importTransaction(ctx context.Context, entry *models.Ticket) (*dynamodb.TransactWriteItemsInput, error) {
+	item, err := attributevalue.MarshalMap(newTicketDbRecord(ctx, entry))
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling record")
+	}
+	history, err := historyPut(ctx, models.ActionImported, nil, entry)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling record")
+	}
+	return &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:           aws.String(TableName),
+				Item:                item,
+				ConditionExpression: aws.String("attribute_not_exists(PK)"),
+			}},
+			{Put: history},
+		},
+	}, nil
+}
+
+// Writes a transaction, retrying with exponential backoff while it is
+// cancelled by conflicting writes. A failed condition is ErrTicketExists.
+func writeTransaction(ctx context.Context, client transactWriter, transaction *dynamodb.TransactWriteItemsInput) error {
+	var err error
+	for attempt := 0; attempt < maxBatchAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, backoff(attempt)); err != nil {
+				return err
+			}
+		}
+		_, err = client.TransactWriteItems(ctx, transaction)
+		switch {
+		case err == nil:
+			return nil
+		case isConditionFailure(err):
+			return ErrTicketExists
+		case !isTransactionConflict(err):
+			return fmt.Errorf("transaction failed: %w", err)
+		}
+		slog.WarnContext(ctx, "Retrying conflicting transaction", "attempt", attempt+1)
+	}
+	return fmt.Errorf("not written after %d attempts: %w", maxBatchAttempts, err)
+}
+
+// Reports whether a transaction was cancelled by another one writing the
+// same items, which is worth retrying
+func isTransactionConflict(err error) bool {
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "TransactionConflict" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Writes a batch, retrying UnprocessedItems with exponential backoff.
+// Returns the requests still unprocessed after the last attempt, along with
+// the error of ctx when it is done before they could be retried.
+func writeBatch(ctx context.Context, client batchWriter, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	pending := requests
+	for attempt := 0; attempt < maxBatchAttempts && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, backoff(attempt)); err != nil {
+				return pending, err
+			}
+		}
+
+		result, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{
+				TableName: pending,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("batch write failed: %w", err)
+		}
+		pending = result.UnprocessedItems[TableName]
+		if len(pending) > 0 {
+			slog.WarnContext(ctx, "Retrying unprocessed items", "count", len(pending), "attempt", attempt+1)
+		}
+	}
+	return pending, nil
+}
+
+// Full jitter backoff: a random duration up to base * 2^attempt
+func backoff(attempt int) time.Duration {
+	limit := baseBackoff << attempt
+	if limit > maxBackoff {
+		limit = maxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(limit)))
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchWriter leaves the first unprocessed requests of each call
+// unwritten, for as many calls as there are entries in unprocessed
+type fakeBatchWriter struct {
+	unprocessed []int
+	calls       [][]types.WriteRequest
+}
+
+func (f *fakeBatchWriter) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	requests := params.RequestItems[TableName]
+	f.calls = append(f.calls, requests)
+
+	output := &dynamodb.BatchWriteItemOutput{}
+	if len(f.calls) <= len(f.unprocessed) {
+		output.UnprocessedItems = map[string][]types.WriteRequest{
+			TableName: requests[:f.unprocessed[len(f.calls)-1]],
+		}
+	}
+	return output, nil
+}
+
+func putRequests(count int) []types.WriteRequest {
+	requests := make([]types.WriteRequest, count)
+	for i := range requests {
+		requests[i] = types.WriteRequest{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("item#%d", i)},
+		}}}
+	}
+	return requests
+}
+
+func TestWriteBatchRetriesUnprocessedItems(t *testing.T) {
+	writer := &fakeBatchWriter{unprocessed: []int{4, 1}}
+	pending, err := writeBatch(context.Background(), writer, putRequests(6))
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	require.Len(t, writer.calls, 3)
+	assert.Len(t, writer.calls[1], 4)
+	assert.Len(t, writer.calls[2], 1)
+}
+
+func TestWriteBatchReportsItemsLeftUnprocessed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the context is cancelled during the backoff, leaving the items pending
+	cancel()
+	writer := &fakeBatchWriter{unprocessed: []int{3}}
+	pending, err := writeBatch(ctx, writer, putRequests(4))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, pending, 3)
+}
+
+// fakeTransactWriter cancels the transactions with the reasons in
+// cancellations, one call each, then lets them through
+type fakeTransactWriter struct {
+	cancellations [][]string
+	calls         int
+}
+
+func (f *fakeTransactWriter) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	f.calls++
+	if f.calls > len(f.cancellations) {
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	}
+	canceled := &types.TransactionCanceledException{}
+	for _, code := range f.cancellations[f.calls-1] {
+		canceled.CancellationReasons = append(canceled.CancellationReasons, types.CancellationReason{Code: aws.String(code)})
+	}
+	return nil, canceled
+}
+
+func TestImportTransaction(t *testing.T) {
+	transaction, err := importTransaction(context.Background(), &models.Ticket{TicketID: "1"})
+	require.NoError(t, err)
+
+	// the ticket and its history entry are written together, the ticket
+	// only when its id is free
+	require.Len(t, transaction.TransactItems, 2)
+	assert.Equal(t, "attribute_not_exists(PK)", aws.ToString(transaction.TransactItems[0].Put.ConditionExpression))
+	assert.NotNil(t, transaction.TransactItems[1].Put)
+}
+
+func TestWriteTransaction(t *testing.T) {
+	ctx := context.Background()
+	transaction, err := importTransaction(ctx, &models.Ticket{TicketID: "1"})
+	require.NoError(t, err)
+
+	t.Run("retries conflicts", func(t *testing.T) {
+		writer := &fakeTransactWriter{cancellations: [][]string{{"TransactionConflict", "None"}}}
+		assert.NoError(t, writeTransaction(ctx, writer, transaction))
+		assert.Equal(t, 2, writer.calls)
+	})
+
+	t.Run("existing ticket", func(t *testing.T) {
+		writer := &fakeTransactWriter{cancellations: [][]string{{"ConditionalCheckFailed", "None"}}}
+		assert.ErrorIs(t, writeTransaction(ctx, writer, transaction), ErrTicketExists)
+		assert.Equal(t, 1, writer.calls)
+	})
+
+	t.Run("too many conflicts", func(t *testing.T) {
+		var cancellations [][]string
+		for i := 0; i < maxBatchAttempts; i++ {
+			cancellations = append(cancellations, []string{"TransactionConflict"})
+		}
+		writer := &fakeTransactWriter{cancellations: cancellations}
+		err := writeTransaction(ctx, writer, transaction)
+		var canceled *types.TransactionCanceledException
+		assert.True(t, errors.As(err, &canceled))
+		assert.Equal(t, maxBatchAttempts, writer.calls)
+	})
+}
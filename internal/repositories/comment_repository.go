@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+const (
+	commentSKPrefix = "comment#"
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// a comment recording the first response races with the other ticket
+	// writes, it is retried this many times
+	commentAttempts = 3
+)
+
+var (
+	ErrCommentNotFound = notFound("comment_not_found", "comment not found")
+	ErrSavingComment   = errors.New("error saving comment")
+	ErrLoadingComments = errors.New("error loading comments")
+)
+
+// Comments are stored under the ticket partition with a
+// comment#<timestamp>#<id> sort key, so a query returns them in order.
+type CommentRepository interface {
+	AddComment(ctx context.Context, comment *models.Comment) (string, error)
+	ListComments(ctx context.Context, ticketID string, page models.PageRequest) (*models.CommentPage, error)
+	UpdateComment(ctx context.Context, ticketID string, commentID string, body string) (*models.Comment, error)
+	DeleteComment(ctx context.Context, ticketID string, commentID string) error
+}
+
+func commentSK(comment *models.Comment) string {
+	return fmt.Sprintf("%s%s#%s", commentSKPrefix, comment.CreatedAt, comment.CommentID)
+}
+
+func pageSize(page models.PageRequest) int32 {
+	if page.Limit <= 0 {
+		return defaultPageSize
+	}
+	if page.Limit > maxPageSize {
+		return maxPageSize
+	}
+	return int32(page.Limit)
+}
+
+// Adds a comment to an existing ticket and returns the comment id. The
+// first comment by someone other than the requester is the response the SLA
+// waits for, it is recorded on the ticket in the same transaction.
+func (tr *ticketRepository) AddComment(ctx context.Context, comment *models.Comment) (string, error) {
+	for attempt := 1; ; attempt++ {
+		err := tr.addComment(ctx, comment)
+		if errors.Is(err, ErrVersionConflict) && attempt < commentAttempts {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return comment.CommentID, nil
+	}
+}
+
+func (tr *ticketRepository) addComment(ctx context.Context, comment *models.Comment) error {
+	ticket, err := tr.GetTicket(ctx, comment.TicketID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	comment.CommentID = uuid.NewString()
+	comment.CreatedAt = models.FormatSortableTime(now)
+	record := models.CommentDbRecord{
+		Comment: *comment,
+		PK:      ticketPK(ctx, comment.TicketID),
+		SK:      commentSK(comment),
+	}
+	item, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+
+	event, err := newEvent(ctx, models.EventCommentAdded, comment.TicketID, ticket.Version, models.CommentEventData{Comment: comment})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+	outbox, err := outboxPut(event)
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+
+	items := []types.TransactWriteItem{
+		{Put: &types.Put{TableName: aws.String(TableName), Item: item}},
+		{Put: outbox},
+	}
+	if comment.Author != ticket.CreatedBy && ticket.SLAResponded(now) {
+		put, _, err := ticketPut(ctx, ticket)
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingComment, err)
+		}
+		items = append(items, types.TransactWriteItem{Put: put})
+	}
+
+	_, err = tr.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrVersionConflict, ticket.TicketID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) ListComments(ctx context.Context, ticketID string, page models.PageRequest) (*models.CommentPage, error) {
+	startKey, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tr.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: ticketPK(ctx, ticketID)},
+			":prefix": &types.AttributeValueMemberS{Value: commentSKPrefix},
+		},
+		ScanIndexForward:  aws.Bool(!page.Descending),
+		Limit:             aws.Int32(pageSize(page)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingComments, err)
+	}
+
+	commentPage := &models.CommentPage{Comments: []models.Comment{}}
+	for _, item := range result.Items {
+		var record models.CommentDbRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingComments, err)
+		}
+		commentPage.Comments = append(commentPage.Comments, record.Comment)
+	}
+	commentPage.NextCursor, err = encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingComments, err)
+	}
+	return commentPage, nil
+}
+
+func (tr *ticketRepository) UpdateComment(ctx context.Context, ticketID string, commentID string, body string) (*models.Comment, error) {
+	record, err := tr.findComment(ctx, ticketID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tr.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: record.PK},
+			"SK": &types.AttributeValueMemberS{Value: record.SK},
+		},
+		UpdateExpression:    aws.String("SET #body = :body, updatedAt = :updatedAt"),
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+		ExpressionAttributeNames: map[string]string{
+			"#body": "body",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":body":      &types.AttributeValueMemberS{Value: body},
+			":updatedAt": &types.AttributeValueMemberS{Value: models.FormatSortableTime(time.Now())},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil, fmt.Errorf("%w - %s", ErrCommentNotFound, commentID)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateComment", "error", err)
+		return nil, fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+
+	var updated models.CommentDbRecord
+	if err := attributevalue.UnmarshalMap(result.Attributes, &updated); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+	return &updated.Comment, nil
+}
+
+func (tr *ticketRepository) DeleteComment(ctx context.Context, ticketID string, commentID string) error {
+	record, err := tr.findComment(ctx, ticketID, commentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: record.PK},
+			"SK": &types.AttributeValueMemberS{Value: record.SK},
+		},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteComment", "error", err)
+		return fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+	return nil
+}
+
+// The sort key embeds the creation time, so comments are located by
+// filtering the ticket partition on the comment id
+func (tr *ticketRepository) findComment(ctx context.Context, ticketID string, commentID string) (*models.CommentDbRecord, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		FilterExpression:       aws.String("comment_id = :commentId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":        &types.AttributeValueMemberS{Value: ticketPK(ctx, ticketID)},
+			":prefix":    &types.AttributeValueMemberS{Value: commentSKPrefix},
+			":commentId": &types.AttributeValueMemberS{Value: commentID},
+		},
+	}
+
+	paginator := dynamodb.NewQueryPaginator(tr.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingComments, err)
+		}
+		if len(result.Items) > 0 {
+			var record models.CommentDbRecord
+			if err := attributevalue.UnmarshalMap(result.Items[0], &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrLoadingComments, err)
+			}
+			return &record, nil
+		}
+	}
+	return nil, fmt.Errorf("%w - %s", ErrCommentNotFound, commentID)
+}
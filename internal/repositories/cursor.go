@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrInvalidCursor = invalid("invalid_cursor", "cursor", "invalid pagination cursor")
+
+// Pagination cursors are the LastEvaluatedKey of a query, base64 encoded so
+// callers treat them as opaque. Key attributes are always strings.
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+	values := make(map[string]string, len(key))
+	for name, value := range key {
+		s, ok := value.(*types.AttributeValueMemberS)
+		if !ok {
+			return "", ErrInvalidCursor
+		}
+		values[name] = s.Value
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil || len(values) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	key := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		key[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return key, nil
+}
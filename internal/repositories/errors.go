@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+)
+
+// Kinds of failure the callers of the repositories tell apart. Every Error
+// is of one kind, errors.Is(err, ErrNotFound) holds for a missing ticket as
+// well as a missing team.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// ErrStorageUnavailable stands for any failed call to DynamoDB
+var ErrStorageUnavailable = &Error{Kind: ErrUnavailable, Code: "storage_unavailable", Message: "storage unavailable"}
+
+// Error is a failure of a kind, with a code that does not change with the
+// wording of the message
+type Error struct {
+	Kind error
+	Code string
+	// the field of the request at fault, for validation failures
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func notFound(code string, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func conflict(code string, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func invalid(code string, field string, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Field: field, Message: message}
+}
+
+// AsError returns the outermost Error wrapped by err. A failed DynamoDB call
+// the repositories did not recognize is ErrStorageUnavailable. Anything else
+// is a fault of the service itself.
+func AsError(err error) (*Error, bool) {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed, true
+	}
+	var operation *smithy.OperationError
+	if errors.As(err, &operation) {
+		return ErrStorageUnavailable, true
+	}
+	return nil, false
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsError(t *testing.T) {
+	unavailable := &smithy.OperationError{ServiceID: "DynamoDB", OperationName: "GetItem", Err: &types.InternalServerError{}}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected *Error
+	}{
+		{"sentinel", ErrVersionConflict, ErrVersionConflict},
+		{"wrapped behind a plain error", fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound), ErrTicketNotFound},
+		{"outermost wins", fmt.Errorf("%w - %w", ErrUnknownQueue, ErrQueueNotFound), ErrUnknownQueue},
+		{"failed DynamoDB call", fmt.Errorf("%w - %w", ErrLoadingTicket, unavailable), ErrStorageUnavailable},
+		{"anything else", ErrLoadingTicket, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typed, ok := AsError(tt.err)
+			assert.Equal(t, tt.expected != nil, ok)
+			assert.Same(t, tt.expected, typed)
+		})
+	}
+
+	assert.ErrorIs(t, fmt.Errorf("%w - %s", ErrTeamNotFound, "support"), ErrNotFound)
+	assert.NotErrorIs(t, ErrTeamNotFound, ErrQueueNotFound)
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+const historySKPrefix = "history#"
+
+var ErrLoadingHistory = errors.New("error loading ticket history")
+
+// History entries are written by TicketRepository in the same transaction as
+// the change they describe, under the ticket partition with a
+// history#<timestamp>#<id> sort key. They are never updated.
+type HistoryRepository interface {
+	GetTicketHistory(ctx context.Context, ticketID string, page models.PageRequest) (*models.HistoryPage, error)
+}
+
+func historySK(entry *models.HistoryEntry) string {
+	return fmt.Sprintf("%s%s#%s", historySKPrefix, entry.Timestamp, entry.EntryID)
+}
+
+func newHistoryEntry(ctx context.Context, action string, before *models.Ticket, after *models.Ticket) models.HistoryEntry {
+	return models.HistoryEntry{
+		EntryID:   uuid.NewString(),
+		TicketID:  after.TicketID,
+		Action:    action,
+		Actor:     identity.Actor(ctx),
+		Timestamp: models.FormatSortableTime(time.Now()),
+		Changes:   models.DiffTickets(before, after),
+	}
+}
+
+// Builds the put for a new history entry. The condition keeps existing
+// entries from being overwritten.
+func historyPut(ctx context.Context, action string, before *models.Ticket, after *models.Ticket) (*types.Put, error) {
+	entry := newHistoryEntry(ctx, action, before, after)
+	record := models.HistoryDbRecord{
+		HistoryEntry: entry,
+		PK:           ticketPK(ctx, entry.TicketID),
+		SK:           historySK(&entry),
+	}
+	item, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Put{
+		TableName:           aws.String(TableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}, nil
+}
+
+func (tr *ticketRepository) GetTicketHistory(ctx context.Context, ticketID string, page models.PageRequest) (*models.HistoryPage, error) {
+	startKey, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tr.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: ticketPK(ctx, ticketID)},
+			":prefix": &types.AttributeValueMemberS{Value: historySKPrefix},
+		},
+		ScanIndexForward:  aws.Bool(!page.Descending),
+		Limit:             aws.Int32(pageSize(page)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingHistory, err)
+	}
+
+	historyPage := &models.HistoryPage{Entries: []models.HistoryEntry{}}
+	for _, item := range result.Items {
+		var record models.HistoryDbRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingHistory, err)
+		}
+		historyPage.Entries = append(historyPage.Entries, record.HistoryEntry)
+	}
+	historyPage.NextCursor, err = encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingHistory, err)
+	}
+	return historyPage, nil
+}
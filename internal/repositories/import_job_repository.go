@@ -0,0 +1,300 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	importChunkSKPrefix = "chunk#"
+	importLineSKPrefix  = "line#"
+)
+
+var (
+	ErrImportJobNotFound = notFound("import_job_not_found", "import job not found")
+	ErrSavingImportJob   = errors.New("error saving import job")
+	ErrLoadingImportJob  = errors.New("error loading import job")
+)
+
+// Import jobs live under their own #import#<id> partition of the tenant: a details item
+// with the counters, one item per chunk of entries to write, and one item
+// per rejected or failed line for the error report.
+type ImportJobRepository interface {
+	CreateImportJob(ctx context.Context, job *models.ImportJob, chunks []models.ImportChunk, rejected []models.ImportLineResult) error
+	GetImportJob(ctx context.Context, jobID string) (*models.ImportJob, error)
+	GetImportChunk(ctx context.Context, jobID string, index int) (*models.ImportChunk, error)
+	// Records the outcome of a chunk and updates the job counters. A chunk
+	// is only counted once, repeated calls are ignored. The call processing
+	// the last chunk completes the job and raises bulk_import.completed.
+	CompleteImportChunk(ctx context.Context, jobID string, index int, results []models.ImportLineResult) error
+	ListImportErrors(ctx context.Context, jobID string) ([]models.ImportLineResult, error)
+}
+
+func importJobPK(ctx context.Context, jobID string) string {
+	return fmt.Sprintf("%s#import#%s", tenantPrefix(ctx), jobID)
+}
+
+func importChunkSK(index int) string {
+	return fmt.Sprintf("%s%05d", importChunkSKPrefix, index)
+}
+
+func importLineSK(line int) string {
+	return fmt.Sprintf("%s%08d", importLineSKPrefix, line)
+}
+
+func importLineItem(ctx context.Context, jobID string, result models.ImportLineResult) (map[string]types.AttributeValue, error) {
+	return attributevalue.MarshalMap(models.ImportLineDbRecord{
+		ImportLineResult: result,
+		JobID:            jobID,
+		PK:               importJobPK(ctx, jobID),
+		SK:               importLineSK(result.Line),
+	})
+}
+
+func (tr *ticketRepository) CreateImportJob(ctx context.Context, job *models.ImportJob, chunks []models.ImportChunk, rejected []models.ImportLineResult) error {
+	job.Chunks = len(chunks)
+	job.RemainingChunks = len(chunks)
+
+	var requests []types.WriteRequest
+	for _, chunk := range chunks {
+		item, err := attributevalue.MarshalMap(models.ImportChunkDbRecord{
+			ImportChunk: chunk,
+			PK:          importJobPK(ctx, job.JobID),
+			SK:          importChunkSK(chunk.Index),
+		})
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+	for _, result := range rejected {
+		item, err := importLineItem(ctx, job.JobID, result)
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+
+	for i := 0; i < len(requests); i += maxBatchWriteItems {
+		end := i + maxBatchWriteItems
+		if end > len(requests) {
+			end = len(requests)
+		}
+		unprocessed, err := writeBatch(ctx, tr.client, requests[i:end])
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		if len(unprocessed) > 0 {
+			return fmt.Errorf("%w - %d items not written", ErrSavingImportJob, len(unprocessed))
+		}
+	}
+
+	// the job is written last so it is never visible without its chunks. A
+	// job without chunks is complete right away and raises its event.
+	item, err := attributevalue.MarshalMap(models.ImportJobDbRecord{
+		ImportJob: *job,
+		PK:        importJobPK(ctx, job.JobID),
+		SK:        "details",
+	})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+	}
+	items := []types.TransactWriteItem{{Put: &types.Put{TableName: aws.String(TableName), Item: item}}}
+	if job.Status == models.ImportJobCompleted {
+		outbox, err := importCompletedPut(ctx, job)
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		items = append(items, types.TransactWriteItem{Put: outbox})
+	}
+	_, err = tr.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+	}
+	return nil
+}
+
+func importCompletedPut(ctx context.Context, job *models.ImportJob) (*types.Put, error) {
+	event, err := newEvent(ctx, models.EventBulkImportCompleted, job.JobID, 0, models.ImportJobEventData{Job: job})
+	if err != nil {
+		return nil, err
+	}
+	return outboxPut(event)
+}
+
+func (tr *ticketRepository) GetImportJob(ctx context.Context, jobID string) (*models.ImportJob, error) {
+	result, err := tr.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(TableName),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: importJobPK(ctx, jobID)},
+			"SK": &types.AttributeValueMemberS{Value: "details"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingImportJob, err)
+	}
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w - %s", ErrImportJobNotFound, jobID)
+	}
+
+	var record models.ImportJobDbRecord
+	if err := attributevalue.UnmarshalMap(result.Item, &record); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingImportJob, err)
+	}
+	return &record.ImportJob, nil
+}
+
+func (tr *ticketRepository) GetImportChunk(ctx context.Context, jobID string, index int) (*models.ImportChunk, error) {
+	result, err := tr.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: importJobPK(ctx, jobID)},
+			"SK": &types.AttributeValueMemberS{Value: importChunkSK(index)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingImportJob, err)
+	}
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w - %s chunk %d", ErrImportJobNotFound, jobID, index)
+	}
+
+	var record models.ImportChunkDbRecord
+	if err := attributevalue.UnmarshalMap(result.Item, &record); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingImportJob, err)
+	}
+	return &record.ImportChunk, nil
+}
+
+func (tr *ticketRepository) CompleteImportChunk(ctx context.Context, jobID string, index int, results []models.ImportLineResult) error {
+	report := models.NewImportReport(results)
+	items := []types.TransactWriteItem{
+		{Update: &types.Update{
+			TableName: aws.String(TableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: importJobPK(ctx, jobID)},
+				"SK": &types.AttributeValueMemberS{Value: importChunkSK(index)},
+			},
+			UpdateExpression:    aws.String("SET processed = :true"),
+			ConditionExpression: aws.String("processed = :false"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":true":  &types.AttributeValueMemberBOOL{Value: true},
+				":false": &types.AttributeValueMemberBOOL{Value: false},
+			},
+		}},
+		{Update: &types.Update{
+			TableName: aws.String(TableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: importJobPK(ctx, jobID)},
+				"SK": &types.AttributeValueMemberS{Value: "details"},
+			},
+			UpdateExpression: aws.String("SET jobStatus = :running ADD processed :processed, created :created, failed :failed, remainingChunks :minusOne"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":running":   &types.AttributeValueMemberS{Value: string(models.ImportJobRunning)},
+				":processed": &types.AttributeValueMemberN{Value: strconv.Itoa(len(results))},
+				":created":   &types.AttributeValueMemberN{Value: strconv.Itoa(report.Created)},
+				":failed":    &types.AttributeValueMemberN{Value: strconv.Itoa(report.Failed)},
+				":minusOne":  &types.AttributeValueMemberN{Value: "-1"},
+			},
+		}},
+	}
+	for _, result := range results {
+		if result.Result == models.ImportCreated {
+			continue
+		}
+		item, err := importLineItem(ctx, jobID, result)
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		items = append(items, types.TransactWriteItem{Put: &types.Put{TableName: aws.String(TableName), Item: item}})
+	}
+
+	_, err := tr.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if isConditionFailure(err) {
+		slog.InfoContext(ctx, "Import chunk already processed", "jobId", jobID, "chunk", index)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+	}
+
+	job, err := tr.GetImportJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.RemainingChunks > 0 {
+		return nil
+	}
+	job.Status = models.ImportJobCompleted
+	job.CompletedAt = models.FormatSortableTime(time.Now())
+	outbox, err := importCompletedPut(ctx, job)
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+	}
+	_, err = tr.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName: aws.String(TableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: importJobPK(ctx, jobID)},
+					"SK": &types.AttributeValueMemberS{Value: "details"},
+				},
+				UpdateExpression:    aws.String("SET jobStatus = :completed, completedAt = :now"),
+				ConditionExpression: aws.String("remainingChunks = :zero AND jobStatus <> :completed"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":completed": &types.AttributeValueMemberS{Value: string(models.ImportJobCompleted)},
+					":now":       &types.AttributeValueMemberS{Value: job.CompletedAt},
+					":zero":      &types.AttributeValueMemberN{Value: "0"},
+				},
+			}},
+			{Put: outbox},
+		},
+	})
+	if isConditionFailure(err) {
+		// completed by a concurrent call
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+	}
+	return nil
+}
+
+// Returns the rejected and failed lines of a job, ordered by line number
+func (tr *ticketRepository) ListImportErrors(ctx context.Context, jobID string) ([]models.ImportLineResult, error) {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: importJobPK(ctx, jobID)},
+			":prefix": &types.AttributeValueMemberS{Value: importLineSKPrefix},
+		},
+	})
+
+	results := []models.ImportLineResult{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingImportJob, err)
+		}
+		for _, item := range page.Items {
+			var record models.ImportLineDbRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrLoadingImportJob, err)
+			}
+			results = append(results, record.ImportLineResult)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+)
+
+func (mr *memoryTicketRepository) ReserveAssignmentTurns(ctx context.Context, key string, n int) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	counter := assignmentPK(ctx) + "#" + key
+	first := mr.assignmentTurns[counter]
+	mr.assignmentTurns[counter] = first + int64(n)
+	return first, nil
+}
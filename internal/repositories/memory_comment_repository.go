@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/google/uuid"
+)
+
+// Adds a comment to an existing ticket and returns the comment id. The
+// first comment by someone other than the requester is recorded as the SLA
+// response.
+func (mr *memoryTicketRepository) AddComment(ctx context.Context, comment *models.Comment) (string, error) {
+	ticket, err := mr.GetTicket(ctx, comment.TicketID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	comment.CommentID = uuid.NewString()
+	comment.CreatedAt = models.FormatSortableTime(now)
+	event, err := newEvent(ctx, models.EventCommentAdded, comment.TicketID, ticket.Version, models.CommentEventData{Comment: comment})
+	if err != nil {
+		return "", fmt.Errorf("%w - %w", ErrSavingComment, err)
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	pk := ticketPK(ctx, comment.TicketID)
+	comments := append(mr.comments[pk], *comment)
+	sort.Slice(comments, func(i, j int) bool {
+		return commentSK(&comments[i]) < commentSK(&comments[j])
+	})
+	mr.comments[pk] = comments
+	mr.appendOutbox(event)
+	// the ticket is read again under the lock, it may have changed since
+	if stored, ok := mr.tickets[pk]; ok && comment.Author != stored.CreatedBy && stored.SLAResponded(now) {
+		stored.Version++
+		mr.tickets[pk] = stored
+	}
+	return comment.CommentID, nil
+}
+
+func (mr *memoryTicketRepository) ListComments(ctx context.Context, ticketID string, page models.PageRequest) (*models.CommentPage, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	comments := mr.comments[ticketPK(ctx, ticketID)]
+	sortKeys := make([]string, len(comments))
+	for i := range comments {
+		sortKeys[i] = commentSK(&comments[i])
+	}
+	indexes, nextCursor, err := memoryPage(ticketPK(ctx, ticketID), sortKeys, page)
+	if err != nil {
+		return nil, err
+	}
+
+	commentPage := &models.CommentPage{Comments: []models.Comment{}, NextCursor: nextCursor}
+	for _, i := range indexes {
+		commentPage.Comments = append(commentPage.Comments, comments[i])
+	}
+	return commentPage, nil
+}
+
+func (mr *memoryTicketRepository) UpdateComment(ctx context.Context, ticketID string, commentID string, body string) (*models.Comment, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := ticketPK(ctx, ticketID)
+	for i, comment := range mr.comments[pk] {
+		if comment.CommentID == commentID {
+			comment.Body = body
+			comment.UpdatedAt = models.FormatSortableTime(time.Now())
+			mr.comments[pk][i] = comment
+			return &comment, nil
+		}
+	}
+	return nil, fmt.Errorf("%w - %s", ErrCommentNotFound, commentID)
+}
+
+func (mr *memoryTicketRepository) DeleteComment(ctx context.Context, ticketID string, commentID string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := ticketPK(ctx, ticketID)
+	comments := mr.comments[pk]
+	for i, comment := range comments {
+		if comment.CommentID == commentID {
+			mr.comments[pk] = append(comments[:i:i], comments[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w - %s", ErrCommentNotFound, commentID)
+}
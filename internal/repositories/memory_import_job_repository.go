@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+)
+
+func (mr *memoryTicketRepository) CreateImportJob(ctx context.Context, job *models.ImportJob, chunks []models.ImportChunk, rejected []models.ImportLineResult) error {
+	job.Chunks = len(chunks)
+	job.RemainingChunks = len(chunks)
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	if job.Status == models.ImportJobCompleted {
+		event, err := newEvent(ctx, models.EventBulkImportCompleted, job.JobID, 0, models.ImportJobEventData{Job: job})
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		mr.appendOutbox(event)
+	}
+	pk := importJobPK(ctx, job.JobID)
+	mr.importJobs[pk] = *job
+	mr.importChunks[pk] = append([]models.ImportChunk(nil), chunks...)
+	mr.importErrors[pk] = append([]models.ImportLineResult(nil), rejected...)
+	return nil
+}
+
+func (mr *memoryTicketRepository) GetImportJob(ctx context.Context, jobID string) (*models.ImportJob, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	job, ok := mr.importJobs[importJobPK(ctx, jobID)]
+	if !ok {
+		return nil, fmt.Errorf("%w - %s", ErrImportJobNotFound, jobID)
+	}
+	return &job, nil
+}
+
+func (mr *memoryTicketRepository) GetImportChunk(ctx context.Context, jobID string, index int) (*models.ImportChunk, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	chunks := mr.importChunks[importJobPK(ctx, jobID)]
+	if index < 0 || index >= len(chunks) {
+		return nil, fmt.Errorf("%w - %s chunk %d", ErrImportJobNotFound, jobID, index)
+	}
+	chunk := chunks[index]
+	return &chunk, nil
+}
+
+func (mr *memoryTicketRepository) CompleteImportChunk(ctx context.Context, jobID string, index int, results []models.ImportLineResult) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := importJobPK(ctx, jobID)
+	job, ok := mr.importJobs[pk]
+	chunks := mr.importChunks[pk]
+	if !ok || index < 0 || index >= len(chunks) {
+		return fmt.Errorf("%w - %s chunk %d", ErrImportJobNotFound, jobID, index)
+	}
+	if chunks[index].Processed {
+		return nil
+	}
+
+	report := models.NewImportReport(results)
+	job.Status = models.ImportJobRunning
+	job.Processed += len(results)
+	job.Created += report.Created
+	job.Failed += report.Failed
+	job.RemainingChunks--
+	if job.RemainingChunks == 0 {
+		job.Status = models.ImportJobCompleted
+		job.CompletedAt = models.FormatSortableTime(time.Now())
+		event, err := newEvent(ctx, models.EventBulkImportCompleted, jobID, 0, models.ImportJobEventData{Job: &job})
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrSavingImportJob, err)
+		}
+		mr.appendOutbox(event)
+	}
+	chunks[index].Processed = true
+	mr.importJobs[pk] = job
+
+	for _, result := range results {
+		if result.Result != models.ImportCreated {
+			mr.importErrors[pk] = append(mr.importErrors[pk], result)
+		}
+	}
+	return nil
+}
+
+func (mr *memoryTicketRepository) ListImportErrors(ctx context.Context, jobID string) ([]models.ImportLineResult, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	results := append([]models.ImportLineResult{}, mr.importErrors[importJobPK(ctx, jobID)]...)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+	return results, nil
+}
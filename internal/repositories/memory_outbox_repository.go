@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	models "example.com/ticket-system/internal/models"
+)
+
+func (mr *memoryTicketRepository) ListOutbox(ctx context.Context, after string, limit int) ([]models.OutboxEntry, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	start := sort.Search(len(mr.outbox), func(i int) bool {
+		return mr.outbox[i].Key > after
+	})
+	end := len(mr.outbox)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	return append([]models.OutboxEntry{}, mr.outbox[start:end]...), nil
+}
+
+func (mr *memoryTicketRepository) DeleteOutboxEntry(ctx context.Context, key string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, entry := range mr.outbox {
+		if entry.Key == key {
+			mr.outbox = append(mr.outbox[:i:i], mr.outbox[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// callers must hold the write lock
+func (mr *memoryTicketRepository) appendOutbox(event *models.Event) {
+	mr.outbox = append(mr.outbox, models.OutboxEntry{Key: outboxKey(event), Event: *event})
+	sort.Slice(mr.outbox, func(i, j int) bool {
+		return mr.outbox[i].Key < mr.outbox[j].Key
+	})
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	models "example.com/ticket-system/internal/models"
+)
+
+func (mr *memoryTicketRepository) CreateTeam(ctx context.Context, team *models.Team) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := teamsPK(ctx)
+	if _, ok := mr.teams[pk][team.TeamID]; ok {
+		return fmt.Errorf("%w - %s already exists", ErrSavingTeam, team.TeamID)
+	}
+	if mr.teams[pk] == nil {
+		mr.teams[pk] = make(map[string]models.Team)
+	}
+	mr.teams[pk][team.TeamID] = *team
+	return nil
+}
+
+func (mr *memoryTicketRepository) GetTeam(ctx context.Context, teamID string) (*models.Team, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	team, ok := mr.teams[teamsPK(ctx)][teamID]
+	if !ok {
+		return nil, fmt.Errorf("%w - %s", ErrTeamNotFound, teamID)
+	}
+	return &team, nil
+}
+
+func (mr *memoryTicketRepository) ListTeams(ctx context.Context) ([]models.Team, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	teams := []models.Team{}
+	for _, team := range mr.teams[teamsPK(ctx)] {
+		teams = append(teams, team)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].TeamID < teams[j].TeamID
+	})
+	return teams, nil
+}
+
+func (mr *memoryTicketRepository) UpdateTeam(ctx context.Context, team *models.Team) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := teamsPK(ctx)
+	if _, ok := mr.teams[pk][team.TeamID]; !ok {
+		return fmt.Errorf("%w - %s", ErrTeamNotFound, team.TeamID)
+	}
+	mr.teams[pk][team.TeamID] = *team
+	return nil
+}
+
+func (mr *memoryTicketRepository) DeleteTeam(ctx context.Context, teamID string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := teamsPK(ctx)
+	if _, ok := mr.teams[pk][teamID]; !ok {
+		return fmt.Errorf("%w - %s", ErrTeamNotFound, teamID)
+	}
+	for _, queue := range mr.queues[queuesPK(ctx)] {
+		if queue.TeamID == teamID {
+			return fmt.Errorf("%w - %s", ErrTeamInUse, queue.QueueID)
+		}
+	}
+	delete(mr.teams[pk], teamID)
+	return nil
+}
+
+func (mr *memoryTicketRepository) CreateQueue(ctx context.Context, queue *models.Queue) error {
+	if _, err := mr.GetTeam(ctx, queue.TeamID); err != nil {
+		return err
+	}
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := queuesPK(ctx)
+	if _, ok := mr.queues[pk][queue.QueueID]; ok {
+		return fmt.Errorf("%w - %s already exists", ErrSavingTeam, queue.QueueID)
+	}
+	if mr.queues[pk] == nil {
+		mr.queues[pk] = make(map[string]models.Queue)
+	}
+	mr.queues[pk][queue.QueueID] = *queue
+	return nil
+}
+
+func (mr *memoryTicketRepository) GetQueue(ctx context.Context, queueID string) (*models.Queue, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	queue, ok := mr.queues[queuesPK(ctx)][queueID]
+	if !ok {
+		return nil, fmt.Errorf("%w - %s", ErrQueueNotFound, queueID)
+	}
+	return &queue, nil
+}
+
+func (mr *memoryTicketRepository) ListQueues(ctx context.Context) ([]models.Queue, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	queues := []models.Queue{}
+	for _, queue := range mr.queues[queuesPK(ctx)] {
+		queues = append(queues, queue)
+	}
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].QueueID < queues[j].QueueID
+	})
+	return queues, nil
+}
+
+func (mr *memoryTicketRepository) UpdateQueue(ctx context.Context, queue *models.Queue) error {
+	if _, err := mr.GetTeam(ctx, queue.TeamID); err != nil {
+		return err
+	}
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := queuesPK(ctx)
+	if _, ok := mr.queues[pk][queue.QueueID]; !ok {
+		return fmt.Errorf("%w - %s", ErrQueueNotFound, queue.QueueID)
+	}
+	mr.queues[pk][queue.QueueID] = *queue
+	return nil
+}
+
+func (mr *memoryTicketRepository) DeleteQueue(ctx context.Context, queueID string) error {
+	if err := checkQueueEmpty(ctx, mr, queueID); err != nil {
+		return err
+	}
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := queuesPK(ctx)
+	if _, ok := mr.queues[pk][queueID]; !ok {
+		return fmt.Errorf("%w - %s", ErrQueueNotFound, queueID)
+	}
+	delete(mr.queues[pk], queueID)
+	return nil
+}
@@ -0,0 +1,539 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// memoryTicketRepository implements every repository in memory, keyed by partition key like the table, for local runs and tests
+type memoryTicketRepository struct {
+	mu       sync.RWMutex
+	tickets  map[string]models.Ticket
+	comments map[string][]models.Comment
+	history  map[string][]models.HistoryEntry
+	workflow *workflow.Workflow
+
+	importJobs   map[string]models.ImportJob
+	importChunks map[string][]models.ImportChunk
+	importErrors map[string][]models.ImportLineResult
+
+	webhooks    map[string][]models.WebhookSubscription
+	deadLetters map[string][]models.WebhookDeadLetter
+	// shared by all tenants, ordered by key
+	outbox []models.OutboxEntry
+	// round-robin counters by partition and key
+	assignmentTurns map[string]int64
+	// by partition key, then id
+	teams  map[string]map[string]models.Team
+	queues map[string]map[string]models.Queue
+}
+
+func NewMemoryTicketRepository(wf *workflow.Workflow) *memoryTicketRepository {
+	return &memoryTicketRepository{
+		tickets:  make(map[string]models.Ticket),
+		comments: make(map[string][]models.Comment),
+		history:  make(map[string][]models.HistoryEntry),
+		workflow: wf,
+
+		importJobs:   make(map[string]models.ImportJob),
+		importChunks: make(map[string][]models.ImportChunk),
+		importErrors: make(map[string][]models.ImportLineResult),
+
+		webhooks:    make(map[string][]models.WebhookSubscription),
+		deadLetters: make(map[string][]models.WebhookDeadLetter),
+
+		assignmentTurns: make(map[string]int64),
+		teams:           make(map[string]map[string]models.Team),
+		queues:          make(map[string]map[string]models.Queue),
+	}
+}
+
+// Soft deleted tickets are not found
+func (mr *memoryTicketRepository) GetTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	ticket, err := mr.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.IsDeleted() {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound)
+	}
+	return ticket, nil
+}
+
+// Reads the ticket, soft deleted or not
+func (mr *memoryTicketRepository) getTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	ticket, ok := mr.tickets[ticketPK(ctx, id)]
+	if !ok {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound)
+	}
+	return &ticket, nil
+}
+
+func (mr *memoryTicketRepository) GetTicketsAssignedTo(ctx context.Context, userName string) ([]models.Ticket, error) {
+	slog.InfoContext(ctx, "getTicketAssignedTo", "userName", userName)
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	prefix := ticketPK(ctx, "")
+	var tickets []models.Ticket = []models.Ticket{}
+	for pk, ticket := range mr.tickets {
+		if strings.HasPrefix(pk, prefix) && ticket.AssignedTo == userName && !ticket.IsDeleted() {
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	// the AssignedTo GSI uses createdAt as range key
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt.Time)
+	})
+	return tickets, nil
+}
+
+// Creates a new ticket and returns the ticket id
+func (mr *memoryTicketRepository) CreateTicket(ctx context.Context, ticket *models.Ticket) (string, error) {
+	slog.InfoContext(ctx, "Creating Ticket", "ticket", ticket)
+	if err := checkQueue(ctx, mr, ticket); err != nil {
+		return "", err
+	}
+	if ticket.TicketID == "" {
+		ticket.TicketID = uuid.NewString()
+	}
+	ticket.Version = 1
+	event, err := newTicketEvent(ctx, models.EventTicketCreated, ticket)
+	if err != nil {
+		return "", fmt.Errorf("%w - %w", ErrCreatingTicket, err)
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	mr.tickets[ticketPK(ctx, ticket.TicketID)] = *ticket
+	mr.appendHistory(ctx, newHistoryEntry(ctx, models.ActionCreated, nil, ticket))
+	mr.appendOutbox(event)
+
+	return ticket.TicketID, nil
+}
+
+func (mr *memoryTicketRepository) UpdateStatus(ctx context.Context, id string, status string, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	before := *ticket
+	if err := mr.workflow.Transition(ticket, models.TicketStatus(status)); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidStatus, err)
+	}
+	ticket.SLAStatusChanged(before.Status, time.Now())
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionStatusChanged); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (mr *memoryTicketRepository) UpdateAssignTo(ctx context.Context, id string, assignTo string, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateAssignTo", "error", err)
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	ticket.AssignedTo = assignTo
+	ticket.AssignedBy = ""
+	if err := checkQueue(ctx, mr, ticket); err != nil {
+		return nil, err
+	}
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionAssigned); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// Writes the ticket only if the stored version still matches ticket.Version.
+// On success ticket.Version holds the new version.
+func (mr *memoryTicketRepository) UpdateTicket(ctx context.Context, ticket *models.Ticket) error {
+	if ticket.TicketID == "" {
+		return errors.New("ticket ID required")
+	}
+	before, err := mr.GetTicket(ctx, ticket.TicketID)
+	if err != nil {
+		return err
+	}
+	if ticket.Queue != before.Queue || ticket.AssignedTo != before.AssignedTo {
+		if err := checkQueue(ctx, mr, ticket); err != nil {
+			return err
+		}
+	}
+	return mr.saveTicket(ctx, before, ticket, models.ActionUpdated)
+}
+
+func (mr *memoryTicketRepository) MoveTicketToQueue(ctx context.Context, id string, queueID string, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	if err := moveToQueue(ctx, mr, ticket, queueID); err != nil {
+		return nil, err
+	}
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionQueued); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// The whole ticket is stored, there are no attributes to update separately
+func (mr *memoryTicketRepository) PatchTicket(ctx context.Context, id string, patch []byte, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	patched, action, err := applyTicketPatch(ctx, mr.workflow, mr, ticket, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := mr.saveTicket(ctx, ticket, patched, action); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+func (mr *memoryTicketRepository) DeleteTicket(ctx context.Context, id string, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	markDeleted(ctx, ticket, time.Now())
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionDeleted); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (mr *memoryTicketRepository) RestoreTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	ticket, err := mr.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *ticket
+	if err := markRestored(ticket); err != nil {
+		return nil, err
+	}
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionRestored); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (mr *memoryTicketRepository) PurgeTicket(ctx context.Context, id string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := ticketPK(ctx, id)
+	if _, ok := mr.tickets[pk]; !ok {
+		return fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound)
+	}
+	delete(mr.tickets, pk)
+	delete(mr.comments, pk)
+	delete(mr.history, pk)
+	return nil
+}
+
+func (mr *memoryTicketRepository) UpdatePriority(ctx context.Context, id string, priority models.TicketPriority, expectedVersion int64) (*models.Ticket, error) {
+	if !priority.Valid() {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidPriority, priority)
+	}
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	ticket.Priority = priority
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionPriorityChanged); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (mr *memoryTicketRepository) UpdateTags(ctx context.Context, id string, add []string, remove []string, expectedVersion int64) (*models.Ticket, error) {
+	if err := models.ValidateTags(add); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidTags, err)
+	}
+	ticket, err := mr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	ticket.AddTags(add...)
+	ticket.RemoveTags(remove...)
+	if err := mr.saveTicket(ctx, &before, ticket, models.ActionTagged); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (mr *memoryTicketRepository) ListQueueTickets(ctx context.Context, queueID string) ([]models.Ticket, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	prefix := ticketPK(ctx, "")
+	tickets := []models.Ticket{}
+	for pk, ticket := range mr.tickets {
+		if strings.HasPrefix(pk, prefix) && ticket.Queue == queueID && !ticket.IsDeleted() {
+			tickets = append(tickets, ticket)
+		}
+	}
+	// the Queue GSI uses createdAt as range key
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt.Time)
+	})
+	return tickets, nil
+}
+
+// Writes the ticket, the history entry describing the change from before
+// and the event of the action if it has one
+func (mr *memoryTicketRepository) saveTicket(ctx context.Context, before *models.Ticket, ticket *models.Ticket, action string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	pk := ticketPK(ctx, ticket.TicketID)
+	stored, ok := mr.tickets[pk]
+	if !ok || stored.Version != ticket.Version {
+		return fmt.Errorf("%w - %s", ErrVersionConflict, ticket.TicketID)
+	}
+	ticket.Version++
+	var event *models.Event
+	if eventType, ok := ticketActionEvent(action); ok {
+		var err error
+		if event, err = newTicketEvent(ctx, eventType, ticket); err != nil {
+			ticket.Version--
+			return fmt.Errorf("failed to marshal ticket event: %w", err)
+		}
+	}
+	mr.tickets[pk] = *ticket
+	mr.appendHistory(ctx, newHistoryEntry(ctx, action, before, ticket))
+	if event != nil {
+		mr.appendOutbox(event)
+	}
+	return nil
+}
+
+func (mr *memoryTicketRepository) ListTicketsDueBefore(ctx context.Context, before time.Time) ([]models.Ticket, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	prefix := ticketPK(ctx, "")
+	deadline := models.FormatSortableTime(before)
+	tickets := []models.Ticket{}
+	for pk, ticket := range mr.tickets {
+		if !strings.HasPrefix(pk, prefix) || ticket.SLA == nil || ticket.IsDeleted() {
+			continue
+		}
+		if due := ticket.SLA.NextDue(); due != "" && due <= deadline {
+			tickets = append(tickets, ticket)
+		}
+	}
+	// ordered like the slaDue range key of the index
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].SLA.NextDue() < tickets[j].SLA.NextDue()
+	})
+	return tickets, nil
+}
+
+func (mr *memoryTicketRepository) ListOpenTickets(ctx context.Context, priority models.TicketPriority) ([]models.Ticket, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	prefix := ticketPK(ctx, "")
+	tickets := []models.Ticket{}
+	for pk, ticket := range mr.tickets {
+		if strings.HasPrefix(pk, prefix) && ticket.Priority == priority && ticket.IsOpen() && !ticket.IsDeleted() {
+			tickets = append(tickets, ticket)
+		}
+	}
+	// the OpenPriority index uses createdAt as range key
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt.Time)
+	})
+	return tickets, nil
+}
+
+func (mr *memoryTicketRepository) ListTicketsInStatus(ctx context.Context, statuses []models.TicketStatus) ([]models.TenantTicket, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	wanted := make(map[models.TicketStatus]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+	tickets := []models.TenantTicket{}
+	for pk, ticket := range mr.tickets {
+		tenant, _, ok := ParseTicketKey(pk)
+		if ok && wanted[ticket.Status] && !ticket.IsDeleted() {
+			tickets = append(tickets, models.TenantTicket{Tenant: tenant, Ticket: ticket})
+		}
+	}
+	// the Status index returns each status in createdAt order
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].Ticket.CreatedAt.Before(tickets[j].Ticket.CreatedAt.Time)
+	})
+	return tickets, nil
+}
+
+func (mr *memoryTicketRepository) ListTickets(ctx context.Context, filter models.TicketFilter) (*models.TicketPage, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	prefix := ticketPK(ctx, "")
+	var tickets []models.Ticket
+	for pk, ticket := range mr.tickets {
+		if strings.HasPrefix(pk, prefix) && filter.Matches(&ticket) && !ticket.IsDeleted() {
+			tickets = append(tickets, ticket)
+		}
+	}
+	// ordered like the createdAt range key of the GSIs
+	sortKeys := make([]string, len(tickets))
+	sort.Slice(tickets, func(i, j int) bool {
+		return listingSK(&tickets[i]) < listingSK(&tickets[j])
+	})
+	for i := range tickets {
+		sortKeys[i] = listingSK(&tickets[i])
+	}
+
+	indexes, nextCursor, err := memoryPage("tickets", sortKeys, filter.Page)
+	if err != nil {
+		return nil, err
+	}
+	page := &models.TicketPage{Tickets: []models.Ticket{}, NextCursor: nextCursor}
+	for _, i := range indexes {
+		page.Tickets = append(page.Tickets, tickets[i])
+	}
+	return page, nil
+}
+
+func listingSK(ticket *models.Ticket) string {
+	return models.FormatCreatedAt(ticket.CreatedAt.Time) + "#" + ticket.TicketID
+}
+
+// Like the DynamoDB implementation, a ticket whose id is taken is reported
+// as a failure and left as it is
+func (mr *memoryTicketRepository) BulkImport(ctx context.Context, entries []models.Ticket) ([]models.BulkWriteFailure, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	failures := []models.BulkWriteFailure{}
+	for _, entry := range entries {
+		pk := ticketPK(ctx, entry.TicketID)
+		if _, exists := mr.tickets[pk]; exists {
+			failures = append(failures, models.BulkWriteFailure{TicketID: entry.TicketID, Reason: ErrTicketExists.Error()})
+			continue
+		}
+		mr.tickets[pk] = entry
+		mr.appendHistory(ctx, newHistoryEntry(ctx, models.ActionImported, nil, &entry))
+	}
+	return failures, nil
+}
+
+// callers must hold the write lock
+func (mr *memoryTicketRepository) appendHistory(ctx context.Context, entry models.HistoryEntry) {
+	pk := ticketPK(ctx, entry.TicketID)
+	mr.history[pk] = append(mr.history[pk], entry)
+}
+
+func (mr *memoryTicketRepository) GetTicketHistory(ctx context.Context, ticketID string, page models.PageRequest) (*models.HistoryPage, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	entries := mr.history[ticketPK(ctx, ticketID)]
+	sortKeys := make([]string, len(entries))
+	for i := range entries {
+		sortKeys[i] = historySK(&entries[i])
+	}
+	indexes, nextCursor, err := memoryPage(ticketPK(ctx, ticketID), sortKeys, page)
+	if err != nil {
+		return nil, err
+	}
+
+	historyPage := &models.HistoryPage{Entries: []models.HistoryEntry{}, NextCursor: nextCursor}
+	for _, i := range indexes {
+		historyPage.Entries = append(historyPage.Entries, entries[i])
+	}
+	return historyPage, nil
+}
+
+// Pages through items of a partition the way a DynamoDB query would.
+// sortKeys must be in ascending order; returns the indexes of the items in
+// the requested page and the cursor for the next one.
+func memoryPage(pk string, sortKeys []string, page models.PageRequest) ([]int, string, error) {
+	startKey, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ordered := make([]int, len(sortKeys))
+	for i := range sortKeys {
+		ordered[i] = i
+		if page.Descending {
+			ordered[i] = len(sortKeys) - 1 - i
+		}
+	}
+
+	start := 0
+	if startKey != nil {
+		lastSK, _ := startKey["SK"].(*types.AttributeValueMemberS)
+		if lastSK == nil {
+			return nil, "", ErrInvalidCursor
+		}
+		for start < len(ordered) && sortKeys[ordered[start]] != lastSK.Value {
+			start++
+		}
+		start++
+	}
+	if start > len(ordered) {
+		start = len(ordered)
+	}
+
+	end := start + int(pageSize(page))
+	if end >= len(ordered) {
+		return ordered[start:], "", nil
+	}
+	nextCursor, err := encodeCursor(map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: pk},
+		"SK": &types.AttributeValueMemberS{Value: sortKeys[ordered[end-1]]},
+	})
+	return ordered[start:end], nextCursor, err
+}
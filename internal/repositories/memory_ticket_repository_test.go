@@ -0,0 +1,393 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTicketRepository_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+
+	id, err := repo.CreateTicket(ctx, &models.Ticket{Description: "Test ticket", Status: models.StatusOpen})
+	require.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	ticket, err := repo.GetTicket(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Test ticket", ticket.Description)
+
+	_, err = repo.GetTicket(ctx, "missing")
+	assert.ErrorIs(t, err, ErrLoadingTicket)
+}
+
+func TestMemoryTicketRepository_Updates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+	id, err := repo.CreateTicket(ctx, &models.Ticket{Status: models.StatusOpen, AssignedTo: "None"})
+	require.NoError(t, err)
+
+	_, err = repo.UpdateStatus(ctx, id, string(models.StatusClosed), 0)
+	require.NoError(t, err)
+	_, err = repo.UpdateStatus(ctx, id, "UNKNOWN", 0)
+	assert.Error(t, err)
+	_, err = repo.UpdateAssignTo(ctx, id, "andrew", 0)
+	require.NoError(t, err)
+
+	ticket, err := repo.GetTicket(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusClosed, ticket.Status)
+	assert.Equal(t, "andrew", ticket.AssignedTo)
+
+	tickets, err := repo.GetTicketsAssignedTo(ctx, "andrew")
+	require.NoError(t, err)
+	assert.Len(t, tickets, 1)
+
+	tickets, err = repo.GetTicketsAssignedTo(ctx, "nobody")
+	require.NoError(t, err)
+	assert.NotNil(t, tickets)
+	assert.Empty(t, tickets)
+}
+
+func TestMemoryTicketRepository_OptimisticLocking(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+	id, err := repo.CreateTicket(ctx, &models.Ticket{Status: models.StatusOpen})
+	require.NoError(t, err)
+
+	ticket, err := repo.UpdateAssignTo(ctx, id, "andrew", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), ticket.Version)
+
+	_, err = repo.UpdateStatus(ctx, id, string(models.StatusClosed), 1)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+
+	stale, err := repo.GetTicket(ctx, id)
+	require.NoError(t, err)
+	_, err = repo.UpdateAssignTo(ctx, id, "david", 0)
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.UpdateTicket(ctx, stale), ErrVersionConflict)
+}
+
+func TestMemoryTicketRepository_BulkImport(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+
+	var entries []models.Ticket
+	for i := 0; i < 100; i++ {
+		entries = append(entries, models.Ticket{TicketID: fmt.Sprintf("%d", i), AssignedTo: "hugo"})
+	}
+	failures, err := repo.BulkImport(ctx, entries)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	tickets, err := repo.GetTicketsAssignedTo(ctx, "hugo")
+	require.NoError(t, err)
+	assert.Len(t, tickets, 100)
+}
+
+func TestMemoryTicketRepository_BulkImportKeepsExistingTickets(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+	id, err := repo.CreateTicket(ctx, &models.Ticket{Description: "printer", Status: models.StatusOpen})
+	require.NoError(t, err)
+	_, err = repo.UpdateAssignTo(ctx, id, "david", 0)
+	require.NoError(t, err)
+
+	failures, err := repo.BulkImport(ctx, []models.Ticket{
+		{TicketID: id, Description: "replaced", Status: models.StatusOpen},
+		{TicketID: "new", Description: "imported", Status: models.StatusOpen},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.BulkWriteFailure{{TicketID: id, Reason: ErrTicketExists.Error()}}, failures)
+
+	ticket, err := repo.GetTicket(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "printer", ticket.Description)
+	assert.Equal(t, int64(2), ticket.Version)
+	_, err = repo.GetTicket(ctx, "new")
+	assert.NoError(t, err)
+}
+
+func TestMemoryTicketRepository_ConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := repo.CreateTicket(ctx, &models.Ticket{Status: models.StatusOpen})
+			assert.NoError(t, err)
+			_, err = repo.UpdateAssignTo(ctx, id, "david", 0)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	tickets, err := repo.GetTicketsAssignedTo(ctx, "david")
+	require.NoError(t, err)
+	assert.Len(t, tickets, 50)
+}
+
+func TestMemoryTicketRepository_Comments(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+	ticketID, err := repo.CreateTicket(ctx, &models.Ticket{Status: models.StatusOpen})
+	require.NoError(t, err)
+
+	_, err = repo.AddComment(ctx, &models.Comment{TicketID: "missing", Body: "hello"})
+	assert.ErrorIs(t, err, ErrLoadingTicket)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id, err := repo.AddComment(ctx, &models.Comment{TicketID: ticketID, Author: "hugo", Body: fmt.Sprintf("comment %d", i)})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	page, err := repo.ListComments(ctx, ticketID, models.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 2)
+	assert.Equal(t, "comment 0", page.Comments[0].Body)
+	require.NotEmpty(t, page.NextCursor)
+
+	var bodies []string
+	for cursor := page.NextCursor; cursor != ""; cursor = page.NextCursor {
+		page, err = repo.ListComments(ctx, ticketID, models.PageRequest{Limit: 2, Cursor: cursor})
+		require.NoError(t, err)
+		for _, comment := range page.Comments {
+			bodies = append(bodies, comment.Body)
+		}
+	}
+	assert.Equal(t, []string{"comment 2", "comment 3", "comment 4"}, bodies)
+
+	page, err = repo.ListComments(ctx, ticketID, models.PageRequest{Limit: 1, Descending: true})
+	require.NoError(t, err)
+	assert.Equal(t, "comment 4", page.Comments[0].Body)
+
+	updated, err := repo.UpdateComment(ctx, ticketID, ids[1], "edited")
+	require.NoError(t, err)
+	assert.Equal(t, "edited", updated.Body)
+	assert.NotEmpty(t, updated.UpdatedAt)
+
+	require.NoError(t, repo.DeleteComment(ctx, ticketID, ids[0]))
+	assert.ErrorIs(t, repo.DeleteComment(ctx, ticketID, ids[0]), ErrCommentNotFound)
+
+	page, err = repo.ListComments(ctx, ticketID, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Len(t, page.Comments, 4)
+	assert.Equal(t, "edited", page.Comments[0].Body)
+}
+
+func TestMemoryTicketRepository_History(t *testing.T) {
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "agent-1"})
+	repo := NewMemoryTicketRepository(workflow.Default())
+
+	id, err := repo.CreateTicket(ctx, &models.Ticket{Description: "Test ticket", Status: models.StatusOpen, AssignedTo: "None"})
+	require.NoError(t, err)
+	_, err = repo.UpdateAssignTo(ctx, id, "andrew", 0)
+	require.NoError(t, err)
+	_, err = repo.UpdateStatus(ctx, id, string(models.StatusInProgress), 0)
+	require.NoError(t, err)
+
+	// a rejected write leaves no trace
+	_, err = repo.UpdateStatus(ctx, id, string(models.StatusInProgress), 0)
+	require.Error(t, err)
+
+	page, err := repo.GetTicketHistory(ctx, id, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+
+	assert.Equal(t, models.ActionCreated, page.Entries[0].Action)
+	assert.Equal(t, "agent-1", page.Entries[0].Actor)
+	assert.Contains(t, page.Entries[0].Changes, models.FieldChange{Field: "description", Before: "", After: "Test ticket"})
+
+	assert.Equal(t, models.ActionAssigned, page.Entries[1].Action)
+	assert.Equal(t, []models.FieldChange{{Field: "assignedTo", Before: "None", After: "andrew"}}, page.Entries[1].Changes)
+
+	assert.Equal(t, models.ActionStatusChanged, page.Entries[2].Action)
+	assert.Equal(t, []models.FieldChange{{Field: "status", Before: "OPEN", After: "IN_PROGRESS"}}, page.Entries[2].Changes)
+}
+
+func TestMemoryTicketRepository_Deletion(t *testing.T) {
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "maria"})
+	repo := NewMemoryTicketRepository(workflow.Default())
+	id, err := repo.CreateTicket(ctx, &models.Ticket{Status: models.StatusOpen, AssignedTo: "andrew"})
+	require.NoError(t, err)
+
+	_, err = repo.DeleteTicket(ctx, id, 7)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	ticket, err := repo.DeleteTicket(ctx, id, 1)
+	require.NoError(t, err)
+	assert.True(t, ticket.IsDeleted())
+	assert.Equal(t, "maria", ticket.DeletedBy)
+
+	_, err = repo.GetTicket(ctx, id)
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+	_, err = repo.DeleteTicket(ctx, id, 0)
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+	tickets, err := repo.GetTicketsAssignedTo(ctx, "andrew")
+	require.NoError(t, err)
+	assert.Empty(t, tickets)
+
+	ticket, err = repo.RestoreTicket(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, ticket.IsDeleted())
+	_, err = repo.RestoreTicket(ctx, id)
+	assert.ErrorIs(t, err, ErrTicketNotDeleted)
+	tickets, err = repo.GetTicketsAssignedTo(ctx, "andrew")
+	require.NoError(t, err)
+	assert.Len(t, tickets, 1)
+
+	require.NoError(t, repo.PurgeTicket(ctx, id))
+	_, err = repo.RestoreTicket(ctx, id)
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+	history, err := repo.GetTicketHistory(ctx, id, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, history.Entries)
+	assert.ErrorIs(t, repo.PurgeTicket(ctx, id), ErrTicketNotFound)
+}
+
+func TestMemoryTicketRepository_ListTickets(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTicketRepository(workflow.Default())
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var entries []models.Ticket
+	for i := 0; i < 10; i++ {
+		status := models.StatusOpen
+		if i%2 == 1 {
+			status = models.StatusClosed
+		}
+		entries = append(entries, models.Ticket{
+			TicketID:   fmt.Sprintf("%d", i),
+			Status:     status,
+			AssignedTo: "andrew",
+			CreatedBy:  "hugo",
+			CreatedAt:  models.NewTimestamp(start.Add(time.Duration(i) * time.Hour)),
+		})
+	}
+	failures, err := repo.BulkImport(ctx, entries)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	var ids []string
+	filter := models.TicketFilter{Status: models.StatusOpen, Page: models.PageRequest{Limit: 2, Descending: true}}
+	for {
+		page, err := repo.ListTickets(ctx, filter)
+		require.NoError(t, err)
+		for _, ticket := range page.Tickets {
+			ids = append(ids, ticket.TicketID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Page.Cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"8", "6", "4", "2", "0"}, ids)
+
+	page, err := repo.ListTickets(ctx, models.TicketFilter{
+		AssignedTo:  "andrew",
+		CreatedFrom: start.Add(2 * time.Hour),
+		CreatedTo:   start.Add(4 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Tickets, 3)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.ListTickets(ctx, models.TicketFilter{Page: models.PageRequest{Cursor: "not-a-cursor"}})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestMemoryTicketRepository_TenantIsolation(t *testing.T) {
+	repo := NewMemoryTicketRepository(workflow.Default())
+	acme := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo", Tenant: "acme"})
+	globex := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo", Tenant: "globex"})
+	legacy := context.Background()
+
+	id, err := repo.CreateTicket(acme, &models.Ticket{TicketID: "1234", Description: "acme ticket", Status: models.StatusOpen, AssignedTo: "andrew"})
+	require.NoError(t, err)
+	_, err = repo.CreateTicket(legacy, &models.Ticket{TicketID: "1234", Description: "default ticket", Status: models.StatusOpen, AssignedTo: "andrew"})
+	require.NoError(t, err)
+
+	// the same id in another tenant is another ticket
+	ticket, err := repo.GetTicket(legacy, id)
+	require.NoError(t, err)
+	assert.Equal(t, "default ticket", ticket.Description)
+
+	_, err = repo.GetTicket(globex, id)
+	assert.ErrorIs(t, err, ErrLoadingTicket)
+	_, err = repo.UpdateStatus(globex, id, string(models.StatusInProgress), 0)
+	assert.ErrorIs(t, err, ErrLoadingTicket)
+	_, err = repo.UpdateAssignTo(globex, id, "mallory", 0)
+	assert.ErrorIs(t, err, ErrLoadingTicket)
+	_, err = repo.AddComment(globex, &models.Comment{TicketID: id, Author: "mallory", Body: "hi"})
+	assert.ErrorIs(t, err, ErrLoadingTicket)
+
+	assigned, err := repo.GetTicketsAssignedTo(globex, "andrew")
+	require.NoError(t, err)
+	assert.Empty(t, assigned)
+	assigned, err = repo.GetTicketsAssignedTo(acme, "andrew")
+	require.NoError(t, err)
+	require.Len(t, assigned, 1)
+	assert.Equal(t, "acme ticket", assigned[0].Description)
+
+	page, err := repo.ListTickets(globex, models.TicketFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Tickets)
+
+	history, err := repo.GetTicketHistory(globex, id, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, history.Entries)
+
+	// the escalation engine lists the tickets of every tenant
+	open, err := repo.ListTicketsInStatus(globex, []models.TicketStatus{models.StatusOpen})
+	require.NoError(t, err)
+	tenants := []string{}
+	for _, ticket := range open {
+		tenants = append(tenants, ticket.Tenant)
+	}
+	assert.ElementsMatch(t, []string{"acme", identity.DefaultTenant}, tenants)
+}
+
+func TestTicketPK(t *testing.T) {
+	assert.Equal(t, "#ticket#1234", ticketPK(context.Background(), "1234"))
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Tenant: identity.DefaultTenant})
+	assert.Equal(t, "#ticket#1234", ticketPK(ctx, "1234"))
+	ctx = identity.WithIdentity(context.Background(), identity.Identity{Tenant: "acme"})
+	assert.Equal(t, "#tenant#acme#ticket#1234", ticketPK(ctx, "1234"))
+}
+
+func TestParseTicketKey(t *testing.T) {
+	tests := []struct {
+		pk             string
+		expectedTenant string
+		expectedID     string
+		expectedOK     bool
+	}{
+		{"#ticket#1234", identity.DefaultTenant, "1234", true},
+		{"#tenant#acme#ticket#1234", "acme", "1234", true},
+		{"#tenant#acme#import#1234", "", "", false},
+		{"#outbox", "", "", false},
+		{"#ticket#", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pk, func(t *testing.T) {
+			tenant, id, ok := ParseTicketKey(tt.pk)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedTenant, tenant)
+			assert.Equal(t, tt.expectedID, id)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	models "example.com/ticket-system/internal/models"
+)
+
+func (mr *memoryTicketRepository) CreateWebhook(ctx context.Context, webhook *models.WebhookSubscription) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := webhooksPK(ctx)
+	mr.webhooks[pk] = append(mr.webhooks[pk], *webhook)
+	return nil
+}
+
+func (mr *memoryTicketRepository) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	webhooks := append([]models.WebhookSubscription{}, mr.webhooks[webhooksPK(ctx)]...)
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].WebhookID < webhooks[j].WebhookID
+	})
+	return webhooks, nil
+}
+
+func (mr *memoryTicketRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := webhooksPK(ctx)
+	webhooks := mr.webhooks[pk]
+	for i, webhook := range webhooks {
+		if webhook.WebhookID == webhookID {
+			mr.webhooks[pk] = append(webhooks[:i:i], webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w - %s", ErrWebhookNotFound, webhookID)
+}
+
+func (mr *memoryTicketRepository) AddDeadLetter(ctx context.Context, letter *models.WebhookDeadLetter) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	pk := webhooksPK(ctx)
+	letters := append(mr.deadLetters[pk], *letter)
+	sort.Slice(letters, func(i, j int) bool {
+		return deadLetterSK(&letters[i]) < deadLetterSK(&letters[j])
+	})
+	mr.deadLetters[pk] = letters
+	return nil
+}
+
+func (mr *memoryTicketRepository) ListDeadLetters(ctx context.Context, page models.PageRequest) (*models.WebhookDeadLetterPage, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	letters := mr.deadLetters[webhooksPK(ctx)]
+	sortKeys := make([]string, len(letters))
+	for i := range letters {
+		sortKeys[i] = deadLetterSK(&letters[i])
+	}
+	indexes, nextCursor, err := memoryPage(webhooksPK(ctx), sortKeys, page)
+	if err != nil {
+		return nil, err
+	}
+
+	letterPage := &models.WebhookDeadLetterPage{DeadLetters: []models.WebhookDeadLetter{}, NextCursor: nextCursor}
+	for _, i := range indexes {
+		letterPage.DeadLetters = append(letterPage.DeadLetters, letters[i])
+	}
+	return letterPage, nil
+}
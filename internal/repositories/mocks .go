@@ -6,18 +6,19 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"example.com/ticket-system/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// NewMockTicketRepository creates a new instance of MockTicketRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAssignmentRepository creates a new instance of MockAssignmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockTicketRepository(t interface {
+func NewMockAssignmentRepository(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockTicketRepository {
-	mock := &MockTicketRepository{}
+}) *MockAssignmentRepository {
+	mock := &MockAssignmentRepository{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -25,123 +26,165 @@ func NewMockTicketRepository(t interface {
 	return mock
 }
 
-// MockTicketRepository is an autogenerated mock type for the TicketRepository type
-type MockTicketRepository struct {
+// MockAssignmentRepository is an autogenerated mock type for the AssignmentRepository type
+type MockAssignmentRepository struct {
 	mock.Mock
 }
 
-type MockTicketRepository_Expecter struct {
+type MockAssignmentRepository_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockTicketRepository) EXPECT() *MockTicketRepository_Expecter {
-	return &MockTicketRepository_Expecter{mock: &_m.Mock}
+func (_m *MockAssignmentRepository) EXPECT() *MockAssignmentRepository_Expecter {
+	return &MockAssignmentRepository_Expecter{mock: &_m.Mock}
 }
 
-// BulkImport provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) BulkImport(ctx context.Context, entries []models.Ticket) error {
-	ret := _mock.Called(ctx, entries)
+// ReserveAssignmentTurns provides a mock function for the type MockAssignmentRepository
+func (_mock *MockAssignmentRepository) ReserveAssignmentTurns(ctx context.Context, key string, n int) (int64, error) {
+	ret := _mock.Called(ctx, key, n)
 
 	if len(ret) == 0 {
-		panic("no return value specified for BulkImport")
+		panic("no return value specified for ReserveAssignmentTurns")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Ticket) error); ok {
-		r0 = returnFunc(ctx, entries)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (int64, error)); ok {
+		return returnFunc(ctx, key, n)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) int64); ok {
+		r0 = returnFunc(ctx, key, n)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, key, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockTicketRepository_BulkImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkImport'
-type MockTicketRepository_BulkImport_Call struct {
+// MockAssignmentRepository_ReserveAssignmentTurns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveAssignmentTurns'
+type MockAssignmentRepository_ReserveAssignmentTurns_Call struct {
 	*mock.Call
 }
 
-// BulkImport is a helper method to define mock.On call
+// ReserveAssignmentTurns is a helper method to define mock.On call
 //   - ctx context.Context
-//   - entries []models.Ticket
-func (_e *MockTicketRepository_Expecter) BulkImport(ctx interface{}, entries interface{}) *MockTicketRepository_BulkImport_Call {
-	return &MockTicketRepository_BulkImport_Call{Call: _e.mock.On("BulkImport", ctx, entries)}
+//   - key string
+//   - n int
+func (_e *MockAssignmentRepository_Expecter) ReserveAssignmentTurns(ctx interface{}, key interface{}, n interface{}) *MockAssignmentRepository_ReserveAssignmentTurns_Call {
+	return &MockAssignmentRepository_ReserveAssignmentTurns_Call{Call: _e.mock.On("ReserveAssignmentTurns", ctx, key, n)}
 }
 
-func (_c *MockTicketRepository_BulkImport_Call) Run(run func(ctx context.Context, entries []models.Ticket)) *MockTicketRepository_BulkImport_Call {
+func (_c *MockAssignmentRepository_ReserveAssignmentTurns_Call) Run(run func(ctx context.Context, key string, n int)) *MockAssignmentRepository_ReserveAssignmentTurns_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []models.Ticket
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].([]models.Ticket)
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTicketRepository_BulkImport_Call) Return(err error) *MockTicketRepository_BulkImport_Call {
-	_c.Call.Return(err)
+func (_c *MockAssignmentRepository_ReserveAssignmentTurns_Call) Return(i int64, err error) *MockAssignmentRepository_ReserveAssignmentTurns_Call {
+	_c.Call.Return(i, err)
 	return _c
 }
 
-func (_c *MockTicketRepository_BulkImport_Call) RunAndReturn(run func(ctx context.Context, entries []models.Ticket) error) *MockTicketRepository_BulkImport_Call {
+func (_c *MockAssignmentRepository_ReserveAssignmentTurns_Call) RunAndReturn(run func(ctx context.Context, key string, n int) (int64, error)) *MockAssignmentRepository_ReserveAssignmentTurns_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateTicket provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) CreateTicket(ctx context.Context, ticket *models.Ticket) (string, error) {
-	ret := _mock.Called(ctx, ticket)
+// NewMockCommentRepository creates a new instance of MockCommentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCommentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCommentRepository {
+	mock := &MockCommentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCommentRepository is an autogenerated mock type for the CommentRepository type
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+type MockCommentRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCommentRepository) EXPECT() *MockCommentRepository_Expecter {
+	return &MockCommentRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddComment provides a mock function for the type MockCommentRepository
+func (_mock *MockCommentRepository) AddComment(ctx context.Context, comment *models.Comment) (string, error) {
+	ret := _mock.Called(ctx, comment)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateTicket")
+		panic("no return value specified for AddComment")
 	}
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Ticket) (string, error)); ok {
-		return returnFunc(ctx, ticket)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Comment) (string, error)); ok {
+		return returnFunc(ctx, comment)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Ticket) string); ok {
-		r0 = returnFunc(ctx, ticket)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Comment) string); ok {
+		r0 = returnFunc(ctx, comment)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.Ticket) error); ok {
-		r1 = returnFunc(ctx, ticket)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.Comment) error); ok {
+		r1 = returnFunc(ctx, comment)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockTicketRepository_CreateTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTicket'
-type MockTicketRepository_CreateTicket_Call struct {
+// MockCommentRepository_AddComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddComment'
+type MockCommentRepository_AddComment_Call struct {
 	*mock.Call
 }
 
-// CreateTicket is a helper method to define mock.On call
+// AddComment is a helper method to define mock.On call
 //   - ctx context.Context
-//   - ticket *models.Ticket
-func (_e *MockTicketRepository_Expecter) CreateTicket(ctx interface{}, ticket interface{}) *MockTicketRepository_CreateTicket_Call {
-	return &MockTicketRepository_CreateTicket_Call{Call: _e.mock.On("CreateTicket", ctx, ticket)}
+//   - comment *models.Comment
+func (_e *MockCommentRepository_Expecter) AddComment(ctx interface{}, comment interface{}) *MockCommentRepository_AddComment_Call {
+	return &MockCommentRepository_AddComment_Call{Call: _e.mock.On("AddComment", ctx, comment)}
 }
 
-func (_c *MockTicketRepository_CreateTicket_Call) Run(run func(ctx context.Context, ticket *models.Ticket)) *MockTicketRepository_CreateTicket_Call {
+func (_c *MockCommentRepository_AddComment_Call) Run(run func(ctx context.Context, comment *models.Comment)) *MockCommentRepository_AddComment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *models.Ticket
+		var arg1 *models.Comment
 		if args[1] != nil {
-			arg1 = args[1].(*models.Ticket)
+			arg1 = args[1].(*models.Comment)
 		}
 		run(
 			arg0,
@@ -151,57 +194,47 @@ func (_c *MockTicketRepository_CreateTicket_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockTicketRepository_CreateTicket_Call) Return(s string, err error) *MockTicketRepository_CreateTicket_Call {
+func (_c *MockCommentRepository_AddComment_Call) Return(s string, err error) *MockCommentRepository_AddComment_Call {
 	_c.Call.Return(s, err)
 	return _c
 }
 
-func (_c *MockTicketRepository_CreateTicket_Call) RunAndReturn(run func(ctx context.Context, ticket *models.Ticket) (string, error)) *MockTicketRepository_CreateTicket_Call {
+func (_c *MockCommentRepository_AddComment_Call) RunAndReturn(run func(ctx context.Context, comment *models.Comment) (string, error)) *MockCommentRepository_AddComment_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTicket provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) GetTicket(ctx context.Context, id string) (*models.Ticket, error) {
-	ret := _mock.Called(ctx, id)
+// DeleteComment provides a mock function for the type MockCommentRepository
+func (_mock *MockCommentRepository) DeleteComment(ctx context.Context, ticketID string, commentID string) error {
+	ret := _mock.Called(ctx, ticketID, commentID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTicket")
+		panic("no return value specified for DeleteComment")
 	}
 
-	var r0 *models.Ticket
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Ticket, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Ticket); ok {
-		r0 = returnFunc(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Ticket)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, ticketID, commentID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockTicketRepository_GetTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicket'
-type MockTicketRepository_GetTicket_Call struct {
+// MockCommentRepository_DeleteComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteComment'
+type MockCommentRepository_DeleteComment_Call struct {
 	*mock.Call
 }
 
-// GetTicket is a helper method to define mock.On call
+// DeleteComment is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-func (_e *MockTicketRepository_Expecter) GetTicket(ctx interface{}, id interface{}) *MockTicketRepository_GetTicket_Call {
-	return &MockTicketRepository_GetTicket_Call{Call: _e.mock.On("GetTicket", ctx, id)}
+//   - ticketID string
+//   - commentID string
+func (_e *MockCommentRepository_Expecter) DeleteComment(ctx interface{}, ticketID interface{}, commentID interface{}) *MockCommentRepository_DeleteComment_Call {
+	return &MockCommentRepository_DeleteComment_Call{Call: _e.mock.On("DeleteComment", ctx, ticketID, commentID)}
 }
 
-func (_c *MockTicketRepository_GetTicket_Call) Run(run func(ctx context.Context, id string)) *MockTicketRepository_GetTicket_Call {
+func (_c *MockCommentRepository_DeleteComment_Call) Run(run func(ctx context.Context, ticketID string, commentID string)) *MockCommentRepository_DeleteComment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -211,65 +244,71 @@ func (_c *MockTicketRepository_GetTicket_Call) Run(run func(ctx context.Context,
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTicketRepository_GetTicket_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_GetTicket_Call {
-	_c.Call.Return(ticket, err)
+func (_c *MockCommentRepository_DeleteComment_Call) Return(err error) *MockCommentRepository_DeleteComment_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockTicketRepository_GetTicket_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Ticket, error)) *MockTicketRepository_GetTicket_Call {
+func (_c *MockCommentRepository_DeleteComment_Call) RunAndReturn(run func(ctx context.Context, ticketID string, commentID string) error) *MockCommentRepository_DeleteComment_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTicketsAssignedTo provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) GetTicketsAssignedTo(ctx context.Context, userName string) ([]models.Ticket, error) {
-	ret := _mock.Called(ctx, userName)
+// ListComments provides a mock function for the type MockCommentRepository
+func (_mock *MockCommentRepository) ListComments(ctx context.Context, ticketID string, page models.PageRequest) (*models.CommentPage, error) {
+	ret := _mock.Called(ctx, ticketID, page)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTicketsAssignedTo")
+		panic("no return value specified for ListComments")
 	}
 
-	var r0 []models.Ticket
+	var r0 *models.CommentPage
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]models.Ticket, error)); ok {
-		return returnFunc(ctx, userName)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.PageRequest) (*models.CommentPage, error)); ok {
+		return returnFunc(ctx, ticketID, page)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []models.Ticket); ok {
-		r0 = returnFunc(ctx, userName)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.PageRequest) *models.CommentPage); ok {
+		r0 = returnFunc(ctx, ticketID, page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Ticket)
+			r0 = ret.Get(0).(*models.CommentPage)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, userName)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, ticketID, page)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockTicketRepository_GetTicketsAssignedTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicketsAssignedTo'
-type MockTicketRepository_GetTicketsAssignedTo_Call struct {
+// MockCommentRepository_ListComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListComments'
+type MockCommentRepository_ListComments_Call struct {
 	*mock.Call
 }
 
-// GetTicketsAssignedTo is a helper method to define mock.On call
+// ListComments is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userName string
-func (_e *MockTicketRepository_Expecter) GetTicketsAssignedTo(ctx interface{}, userName interface{}) *MockTicketRepository_GetTicketsAssignedTo_Call {
-	return &MockTicketRepository_GetTicketsAssignedTo_Call{Call: _e.mock.On("GetTicketsAssignedTo", ctx, userName)}
+//   - ticketID string
+//   - page models.PageRequest
+func (_e *MockCommentRepository_Expecter) ListComments(ctx interface{}, ticketID interface{}, page interface{}) *MockCommentRepository_ListComments_Call {
+	return &MockCommentRepository_ListComments_Call{Call: _e.mock.On("ListComments", ctx, ticketID, page)}
 }
 
-func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) Run(run func(ctx context.Context, userName string)) *MockTicketRepository_GetTicketsAssignedTo_Call {
+func (_c *MockCommentRepository_ListComments_Call) Run(run func(ctx context.Context, ticketID string, page models.PageRequest)) *MockCommentRepository_ListComments_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -279,55 +318,72 @@ func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) Run(run func(ctx conte
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 models.PageRequest
+		if args[2] != nil {
+			arg2 = args[2].(models.PageRequest)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) Return(tickets []models.Ticket, err error) *MockTicketRepository_GetTicketsAssignedTo_Call {
-	_c.Call.Return(tickets, err)
+func (_c *MockCommentRepository_ListComments_Call) Return(commentPage *models.CommentPage, err error) *MockCommentRepository_ListComments_Call {
+	_c.Call.Return(commentPage, err)
 	return _c
 }
 
-func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) RunAndReturn(run func(ctx context.Context, userName string) ([]models.Ticket, error)) *MockTicketRepository_GetTicketsAssignedTo_Call {
+func (_c *MockCommentRepository_ListComments_Call) RunAndReturn(run func(ctx context.Context, ticketID string, page models.PageRequest) (*models.CommentPage, error)) *MockCommentRepository_ListComments_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateAssignTo provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) UpdateAssignTo(ctx context.Context, id string, assignTo string) error {
-	ret := _mock.Called(ctx, id, assignTo)
+// UpdateComment provides a mock function for the type MockCommentRepository
+func (_mock *MockCommentRepository) UpdateComment(ctx context.Context, ticketID string, commentID string, body string) (*models.Comment, error) {
+	ret := _mock.Called(ctx, ticketID, commentID, body)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateAssignTo")
+		panic("no return value specified for UpdateComment")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = returnFunc(ctx, id, assignTo)
+	var r0 *models.Comment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.Comment, error)); ok {
+		return returnFunc(ctx, ticketID, commentID, body)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *models.Comment); ok {
+		r0 = returnFunc(ctx, ticketID, commentID, body)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Comment)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, ticketID, commentID, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockTicketRepository_UpdateAssignTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAssignTo'
-type MockTicketRepository_UpdateAssignTo_Call struct {
+// MockCommentRepository_UpdateComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateComment'
+type MockCommentRepository_UpdateComment_Call struct {
 	*mock.Call
 }
 
-// UpdateAssignTo is a helper method to define mock.On call
+// UpdateComment is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-//   - assignTo string
-func (_e *MockTicketRepository_Expecter) UpdateAssignTo(ctx interface{}, id interface{}, assignTo interface{}) *MockTicketRepository_UpdateAssignTo_Call {
-	return &MockTicketRepository_UpdateAssignTo_Call{Call: _e.mock.On("UpdateAssignTo", ctx, id, assignTo)}
+//   - ticketID string
+//   - commentID string
+//   - body string
+func (_e *MockCommentRepository_Expecter) UpdateComment(ctx interface{}, ticketID interface{}, commentID interface{}, body interface{}) *MockCommentRepository_UpdateComment_Call {
+	return &MockCommentRepository_UpdateComment_Call{Call: _e.mock.On("UpdateComment", ctx, ticketID, commentID, body)}
 }
 
-func (_c *MockTicketRepository_UpdateAssignTo_Call) Run(run func(ctx context.Context, id string, assignTo string)) *MockTicketRepository_UpdateAssignTo_Call {
+func (_c *MockCommentRepository_UpdateComment_Call) Run(run func(ctx context.Context, ticketID string, commentID string, body string)) *MockCommentRepository_UpdateComment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -341,56 +397,99 @@ func (_c *MockTicketRepository_UpdateAssignTo_Call) Run(run func(ctx context.Con
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTicketRepository_UpdateAssignTo_Call) Return(err error) *MockTicketRepository_UpdateAssignTo_Call {
-	_c.Call.Return(err)
+func (_c *MockCommentRepository_UpdateComment_Call) Return(comment *models.Comment, err error) *MockCommentRepository_UpdateComment_Call {
+	_c.Call.Return(comment, err)
 	return _c
 }
 
-func (_c *MockTicketRepository_UpdateAssignTo_Call) RunAndReturn(run func(ctx context.Context, id string, assignTo string) error) *MockTicketRepository_UpdateAssignTo_Call {
+func (_c *MockCommentRepository_UpdateComment_Call) RunAndReturn(run func(ctx context.Context, ticketID string, commentID string, body string) (*models.Comment, error)) *MockCommentRepository_UpdateComment_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateStatus provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) UpdateStatus(ctx context.Context, id string, status string) error {
-	ret := _mock.Called(ctx, id, status)
+// NewMockHistoryRepository creates a new instance of MockHistoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockHistoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockHistoryRepository {
+	mock := &MockHistoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockHistoryRepository is an autogenerated mock type for the HistoryRepository type
+type MockHistoryRepository struct {
+	mock.Mock
+}
+
+type MockHistoryRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockHistoryRepository) EXPECT() *MockHistoryRepository_Expecter {
+	return &MockHistoryRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetTicketHistory provides a mock function for the type MockHistoryRepository
+func (_mock *MockHistoryRepository) GetTicketHistory(ctx context.Context, ticketID string, page models.PageRequest) (*models.HistoryPage, error) {
+	ret := _mock.Called(ctx, ticketID, page)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateStatus")
+		panic("no return value specified for GetTicketHistory")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = returnFunc(ctx, id, status)
+	var r0 *models.HistoryPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.PageRequest) (*models.HistoryPage, error)); ok {
+		return returnFunc(ctx, ticketID, page)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.PageRequest) *models.HistoryPage); ok {
+		r0 = returnFunc(ctx, ticketID, page)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.HistoryPage)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, ticketID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockTicketRepository_UpdateStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStatus'
-type MockTicketRepository_UpdateStatus_Call struct {
+// MockHistoryRepository_GetTicketHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicketHistory'
+type MockHistoryRepository_GetTicketHistory_Call struct {
 	*mock.Call
 }
 
-// UpdateStatus is a helper method to define mock.On call
+// GetTicketHistory is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-//   - status string
-func (_e *MockTicketRepository_Expecter) UpdateStatus(ctx interface{}, id interface{}, status interface{}) *MockTicketRepository_UpdateStatus_Call {
-	return &MockTicketRepository_UpdateStatus_Call{Call: _e.mock.On("UpdateStatus", ctx, id, status)}
+//   - ticketID string
+//   - page models.PageRequest
+func (_e *MockHistoryRepository_Expecter) GetTicketHistory(ctx interface{}, ticketID interface{}, page interface{}) *MockHistoryRepository_GetTicketHistory_Call {
+	return &MockHistoryRepository_GetTicketHistory_Call{Call: _e.mock.On("GetTicketHistory", ctx, ticketID, page)}
 }
 
-func (_c *MockTicketRepository_UpdateStatus_Call) Run(run func(ctx context.Context, id string, status string)) *MockTicketRepository_UpdateStatus_Call {
+func (_c *MockHistoryRepository_GetTicketHistory_Call) Run(run func(ctx context.Context, ticketID string, page models.PageRequest)) *MockHistoryRepository_GetTicketHistory_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -400,9 +499,9 @@ func (_c *MockTicketRepository_UpdateStatus_Call) Run(run func(ctx context.Conte
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 string
+		var arg2 models.PageRequest
 		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg2 = args[2].(models.PageRequest)
 		}
 		run(
 			arg0,
@@ -413,69 +512,2881 @@ func (_c *MockTicketRepository_UpdateStatus_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockTicketRepository_UpdateStatus_Call) Return(err error) *MockTicketRepository_UpdateStatus_Call {
-	_c.Call.Return(err)
+func (_c *MockHistoryRepository_GetTicketHistory_Call) Return(historyPage *models.HistoryPage, err error) *MockHistoryRepository_GetTicketHistory_Call {
+	_c.Call.Return(historyPage, err)
 	return _c
 }
 
-func (_c *MockTicketRepository_UpdateStatus_Call) RunAndReturn(run func(ctx context.Context, id string, status string) error) *MockTicketRepository_UpdateStatus_Call {
+func (_c *MockHistoryRepository_GetTicketHistory_Call) RunAndReturn(run func(ctx context.Context, ticketID string, page models.PageRequest) (*models.HistoryPage, error)) *MockHistoryRepository_GetTicketHistory_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateTicket provides a mock function for the type MockTicketRepository
-func (_mock *MockTicketRepository) UpdateTicket(ctx context.Context, ticket *models.Ticket) error {
-	ret := _mock.Called(ctx, ticket)
+// NewMockImportJobRepository creates a new instance of MockImportJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockImportJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockImportJobRepository {
+	mock := &MockImportJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockImportJobRepository is an autogenerated mock type for the ImportJobRepository type
+type MockImportJobRepository struct {
+	mock.Mock
+}
+
+type MockImportJobRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockImportJobRepository) EXPECT() *MockImportJobRepository_Expecter {
+	return &MockImportJobRepository_Expecter{mock: &_m.Mock}
+}
+
+// CompleteImportChunk provides a mock function for the type MockImportJobRepository
+func (_mock *MockImportJobRepository) CompleteImportChunk(ctx context.Context, jobID string, index int, results []models.ImportLineResult) error {
+	ret := _mock.Called(ctx, jobID, index, results)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateTicket")
+		panic("no return value specified for CompleteImportChunk")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Ticket) error); ok {
-		r0 = returnFunc(ctx, ticket)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, []models.ImportLineResult) error); ok {
+		r0 = returnFunc(ctx, jobID, index, results)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockTicketRepository_UpdateTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTicket'
-type MockTicketRepository_UpdateTicket_Call struct {
+// MockImportJobRepository_CompleteImportChunk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteImportChunk'
+type MockImportJobRepository_CompleteImportChunk_Call struct {
 	*mock.Call
 }
 
-// UpdateTicket is a helper method to define mock.On call
+// CompleteImportChunk is a helper method to define mock.On call
 //   - ctx context.Context
-//   - ticket *models.Ticket
-func (_e *MockTicketRepository_Expecter) UpdateTicket(ctx interface{}, ticket interface{}) *MockTicketRepository_UpdateTicket_Call {
-	return &MockTicketRepository_UpdateTicket_Call{Call: _e.mock.On("UpdateTicket", ctx, ticket)}
+//   - jobID string
+//   - index int
+//   - results []models.ImportLineResult
+func (_e *MockImportJobRepository_Expecter) CompleteImportChunk(ctx interface{}, jobID interface{}, index interface{}, results interface{}) *MockImportJobRepository_CompleteImportChunk_Call {
+	return &MockImportJobRepository_CompleteImportChunk_Call{Call: _e.mock.On("CompleteImportChunk", ctx, jobID, index, results)}
 }
 
-func (_c *MockTicketRepository_UpdateTicket_Call) Run(run func(ctx context.Context, ticket *models.Ticket)) *MockTicketRepository_UpdateTicket_Call {
+func (_c *MockImportJobRepository_CompleteImportChunk_Call) Run(run func(ctx context.Context, jobID string, index int, results []models.ImportLineResult)) *MockImportJobRepository_CompleteImportChunk_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *models.Ticket
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*models.Ticket)
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 []models.ImportLineResult
+		if args[3] != nil {
+			arg3 = args[3].([]models.ImportLineResult)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTicketRepository_UpdateTicket_Call) Return(err error) *MockTicketRepository_UpdateTicket_Call {
+func (_c *MockImportJobRepository_CompleteImportChunk_Call) Return(err error) *MockImportJobRepository_CompleteImportChunk_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockTicketRepository_UpdateTicket_Call) RunAndReturn(run func(ctx context.Context, ticket *models.Ticket) error) *MockTicketRepository_UpdateTicket_Call {
+func (_c *MockImportJobRepository_CompleteImportChunk_Call) RunAndReturn(run func(ctx context.Context, jobID string, index int, results []models.ImportLineResult) error) *MockImportJobRepository_CompleteImportChunk_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateImportJob provides a mock function for the type MockImportJobRepository
+func (_mock *MockImportJobRepository) CreateImportJob(ctx context.Context, job *models.ImportJob, chunks []models.ImportChunk, rejected []models.ImportLineResult) error {
+	ret := _mock.Called(ctx, job, chunks, rejected)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateImportJob")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.ImportJob, []models.ImportChunk, []models.ImportLineResult) error); ok {
+		r0 = returnFunc(ctx, job, chunks, rejected)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockImportJobRepository_CreateImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateImportJob'
+type MockImportJobRepository_CreateImportJob_Call struct {
+	*mock.Call
+}
+
+// CreateImportJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *models.ImportJob
+//   - chunks []models.ImportChunk
+//   - rejected []models.ImportLineResult
+func (_e *MockImportJobRepository_Expecter) CreateImportJob(ctx interface{}, job interface{}, chunks interface{}, rejected interface{}) *MockImportJobRepository_CreateImportJob_Call {
+	return &MockImportJobRepository_CreateImportJob_Call{Call: _e.mock.On("CreateImportJob", ctx, job, chunks, rejected)}
+}
+
+func (_c *MockImportJobRepository_CreateImportJob_Call) Run(run func(ctx context.Context, job *models.ImportJob, chunks []models.ImportChunk, rejected []models.ImportLineResult)) *MockImportJobRepository_CreateImportJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.ImportJob
+		if args[1] != nil {
+			arg1 = args[1].(*models.ImportJob)
+		}
+		var arg2 []models.ImportChunk
+		if args[2] != nil {
+			arg2 = args[2].([]models.ImportChunk)
+		}
+		var arg3 []models.ImportLineResult
+		if args[3] != nil {
+			arg3 = args[3].([]models.ImportLineResult)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImportJobRepository_CreateImportJob_Call) Return(err error) *MockImportJobRepository_CreateImportJob_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockImportJobRepository_CreateImportJob_Call) RunAndReturn(run func(ctx context.Context, job *models.ImportJob, chunks []models.ImportChunk, rejected []models.ImportLineResult) error) *MockImportJobRepository_CreateImportJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImportChunk provides a mock function for the type MockImportJobRepository
+func (_mock *MockImportJobRepository) GetImportChunk(ctx context.Context, jobID string, index int) (*models.ImportChunk, error) {
+	ret := _mock.Called(ctx, jobID, index)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportChunk")
+	}
+
+	var r0 *models.ImportChunk
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (*models.ImportChunk, error)); ok {
+		return returnFunc(ctx, jobID, index)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) *models.ImportChunk); ok {
+		r0 = returnFunc(ctx, jobID, index)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImportChunk)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, jobID, index)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockImportJobRepository_GetImportChunk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImportChunk'
+type MockImportJobRepository_GetImportChunk_Call struct {
+	*mock.Call
+}
+
+// GetImportChunk is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID string
+//   - index int
+func (_e *MockImportJobRepository_Expecter) GetImportChunk(ctx interface{}, jobID interface{}, index interface{}) *MockImportJobRepository_GetImportChunk_Call {
+	return &MockImportJobRepository_GetImportChunk_Call{Call: _e.mock.On("GetImportChunk", ctx, jobID, index)}
+}
+
+func (_c *MockImportJobRepository_GetImportChunk_Call) Run(run func(ctx context.Context, jobID string, index int)) *MockImportJobRepository_GetImportChunk_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImportJobRepository_GetImportChunk_Call) Return(importChunk *models.ImportChunk, err error) *MockImportJobRepository_GetImportChunk_Call {
+	_c.Call.Return(importChunk, err)
+	return _c
+}
+
+func (_c *MockImportJobRepository_GetImportChunk_Call) RunAndReturn(run func(ctx context.Context, jobID string, index int) (*models.ImportChunk, error)) *MockImportJobRepository_GetImportChunk_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImportJob provides a mock function for the type MockImportJobRepository
+func (_mock *MockImportJobRepository) GetImportJob(ctx context.Context, jobID string) (*models.ImportJob, error) {
+	ret := _mock.Called(ctx, jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportJob")
+	}
+
+	var r0 *models.ImportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.ImportJob, error)); ok {
+		return returnFunc(ctx, jobID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.ImportJob); ok {
+		r0 = returnFunc(ctx, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockImportJobRepository_GetImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImportJob'
+type MockImportJobRepository_GetImportJob_Call struct {
+	*mock.Call
+}
+
+// GetImportJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID string
+func (_e *MockImportJobRepository_Expecter) GetImportJob(ctx interface{}, jobID interface{}) *MockImportJobRepository_GetImportJob_Call {
+	return &MockImportJobRepository_GetImportJob_Call{Call: _e.mock.On("GetImportJob", ctx, jobID)}
+}
+
+func (_c *MockImportJobRepository_GetImportJob_Call) Run(run func(ctx context.Context, jobID string)) *MockImportJobRepository_GetImportJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImportJobRepository_GetImportJob_Call) Return(importJob *models.ImportJob, err error) *MockImportJobRepository_GetImportJob_Call {
+	_c.Call.Return(importJob, err)
+	return _c
+}
+
+func (_c *MockImportJobRepository_GetImportJob_Call) RunAndReturn(run func(ctx context.Context, jobID string) (*models.ImportJob, error)) *MockImportJobRepository_GetImportJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListImportErrors provides a mock function for the type MockImportJobRepository
+func (_mock *MockImportJobRepository) ListImportErrors(ctx context.Context, jobID string) ([]models.ImportLineResult, error) {
+	ret := _mock.Called(ctx, jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListImportErrors")
+	}
+
+	var r0 []models.ImportLineResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]models.ImportLineResult, error)); ok {
+		return returnFunc(ctx, jobID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []models.ImportLineResult); ok {
+		r0 = returnFunc(ctx, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ImportLineResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockImportJobRepository_ListImportErrors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListImportErrors'
+type MockImportJobRepository_ListImportErrors_Call struct {
+	*mock.Call
+}
+
+// ListImportErrors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID string
+func (_e *MockImportJobRepository_Expecter) ListImportErrors(ctx interface{}, jobID interface{}) *MockImportJobRepository_ListImportErrors_Call {
+	return &MockImportJobRepository_ListImportErrors_Call{Call: _e.mock.On("ListImportErrors", ctx, jobID)}
+}
+
+func (_c *MockImportJobRepository_ListImportErrors_Call) Run(run func(ctx context.Context, jobID string)) *MockImportJobRepository_ListImportErrors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockImportJobRepository_ListImportErrors_Call) Return(importLineResults []models.ImportLineResult, err error) *MockImportJobRepository_ListImportErrors_Call {
+	_c.Call.Return(importLineResults, err)
+	return _c
+}
+
+func (_c *MockImportJobRepository_ListImportErrors_Call) RunAndReturn(run func(ctx context.Context, jobID string) ([]models.ImportLineResult, error)) *MockImportJobRepository_ListImportErrors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOutboxRepository creates a new instance of MockOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOutboxRepository {
+	mock := &MockOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockOutboxRepository is an autogenerated mock type for the OutboxRepository type
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+type MockOutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOutboxRepository) EXPECT() *MockOutboxRepository_Expecter {
+	return &MockOutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteOutboxEntry provides a mock function for the type MockOutboxRepository
+func (_mock *MockOutboxRepository) DeleteOutboxEntry(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOutboxEntry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockOutboxRepository_DeleteOutboxEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteOutboxEntry'
+type MockOutboxRepository_DeleteOutboxEntry_Call struct {
+	*mock.Call
+}
+
+// DeleteOutboxEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockOutboxRepository_Expecter) DeleteOutboxEntry(ctx interface{}, key interface{}) *MockOutboxRepository_DeleteOutboxEntry_Call {
+	return &MockOutboxRepository_DeleteOutboxEntry_Call{Call: _e.mock.On("DeleteOutboxEntry", ctx, key)}
+}
+
+func (_c *MockOutboxRepository_DeleteOutboxEntry_Call) Run(run func(ctx context.Context, key string)) *MockOutboxRepository_DeleteOutboxEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_DeleteOutboxEntry_Call) Return(err error) *MockOutboxRepository_DeleteOutboxEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockOutboxRepository_DeleteOutboxEntry_Call) RunAndReturn(run func(ctx context.Context, key string) error) *MockOutboxRepository_DeleteOutboxEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOutbox provides a mock function for the type MockOutboxRepository
+func (_mock *MockOutboxRepository) ListOutbox(ctx context.Context, after string, limit int) ([]models.OutboxEntry, error) {
+	ret := _mock.Called(ctx, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOutbox")
+	}
+
+	var r0 []models.OutboxEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]models.OutboxEntry, error)); ok {
+		return returnFunc(ctx, after, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []models.OutboxEntry); ok {
+		r0 = returnFunc(ctx, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockOutboxRepository_ListOutbox_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOutbox'
+type MockOutboxRepository_ListOutbox_Call struct {
+	*mock.Call
+}
+
+// ListOutbox is a helper method to define mock.On call
+//   - ctx context.Context
+//   - after string
+//   - limit int
+func (_e *MockOutboxRepository_Expecter) ListOutbox(ctx interface{}, after interface{}, limit interface{}) *MockOutboxRepository_ListOutbox_Call {
+	return &MockOutboxRepository_ListOutbox_Call{Call: _e.mock.On("ListOutbox", ctx, after, limit)}
+}
+
+func (_c *MockOutboxRepository_ListOutbox_Call) Run(run func(ctx context.Context, after string, limit int)) *MockOutboxRepository_ListOutbox_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_ListOutbox_Call) Return(outboxEntrys []models.OutboxEntry, err error) *MockOutboxRepository_ListOutbox_Call {
+	_c.Call.Return(outboxEntrys, err)
+	return _c
+}
+
+func (_c *MockOutboxRepository_ListOutbox_Call) RunAndReturn(run func(ctx context.Context, after string, limit int) ([]models.OutboxEntry, error)) *MockOutboxRepository_ListOutbox_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTeamRepository creates a new instance of MockTeamRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTeamRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTeamRepository {
+	mock := &MockTeamRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTeamRepository is an autogenerated mock type for the TeamRepository type
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+type MockTeamRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTeamRepository) EXPECT() *MockTeamRepository_Expecter {
+	return &MockTeamRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateQueue provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) CreateQueue(ctx context.Context, queue *models.Queue) error {
+	ret := _mock.Called(ctx, queue)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQueue")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Queue) error); ok {
+		r0 = returnFunc(ctx, queue)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTeamRepository_CreateQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateQueue'
+type MockTeamRepository_CreateQueue_Call struct {
+	*mock.Call
+}
+
+// CreateQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queue *models.Queue
+func (_e *MockTeamRepository_Expecter) CreateQueue(ctx interface{}, queue interface{}) *MockTeamRepository_CreateQueue_Call {
+	return &MockTeamRepository_CreateQueue_Call{Call: _e.mock.On("CreateQueue", ctx, queue)}
+}
+
+func (_c *MockTeamRepository_CreateQueue_Call) Run(run func(ctx context.Context, queue *models.Queue)) *MockTeamRepository_CreateQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Queue
+		if args[1] != nil {
+			arg1 = args[1].(*models.Queue)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_CreateQueue_Call) Return(err error) *MockTeamRepository_CreateQueue_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTeamRepository_CreateQueue_Call) RunAndReturn(run func(ctx context.Context, queue *models.Queue) error) *MockTeamRepository_CreateQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTeam provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) CreateTeam(ctx context.Context, team *models.Team) error {
+	ret := _mock.Called(ctx, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTeam")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Team) error); ok {
+		r0 = returnFunc(ctx, team)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTeamRepository_CreateTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTeam'
+type MockTeamRepository_CreateTeam_Call struct {
+	*mock.Call
+}
+
+// CreateTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - team *models.Team
+func (_e *MockTeamRepository_Expecter) CreateTeam(ctx interface{}, team interface{}) *MockTeamRepository_CreateTeam_Call {
+	return &MockTeamRepository_CreateTeam_Call{Call: _e.mock.On("CreateTeam", ctx, team)}
+}
+
+func (_c *MockTeamRepository_CreateTeam_Call) Run(run func(ctx context.Context, team *models.Team)) *MockTeamRepository_CreateTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Team
+		if args[1] != nil {
+			arg1 = args[1].(*models.Team)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_CreateTeam_Call) Return(err error) *MockTeamRepository_CreateTeam_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTeamRepository_CreateTeam_Call) RunAndReturn(run func(ctx context.Context, team *models.Team) error) *MockTeamRepository_CreateTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteQueue provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) DeleteQueue(ctx context.Context, queueID string) error {
+	ret := _mock.Called(ctx, queueID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteQueue")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, queueID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTeamRepository_DeleteQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteQueue'
+type MockTeamRepository_DeleteQueue_Call struct {
+	*mock.Call
+}
+
+// DeleteQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueID string
+func (_e *MockTeamRepository_Expecter) DeleteQueue(ctx interface{}, queueID interface{}) *MockTeamRepository_DeleteQueue_Call {
+	return &MockTeamRepository_DeleteQueue_Call{Call: _e.mock.On("DeleteQueue", ctx, queueID)}
+}
+
+func (_c *MockTeamRepository_DeleteQueue_Call) Run(run func(ctx context.Context, queueID string)) *MockTeamRepository_DeleteQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_DeleteQueue_Call) Return(err error) *MockTeamRepository_DeleteQueue_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTeamRepository_DeleteQueue_Call) RunAndReturn(run func(ctx context.Context, queueID string) error) *MockTeamRepository_DeleteQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTeam provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) DeleteTeam(ctx context.Context, teamID string) error {
+	ret := _mock.Called(ctx, teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTeam")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, teamID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTeamRepository_DeleteTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTeam'
+type MockTeamRepository_DeleteTeam_Call struct {
+	*mock.Call
+}
+
+// DeleteTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - teamID string
+func (_e *MockTeamRepository_Expecter) DeleteTeam(ctx interface{}, teamID interface{}) *MockTeamRepository_DeleteTeam_Call {
+	return &MockTeamRepository_DeleteTeam_Call{Call: _e.mock.On("DeleteTeam", ctx, teamID)}
+}
+
+func (_c *MockTeamRepository_DeleteTeam_Call) Run(run func(ctx context.Context, teamID string)) *MockTeamRepository_DeleteTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_DeleteTeam_Call) Return(err error) *MockTeamRepository_DeleteTeam_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTeamRepository_DeleteTeam_Call) RunAndReturn(run func(ctx context.Context, teamID string) error) *MockTeamRepository_DeleteTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetQueue provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) GetQueue(ctx context.Context, queueID string) (*models.Queue, error) {
+	ret := _mock.Called(ctx, queueID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueue")
+	}
+
+	var r0 *models.Queue
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Queue, error)); ok {
+		return returnFunc(ctx, queueID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Queue); ok {
+		r0 = returnFunc(ctx, queueID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Queue)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, queueID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTeamRepository_GetQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetQueue'
+type MockTeamRepository_GetQueue_Call struct {
+	*mock.Call
+}
+
+// GetQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueID string
+func (_e *MockTeamRepository_Expecter) GetQueue(ctx interface{}, queueID interface{}) *MockTeamRepository_GetQueue_Call {
+	return &MockTeamRepository_GetQueue_Call{Call: _e.mock.On("GetQueue", ctx, queueID)}
+}
+
+func (_c *MockTeamRepository_GetQueue_Call) Run(run func(ctx context.Context, queueID string)) *MockTeamRepository_GetQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_GetQueue_Call) Return(queue *models.Queue, err error) *MockTeamRepository_GetQueue_Call {
+	_c.Call.Return(queue, err)
+	return _c
+}
+
+func (_c *MockTeamRepository_GetQueue_Call) RunAndReturn(run func(ctx context.Context, queueID string) (*models.Queue, error)) *MockTeamRepository_GetQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTeam provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) GetTeam(ctx context.Context, teamID string) (*models.Team, error) {
+	ret := _mock.Called(ctx, teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeam")
+	}
+
+	var r0 *models.Team
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Team, error)); ok {
+		return returnFunc(ctx, teamID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Team); ok {
+		r0 = returnFunc(ctx, teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Team)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTeamRepository_GetTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTeam'
+type MockTeamRepository_GetTeam_Call struct {
+	*mock.Call
+}
+
+// GetTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - teamID string
+func (_e *MockTeamRepository_Expecter) GetTeam(ctx interface{}, teamID interface{}) *MockTeamRepository_GetTeam_Call {
+	return &MockTeamRepository_GetTeam_Call{Call: _e.mock.On("GetTeam", ctx, teamID)}
+}
+
+func (_c *MockTeamRepository_GetTeam_Call) Run(run func(ctx context.Context, teamID string)) *MockTeamRepository_GetTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_GetTeam_Call) Return(team *models.Team, err error) *MockTeamRepository_GetTeam_Call {
+	_c.Call.Return(team, err)
+	return _c
+}
+
+func (_c *MockTeamRepository_GetTeam_Call) RunAndReturn(run func(ctx context.Context, teamID string) (*models.Team, error)) *MockTeamRepository_GetTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQueues provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) ListQueues(ctx context.Context) ([]models.Queue, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQueues")
+	}
+
+	var r0 []models.Queue
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Queue, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Queue); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Queue)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTeamRepository_ListQueues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQueues'
+type MockTeamRepository_ListQueues_Call struct {
+	*mock.Call
+}
+
+// ListQueues is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockTeamRepository_Expecter) ListQueues(ctx interface{}) *MockTeamRepository_ListQueues_Call {
+	return &MockTeamRepository_ListQueues_Call{Call: _e.mock.On("ListQueues", ctx)}
+}
+
+func (_c *MockTeamRepository_ListQueues_Call) Run(run func(ctx context.Context)) *MockTeamRepository_ListQueues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_ListQueues_Call) Return(queues []models.Queue, err error) *MockTeamRepository_ListQueues_Call {
+	_c.Call.Return(queues, err)
+	return _c
+}
+
+func (_c *MockTeamRepository_ListQueues_Call) RunAndReturn(run func(ctx context.Context) ([]models.Queue, error)) *MockTeamRepository_ListQueues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTeams provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) ListTeams(ctx context.Context) ([]models.Team, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTeams")
+	}
+
+	var r0 []models.Team
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Team, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Team); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Team)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTeamRepository_ListTeams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTeams'
+type MockTeamRepository_ListTeams_Call struct {
+	*mock.Call
+}
+
+// ListTeams is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockTeamRepository_Expecter) ListTeams(ctx interface{}) *MockTeamRepository_ListTeams_Call {
+	return &MockTeamRepository_ListTeams_Call{Call: _e.mock.On("ListTeams", ctx)}
+}
+
+func (_c *MockTeamRepository_ListTeams_Call) Run(run func(ctx context.Context)) *MockTeamRepository_ListTeams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_ListTeams_Call) Return(teams []models.Team, err error) *MockTeamRepository_ListTeams_Call {
+	_c.Call.Return(teams, err)
+	return _c
+}
+
+func (_c *MockTeamRepository_ListTeams_Call) RunAndReturn(run func(ctx context.Context) ([]models.Team, error)) *MockTeamRepository_ListTeams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateQueue provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) UpdateQueue(ctx context.Context, queue *models.Queue) error {
+	ret := _mock.Called(ctx, queue)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateQueue")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Queue) error); ok {
+		r0 = returnFunc(ctx, queue)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTeamRepository_UpdateQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateQueue'
+type MockTeamRepository_UpdateQueue_Call struct {
+	*mock.Call
+}
+
+// UpdateQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queue *models.Queue
+func (_e *MockTeamRepository_Expecter) UpdateQueue(ctx interface{}, queue interface{}) *MockTeamRepository_UpdateQueue_Call {
+	return &MockTeamRepository_UpdateQueue_Call{Call: _e.mock.On("UpdateQueue", ctx, queue)}
+}
+
+func (_c *MockTeamRepository_UpdateQueue_Call) Run(run func(ctx context.Context, queue *models.Queue)) *MockTeamRepository_UpdateQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Queue
+		if args[1] != nil {
+			arg1 = args[1].(*models.Queue)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_UpdateQueue_Call) Return(err error) *MockTeamRepository_UpdateQueue_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTeamRepository_UpdateQueue_Call) RunAndReturn(run func(ctx context.Context, queue *models.Queue) error) *MockTeamRepository_UpdateQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTeam provides a mock function for the type MockTeamRepository
+func (_mock *MockTeamRepository) UpdateTeam(ctx context.Context, team *models.Team) error {
+	ret := _mock.Called(ctx, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTeam")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Team) error); ok {
+		r0 = returnFunc(ctx, team)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTeamRepository_UpdateTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTeam'
+type MockTeamRepository_UpdateTeam_Call struct {
+	*mock.Call
+}
+
+// UpdateTeam is a helper method to define mock.On call
+//   - ctx context.Context
+//   - team *models.Team
+func (_e *MockTeamRepository_Expecter) UpdateTeam(ctx interface{}, team interface{}) *MockTeamRepository_UpdateTeam_Call {
+	return &MockTeamRepository_UpdateTeam_Call{Call: _e.mock.On("UpdateTeam", ctx, team)}
+}
+
+func (_c *MockTeamRepository_UpdateTeam_Call) Run(run func(ctx context.Context, team *models.Team)) *MockTeamRepository_UpdateTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Team
+		if args[1] != nil {
+			arg1 = args[1].(*models.Team)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTeamRepository_UpdateTeam_Call) Return(err error) *MockTeamRepository_UpdateTeam_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTeamRepository_UpdateTeam_Call) RunAndReturn(run func(ctx context.Context, team *models.Team) error) *MockTeamRepository_UpdateTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTicketRepository creates a new instance of MockTicketRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTicketRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTicketRepository {
+	mock := &MockTicketRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTicketRepository is an autogenerated mock type for the TicketRepository type
+type MockTicketRepository struct {
+	mock.Mock
+}
+
+type MockTicketRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTicketRepository) EXPECT() *MockTicketRepository_Expecter {
+	return &MockTicketRepository_Expecter{mock: &_m.Mock}
+}
+
+// BulkImport provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) BulkImport(ctx context.Context, entries []models.Ticket) ([]models.BulkWriteFailure, error) {
+	ret := _mock.Called(ctx, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkImport")
+	}
+
+	var r0 []models.BulkWriteFailure
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Ticket) ([]models.BulkWriteFailure, error)); ok {
+		return returnFunc(ctx, entries)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Ticket) []models.BulkWriteFailure); ok {
+		r0 = returnFunc(ctx, entries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BulkWriteFailure)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.Ticket) error); ok {
+		r1 = returnFunc(ctx, entries)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_BulkImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkImport'
+type MockTicketRepository_BulkImport_Call struct {
+	*mock.Call
+}
+
+// BulkImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entries []models.Ticket
+func (_e *MockTicketRepository_Expecter) BulkImport(ctx interface{}, entries interface{}) *MockTicketRepository_BulkImport_Call {
+	return &MockTicketRepository_BulkImport_Call{Call: _e.mock.On("BulkImport", ctx, entries)}
+}
+
+func (_c *MockTicketRepository_BulkImport_Call) Run(run func(ctx context.Context, entries []models.Ticket)) *MockTicketRepository_BulkImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.Ticket
+		if args[1] != nil {
+			arg1 = args[1].([]models.Ticket)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_BulkImport_Call) Return(bulkWriteFailures []models.BulkWriteFailure, err error) *MockTicketRepository_BulkImport_Call {
+	_c.Call.Return(bulkWriteFailures, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_BulkImport_Call) RunAndReturn(run func(ctx context.Context, entries []models.Ticket) ([]models.BulkWriteFailure, error)) *MockTicketRepository_BulkImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) CreateTicket(ctx context.Context, ticket *models.Ticket) (string, error) {
+	ret := _mock.Called(ctx, ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTicket")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Ticket) (string, error)); ok {
+		return returnFunc(ctx, ticket)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Ticket) string); ok {
+		r0 = returnFunc(ctx, ticket)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.Ticket) error); ok {
+		r1 = returnFunc(ctx, ticket)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_CreateTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTicket'
+type MockTicketRepository_CreateTicket_Call struct {
+	*mock.Call
+}
+
+// CreateTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ticket *models.Ticket
+func (_e *MockTicketRepository_Expecter) CreateTicket(ctx interface{}, ticket interface{}) *MockTicketRepository_CreateTicket_Call {
+	return &MockTicketRepository_CreateTicket_Call{Call: _e.mock.On("CreateTicket", ctx, ticket)}
+}
+
+func (_c *MockTicketRepository_CreateTicket_Call) Run(run func(ctx context.Context, ticket *models.Ticket)) *MockTicketRepository_CreateTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Ticket
+		if args[1] != nil {
+			arg1 = args[1].(*models.Ticket)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_CreateTicket_Call) Return(s string, err error) *MockTicketRepository_CreateTicket_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_CreateTicket_Call) RunAndReturn(run func(ctx context.Context, ticket *models.Ticket) (string, error)) *MockTicketRepository_CreateTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) DeleteTicket(ctx context.Context, id string, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTicket")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, id, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_DeleteTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTicket'
+type MockTicketRepository_DeleteTicket_Call struct {
+	*mock.Call
+}
+
+// DeleteTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) DeleteTicket(ctx interface{}, id interface{}, expectedVersion interface{}) *MockTicketRepository_DeleteTicket_Call {
+	return &MockTicketRepository_DeleteTicket_Call{Call: _e.mock.On("DeleteTicket", ctx, id, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_DeleteTicket_Call) Run(run func(ctx context.Context, id string, expectedVersion int64)) *MockTicketRepository_DeleteTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_DeleteTicket_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_DeleteTicket_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_DeleteTicket_Call) RunAndReturn(run func(ctx context.Context, id string, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_DeleteTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) GetTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTicket")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_GetTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicket'
+type MockTicketRepository_GetTicket_Call struct {
+	*mock.Call
+}
+
+// GetTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockTicketRepository_Expecter) GetTicket(ctx interface{}, id interface{}) *MockTicketRepository_GetTicket_Call {
+	return &MockTicketRepository_GetTicket_Call{Call: _e.mock.On("GetTicket", ctx, id)}
+}
+
+func (_c *MockTicketRepository_GetTicket_Call) Run(run func(ctx context.Context, id string)) *MockTicketRepository_GetTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_GetTicket_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_GetTicket_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_GetTicket_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Ticket, error)) *MockTicketRepository_GetTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTicketsAssignedTo provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) GetTicketsAssignedTo(ctx context.Context, userName string) ([]models.Ticket, error) {
+	ret := _mock.Called(ctx, userName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTicketsAssignedTo")
+	}
+
+	var r0 []models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]models.Ticket, error)); ok {
+		return returnFunc(ctx, userName)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []models.Ticket); ok {
+		r0 = returnFunc(ctx, userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_GetTicketsAssignedTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicketsAssignedTo'
+type MockTicketRepository_GetTicketsAssignedTo_Call struct {
+	*mock.Call
+}
+
+// GetTicketsAssignedTo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userName string
+func (_e *MockTicketRepository_Expecter) GetTicketsAssignedTo(ctx interface{}, userName interface{}) *MockTicketRepository_GetTicketsAssignedTo_Call {
+	return &MockTicketRepository_GetTicketsAssignedTo_Call{Call: _e.mock.On("GetTicketsAssignedTo", ctx, userName)}
+}
+
+func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) Run(run func(ctx context.Context, userName string)) *MockTicketRepository_GetTicketsAssignedTo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) Return(tickets []models.Ticket, err error) *MockTicketRepository_GetTicketsAssignedTo_Call {
+	_c.Call.Return(tickets, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_GetTicketsAssignedTo_Call) RunAndReturn(run func(ctx context.Context, userName string) ([]models.Ticket, error)) *MockTicketRepository_GetTicketsAssignedTo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOpenTickets provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) ListOpenTickets(ctx context.Context, priority models.TicketPriority) ([]models.Ticket, error) {
+	ret := _mock.Called(ctx, priority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOpenTickets")
+	}
+
+	var r0 []models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.TicketPriority) ([]models.Ticket, error)); ok {
+		return returnFunc(ctx, priority)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.TicketPriority) []models.Ticket); ok {
+		r0 = returnFunc(ctx, priority)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.TicketPriority) error); ok {
+		r1 = returnFunc(ctx, priority)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_ListOpenTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOpenTickets'
+type MockTicketRepository_ListOpenTickets_Call struct {
+	*mock.Call
+}
+
+// ListOpenTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - priority models.TicketPriority
+func (_e *MockTicketRepository_Expecter) ListOpenTickets(ctx interface{}, priority interface{}) *MockTicketRepository_ListOpenTickets_Call {
+	return &MockTicketRepository_ListOpenTickets_Call{Call: _e.mock.On("ListOpenTickets", ctx, priority)}
+}
+
+func (_c *MockTicketRepository_ListOpenTickets_Call) Run(run func(ctx context.Context, priority models.TicketPriority)) *MockTicketRepository_ListOpenTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.TicketPriority
+		if args[1] != nil {
+			arg1 = args[1].(models.TicketPriority)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_ListOpenTickets_Call) Return(tickets []models.Ticket, err error) *MockTicketRepository_ListOpenTickets_Call {
+	_c.Call.Return(tickets, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_ListOpenTickets_Call) RunAndReturn(run func(ctx context.Context, priority models.TicketPriority) ([]models.Ticket, error)) *MockTicketRepository_ListOpenTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQueueTickets provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) ListQueueTickets(ctx context.Context, queueID string) ([]models.Ticket, error) {
+	ret := _mock.Called(ctx, queueID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQueueTickets")
+	}
+
+	var r0 []models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]models.Ticket, error)); ok {
+		return returnFunc(ctx, queueID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []models.Ticket); ok {
+		r0 = returnFunc(ctx, queueID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, queueID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_ListQueueTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQueueTickets'
+type MockTicketRepository_ListQueueTickets_Call struct {
+	*mock.Call
+}
+
+// ListQueueTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queueID string
+func (_e *MockTicketRepository_Expecter) ListQueueTickets(ctx interface{}, queueID interface{}) *MockTicketRepository_ListQueueTickets_Call {
+	return &MockTicketRepository_ListQueueTickets_Call{Call: _e.mock.On("ListQueueTickets", ctx, queueID)}
+}
+
+func (_c *MockTicketRepository_ListQueueTickets_Call) Run(run func(ctx context.Context, queueID string)) *MockTicketRepository_ListQueueTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_ListQueueTickets_Call) Return(tickets []models.Ticket, err error) *MockTicketRepository_ListQueueTickets_Call {
+	_c.Call.Return(tickets, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_ListQueueTickets_Call) RunAndReturn(run func(ctx context.Context, queueID string) ([]models.Ticket, error)) *MockTicketRepository_ListQueueTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTickets provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) ListTickets(ctx context.Context, filter models.TicketFilter) (*models.TicketPage, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTickets")
+	}
+
+	var r0 *models.TicketPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.TicketFilter) (*models.TicketPage, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.TicketFilter) *models.TicketPage); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TicketPage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.TicketFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_ListTickets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTickets'
+type MockTicketRepository_ListTickets_Call struct {
+	*mock.Call
+}
+
+// ListTickets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.TicketFilter
+func (_e *MockTicketRepository_Expecter) ListTickets(ctx interface{}, filter interface{}) *MockTicketRepository_ListTickets_Call {
+	return &MockTicketRepository_ListTickets_Call{Call: _e.mock.On("ListTickets", ctx, filter)}
+}
+
+func (_c *MockTicketRepository_ListTickets_Call) Run(run func(ctx context.Context, filter models.TicketFilter)) *MockTicketRepository_ListTickets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.TicketFilter
+		if args[1] != nil {
+			arg1 = args[1].(models.TicketFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_ListTickets_Call) Return(ticketPage *models.TicketPage, err error) *MockTicketRepository_ListTickets_Call {
+	_c.Call.Return(ticketPage, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_ListTickets_Call) RunAndReturn(run func(ctx context.Context, filter models.TicketFilter) (*models.TicketPage, error)) *MockTicketRepository_ListTickets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTicketsDueBefore provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) ListTicketsDueBefore(ctx context.Context, before time.Time) ([]models.Ticket, error) {
+	ret := _mock.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTicketsDueBefore")
+	}
+
+	var r0 []models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.Ticket, error)); ok {
+		return returnFunc(ctx, before)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) []models.Ticket); ok {
+		r0 = returnFunc(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_ListTicketsDueBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTicketsDueBefore'
+type MockTicketRepository_ListTicketsDueBefore_Call struct {
+	*mock.Call
+}
+
+// ListTicketsDueBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockTicketRepository_Expecter) ListTicketsDueBefore(ctx interface{}, before interface{}) *MockTicketRepository_ListTicketsDueBefore_Call {
+	return &MockTicketRepository_ListTicketsDueBefore_Call{Call: _e.mock.On("ListTicketsDueBefore", ctx, before)}
+}
+
+func (_c *MockTicketRepository_ListTicketsDueBefore_Call) Run(run func(ctx context.Context, before time.Time)) *MockTicketRepository_ListTicketsDueBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_ListTicketsDueBefore_Call) Return(tickets []models.Ticket, err error) *MockTicketRepository_ListTicketsDueBefore_Call {
+	_c.Call.Return(tickets, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_ListTicketsDueBefore_Call) RunAndReturn(run func(ctx context.Context, before time.Time) ([]models.Ticket, error)) *MockTicketRepository_ListTicketsDueBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTicketsInStatus provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) ListTicketsInStatus(ctx context.Context, statuses []models.TicketStatus) ([]models.TenantTicket, error) {
+	ret := _mock.Called(ctx, statuses)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTicketsInStatus")
+	}
+
+	var r0 []models.TenantTicket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.TicketStatus) ([]models.TenantTicket, error)); ok {
+		return returnFunc(ctx, statuses)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.TicketStatus) []models.TenantTicket); ok {
+		r0 = returnFunc(ctx, statuses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TenantTicket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.TicketStatus) error); ok {
+		r1 = returnFunc(ctx, statuses)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_ListTicketsInStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTicketsInStatus'
+type MockTicketRepository_ListTicketsInStatus_Call struct {
+	*mock.Call
+}
+
+// ListTicketsInStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - statuses []models.TicketStatus
+func (_e *MockTicketRepository_Expecter) ListTicketsInStatus(ctx interface{}, statuses interface{}) *MockTicketRepository_ListTicketsInStatus_Call {
+	return &MockTicketRepository_ListTicketsInStatus_Call{Call: _e.mock.On("ListTicketsInStatus", ctx, statuses)}
+}
+
+func (_c *MockTicketRepository_ListTicketsInStatus_Call) Run(run func(ctx context.Context, statuses []models.TicketStatus)) *MockTicketRepository_ListTicketsInStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.TicketStatus
+		if args[1] != nil {
+			arg1 = args[1].([]models.TicketStatus)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_ListTicketsInStatus_Call) Return(tenantTickets []models.TenantTicket, err error) *MockTicketRepository_ListTicketsInStatus_Call {
+	_c.Call.Return(tenantTickets, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_ListTicketsInStatus_Call) RunAndReturn(run func(ctx context.Context, statuses []models.TicketStatus) ([]models.TenantTicket, error)) *MockTicketRepository_ListTicketsInStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MoveTicketToQueue provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) MoveTicketToQueue(ctx context.Context, id string, queueID string, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, queueID, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveTicketToQueue")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, queueID, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, queueID, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = returnFunc(ctx, id, queueID, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_MoveTicketToQueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MoveTicketToQueue'
+type MockTicketRepository_MoveTicketToQueue_Call struct {
+	*mock.Call
+}
+
+// MoveTicketToQueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - queueID string
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) MoveTicketToQueue(ctx interface{}, id interface{}, queueID interface{}, expectedVersion interface{}) *MockTicketRepository_MoveTicketToQueue_Call {
+	return &MockTicketRepository_MoveTicketToQueue_Call{Call: _e.mock.On("MoveTicketToQueue", ctx, id, queueID, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_MoveTicketToQueue_Call) Run(run func(ctx context.Context, id string, queueID string, expectedVersion int64)) *MockTicketRepository_MoveTicketToQueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_MoveTicketToQueue_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_MoveTicketToQueue_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_MoveTicketToQueue_Call) RunAndReturn(run func(ctx context.Context, id string, queueID string, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_MoveTicketToQueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PatchTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) PatchTicket(ctx context.Context, id string, patch []byte, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, patch, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTicket")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []byte, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, patch, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []byte, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, patch, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []byte, int64) error); ok {
+		r1 = returnFunc(ctx, id, patch, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_PatchTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchTicket'
+type MockTicketRepository_PatchTicket_Call struct {
+	*mock.Call
+}
+
+// PatchTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - patch []byte
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) PatchTicket(ctx interface{}, id interface{}, patch interface{}, expectedVersion interface{}) *MockTicketRepository_PatchTicket_Call {
+	return &MockTicketRepository_PatchTicket_Call{Call: _e.mock.On("PatchTicket", ctx, id, patch, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_PatchTicket_Call) Run(run func(ctx context.Context, id string, patch []byte, expectedVersion int64)) *MockTicketRepository_PatchTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []byte
+		if args[2] != nil {
+			arg2 = args[2].([]byte)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_PatchTicket_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_PatchTicket_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_PatchTicket_Call) RunAndReturn(run func(ctx context.Context, id string, patch []byte, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_PatchTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) PurgeTicket(ctx context.Context, id string) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTicket")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTicketRepository_PurgeTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeTicket'
+type MockTicketRepository_PurgeTicket_Call struct {
+	*mock.Call
+}
+
+// PurgeTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockTicketRepository_Expecter) PurgeTicket(ctx interface{}, id interface{}) *MockTicketRepository_PurgeTicket_Call {
+	return &MockTicketRepository_PurgeTicket_Call{Call: _e.mock.On("PurgeTicket", ctx, id)}
+}
+
+func (_c *MockTicketRepository_PurgeTicket_Call) Run(run func(ctx context.Context, id string)) *MockTicketRepository_PurgeTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_PurgeTicket_Call) Return(err error) *MockTicketRepository_PurgeTicket_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTicketRepository_PurgeTicket_Call) RunAndReturn(run func(ctx context.Context, id string) error) *MockTicketRepository_PurgeTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) RestoreTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTicket")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_RestoreTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreTicket'
+type MockTicketRepository_RestoreTicket_Call struct {
+	*mock.Call
+}
+
+// RestoreTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockTicketRepository_Expecter) RestoreTicket(ctx interface{}, id interface{}) *MockTicketRepository_RestoreTicket_Call {
+	return &MockTicketRepository_RestoreTicket_Call{Call: _e.mock.On("RestoreTicket", ctx, id)}
+}
+
+func (_c *MockTicketRepository_RestoreTicket_Call) Run(run func(ctx context.Context, id string)) *MockTicketRepository_RestoreTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_RestoreTicket_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_RestoreTicket_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_RestoreTicket_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Ticket, error)) *MockTicketRepository_RestoreTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAssignTo provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) UpdateAssignTo(ctx context.Context, id string, assignTo string, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, assignTo, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAssignTo")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, assignTo, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, assignTo, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = returnFunc(ctx, id, assignTo, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_UpdateAssignTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAssignTo'
+type MockTicketRepository_UpdateAssignTo_Call struct {
+	*mock.Call
+}
+
+// UpdateAssignTo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - assignTo string
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) UpdateAssignTo(ctx interface{}, id interface{}, assignTo interface{}, expectedVersion interface{}) *MockTicketRepository_UpdateAssignTo_Call {
+	return &MockTicketRepository_UpdateAssignTo_Call{Call: _e.mock.On("UpdateAssignTo", ctx, id, assignTo, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_UpdateAssignTo_Call) Run(run func(ctx context.Context, id string, assignTo string, expectedVersion int64)) *MockTicketRepository_UpdateAssignTo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateAssignTo_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_UpdateAssignTo_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateAssignTo_Call) RunAndReturn(run func(ctx context.Context, id string, assignTo string, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_UpdateAssignTo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePriority provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) UpdatePriority(ctx context.Context, id string, priority models.TicketPriority, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, priority, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePriority")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.TicketPriority, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, priority, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.TicketPriority, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, priority, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.TicketPriority, int64) error); ok {
+		r1 = returnFunc(ctx, id, priority, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_UpdatePriority_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePriority'
+type MockTicketRepository_UpdatePriority_Call struct {
+	*mock.Call
+}
+
+// UpdatePriority is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - priority models.TicketPriority
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) UpdatePriority(ctx interface{}, id interface{}, priority interface{}, expectedVersion interface{}) *MockTicketRepository_UpdatePriority_Call {
+	return &MockTicketRepository_UpdatePriority_Call{Call: _e.mock.On("UpdatePriority", ctx, id, priority, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_UpdatePriority_Call) Run(run func(ctx context.Context, id string, priority models.TicketPriority, expectedVersion int64)) *MockTicketRepository_UpdatePriority_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.TicketPriority
+		if args[2] != nil {
+			arg2 = args[2].(models.TicketPriority)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdatePriority_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_UpdatePriority_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdatePriority_Call) RunAndReturn(run func(ctx context.Context, id string, priority models.TicketPriority, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_UpdatePriority_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateStatus provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) UpdateStatus(ctx context.Context, id string, status string, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, status, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, status, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, status, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = returnFunc(ctx, id, status, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_UpdateStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStatus'
+type MockTicketRepository_UpdateStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - status string
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) UpdateStatus(ctx interface{}, id interface{}, status interface{}, expectedVersion interface{}) *MockTicketRepository_UpdateStatus_Call {
+	return &MockTicketRepository_UpdateStatus_Call{Call: _e.mock.On("UpdateStatus", ctx, id, status, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_UpdateStatus_Call) Run(run func(ctx context.Context, id string, status string, expectedVersion int64)) *MockTicketRepository_UpdateStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateStatus_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_UpdateStatus_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateStatus_Call) RunAndReturn(run func(ctx context.Context, id string, status string, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_UpdateStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTags provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) UpdateTags(ctx context.Context, id string, add []string, remove []string, expectedVersion int64) (*models.Ticket, error) {
+	ret := _mock.Called(ctx, id, add, remove, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTags")
+	}
+
+	var r0 *models.Ticket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string, []string, int64) (*models.Ticket, error)); ok {
+		return returnFunc(ctx, id, add, remove, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string, []string, int64) *models.Ticket); ok {
+		r0 = returnFunc(ctx, id, add, remove, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []string, []string, int64) error); ok {
+		r1 = returnFunc(ctx, id, add, remove, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTicketRepository_UpdateTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTags'
+type MockTicketRepository_UpdateTags_Call struct {
+	*mock.Call
+}
+
+// UpdateTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - add []string
+//   - remove []string
+//   - expectedVersion int64
+func (_e *MockTicketRepository_Expecter) UpdateTags(ctx interface{}, id interface{}, add interface{}, remove interface{}, expectedVersion interface{}) *MockTicketRepository_UpdateTags_Call {
+	return &MockTicketRepository_UpdateTags_Call{Call: _e.mock.On("UpdateTags", ctx, id, add, remove, expectedVersion)}
+}
+
+func (_c *MockTicketRepository_UpdateTags_Call) Run(run func(ctx context.Context, id string, add []string, remove []string, expectedVersion int64)) *MockTicketRepository_UpdateTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		var arg3 []string
+		if args[3] != nil {
+			arg3 = args[3].([]string)
+		}
+		var arg4 int64
+		if args[4] != nil {
+			arg4 = args[4].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateTags_Call) Return(ticket *models.Ticket, err error) *MockTicketRepository_UpdateTags_Call {
+	_c.Call.Return(ticket, err)
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateTags_Call) RunAndReturn(run func(ctx context.Context, id string, add []string, remove []string, expectedVersion int64) (*models.Ticket, error)) *MockTicketRepository_UpdateTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTicket provides a mock function for the type MockTicketRepository
+func (_mock *MockTicketRepository) UpdateTicket(ctx context.Context, ticket *models.Ticket) error {
+	ret := _mock.Called(ctx, ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTicket")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Ticket) error); ok {
+		r0 = returnFunc(ctx, ticket)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTicketRepository_UpdateTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTicket'
+type MockTicketRepository_UpdateTicket_Call struct {
+	*mock.Call
+}
+
+// UpdateTicket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ticket *models.Ticket
+func (_e *MockTicketRepository_Expecter) UpdateTicket(ctx interface{}, ticket interface{}) *MockTicketRepository_UpdateTicket_Call {
+	return &MockTicketRepository_UpdateTicket_Call{Call: _e.mock.On("UpdateTicket", ctx, ticket)}
+}
+
+func (_c *MockTicketRepository_UpdateTicket_Call) Run(run func(ctx context.Context, ticket *models.Ticket)) *MockTicketRepository_UpdateTicket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Ticket
+		if args[1] != nil {
+			arg1 = args[1].(*models.Ticket)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateTicket_Call) Return(err error) *MockTicketRepository_UpdateTicket_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTicketRepository_UpdateTicket_Call) RunAndReturn(run func(ctx context.Context, ticket *models.Ticket) error) *MockTicketRepository_UpdateTicket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockWebhookRepository creates a new instance of MockWebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWebhookRepository {
+	mock := &MockWebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockWebhookRepository is an autogenerated mock type for the WebhookRepository type
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+type MockWebhookRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockWebhookRepository) EXPECT() *MockWebhookRepository_Expecter {
+	return &MockWebhookRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddDeadLetter provides a mock function for the type MockWebhookRepository
+func (_mock *MockWebhookRepository) AddDeadLetter(ctx context.Context, letter *models.WebhookDeadLetter) error {
+	ret := _mock.Called(ctx, letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDeadLetter")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WebhookDeadLetter) error); ok {
+		r0 = returnFunc(ctx, letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockWebhookRepository_AddDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddDeadLetter'
+type MockWebhookRepository_AddDeadLetter_Call struct {
+	*mock.Call
+}
+
+// AddDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - letter *models.WebhookDeadLetter
+func (_e *MockWebhookRepository_Expecter) AddDeadLetter(ctx interface{}, letter interface{}) *MockWebhookRepository_AddDeadLetter_Call {
+	return &MockWebhookRepository_AddDeadLetter_Call{Call: _e.mock.On("AddDeadLetter", ctx, letter)}
+}
+
+func (_c *MockWebhookRepository_AddDeadLetter_Call) Run(run func(ctx context.Context, letter *models.WebhookDeadLetter)) *MockWebhookRepository_AddDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.WebhookDeadLetter
+		if args[1] != nil {
+			arg1 = args[1].(*models.WebhookDeadLetter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWebhookRepository_AddDeadLetter_Call) Return(err error) *MockWebhookRepository_AddDeadLetter_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockWebhookRepository_AddDeadLetter_Call) RunAndReturn(run func(ctx context.Context, letter *models.WebhookDeadLetter) error) *MockWebhookRepository_AddDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWebhook provides a mock function for the type MockWebhookRepository
+func (_mock *MockWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.WebhookSubscription) error {
+	ret := _mock.Called(ctx, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WebhookSubscription) error); ok {
+		r0 = returnFunc(ctx, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockWebhookRepository_CreateWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhook'
+type MockWebhookRepository_CreateWebhook_Call struct {
+	*mock.Call
+}
+
+// CreateWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - webhook *models.WebhookSubscription
+func (_e *MockWebhookRepository_Expecter) CreateWebhook(ctx interface{}, webhook interface{}) *MockWebhookRepository_CreateWebhook_Call {
+	return &MockWebhookRepository_CreateWebhook_Call{Call: _e.mock.On("CreateWebhook", ctx, webhook)}
+}
+
+func (_c *MockWebhookRepository_CreateWebhook_Call) Run(run func(ctx context.Context, webhook *models.WebhookSubscription)) *MockWebhookRepository_CreateWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.WebhookSubscription
+		if args[1] != nil {
+			arg1 = args[1].(*models.WebhookSubscription)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWebhookRepository_CreateWebhook_Call) Return(err error) *MockWebhookRepository_CreateWebhook_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockWebhookRepository_CreateWebhook_Call) RunAndReturn(run func(ctx context.Context, webhook *models.WebhookSubscription) error) *MockWebhookRepository_CreateWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhook provides a mock function for the type MockWebhookRepository
+func (_mock *MockWebhookRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+	ret := _mock.Called(ctx, webhookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockWebhookRepository_DeleteWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhook'
+type MockWebhookRepository_DeleteWebhook_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - webhookID string
+func (_e *MockWebhookRepository_Expecter) DeleteWebhook(ctx interface{}, webhookID interface{}) *MockWebhookRepository_DeleteWebhook_Call {
+	return &MockWebhookRepository_DeleteWebhook_Call{Call: _e.mock.On("DeleteWebhook", ctx, webhookID)}
+}
+
+func (_c *MockWebhookRepository_DeleteWebhook_Call) Run(run func(ctx context.Context, webhookID string)) *MockWebhookRepository_DeleteWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWebhookRepository_DeleteWebhook_Call) Return(err error) *MockWebhookRepository_DeleteWebhook_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockWebhookRepository_DeleteWebhook_Call) RunAndReturn(run func(ctx context.Context, webhookID string) error) *MockWebhookRepository_DeleteWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeadLetters provides a mock function for the type MockWebhookRepository
+func (_mock *MockWebhookRepository) ListDeadLetters(ctx context.Context, page models.PageRequest) (*models.WebhookDeadLetterPage, error) {
+	ret := _mock.Called(ctx, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeadLetters")
+	}
+
+	var r0 *models.WebhookDeadLetterPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) (*models.WebhookDeadLetterPage, error)); ok {
+		return returnFunc(ctx, page)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PageRequest) *models.WebhookDeadLetterPage); ok {
+		r0 = returnFunc(ctx, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDeadLetterPage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PageRequest) error); ok {
+		r1 = returnFunc(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockWebhookRepository_ListDeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeadLetters'
+type MockWebhookRepository_ListDeadLetters_Call struct {
+	*mock.Call
+}
+
+// ListDeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page models.PageRequest
+func (_e *MockWebhookRepository_Expecter) ListDeadLetters(ctx interface{}, page interface{}) *MockWebhookRepository_ListDeadLetters_Call {
+	return &MockWebhookRepository_ListDeadLetters_Call{Call: _e.mock.On("ListDeadLetters", ctx, page)}
+}
+
+func (_c *MockWebhookRepository_ListDeadLetters_Call) Run(run func(ctx context.Context, page models.PageRequest)) *MockWebhookRepository_ListDeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.PageRequest
+		if args[1] != nil {
+			arg1 = args[1].(models.PageRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWebhookRepository_ListDeadLetters_Call) Return(webhookDeadLetterPage *models.WebhookDeadLetterPage, err error) *MockWebhookRepository_ListDeadLetters_Call {
+	_c.Call.Return(webhookDeadLetterPage, err)
+	return _c
+}
+
+func (_c *MockWebhookRepository_ListDeadLetters_Call) RunAndReturn(run func(ctx context.Context, page models.PageRequest) (*models.WebhookDeadLetterPage, error)) *MockWebhookRepository_ListDeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWebhooks provides a mock function for the type MockWebhookRepository
+func (_mock *MockWebhookRepository) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhooks")
+	}
+
+	var r0 []models.WebhookSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.WebhookSubscription, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.WebhookSubscription); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookSubscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockWebhookRepository_ListWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebhooks'
+type MockWebhookRepository_ListWebhooks_Call struct {
+	*mock.Call
+}
+
+// ListWebhooks is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockWebhookRepository_Expecter) ListWebhooks(ctx interface{}) *MockWebhookRepository_ListWebhooks_Call {
+	return &MockWebhookRepository_ListWebhooks_Call{Call: _e.mock.On("ListWebhooks", ctx)}
+}
+
+func (_c *MockWebhookRepository_ListWebhooks_Call) Run(run func(ctx context.Context)) *MockWebhookRepository_ListWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWebhookRepository_ListWebhooks_Call) Return(webhookSubscriptions []models.WebhookSubscription, err error) *MockWebhookRepository_ListWebhooks_Call {
+	_c.Call.Return(webhookSubscriptions, err)
+	return _c
+}
+
+func (_c *MockWebhookRepository_ListWebhooks_Call) RunAndReturn(run func(ctx context.Context) ([]models.WebhookSubscription, error)) *MockWebhookRepository_ListWebhooks_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// All tenants share the outbox partition, the event carries the tenant
+const outboxPK = "#outbox"
+
+var (
+	ErrLoadingOutbox = errors.New("error loading outbox")
+	ErrSavingOutbox  = errors.New("error saving outbox")
+)
+
+// Events are written to the outbox by the repositories in the same
+// transaction as the change they describe, and removed once relayed. The
+// <timestamp>#<id> sort key keeps them in commit order.
+type OutboxRepository interface {
+	// Returns up to limit entries with a key greater than after, oldest first
+	ListOutbox(ctx context.Context, after string, limit int) ([]models.OutboxEntry, error)
+	DeleteOutboxEntry(ctx context.Context, key string) error
+}
+
+func newEvent(ctx context.Context, eventType models.EventType, subject string, sequence int64, data any) (*models.Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		Tenant:     identity.Tenant(ctx),
+		Actor:      identity.Actor(ctx),
+		OccurredAt: models.FormatSortableTime(time.Now()),
+		Subject:    subject,
+		Sequence:   sequence,
+		Data:       payload,
+	}, nil
+}
+
+func newTicketEvent(ctx context.Context, eventType models.EventType, ticket *models.Ticket) (*models.Event, error) {
+	return newEvent(ctx, eventType, ticket.TicketID, ticket.Version, models.TicketEventData{Ticket: ticket})
+}
+
+// The event raised by a ticket change, history actions without one are not
+// published
+func ticketActionEvent(action string) (models.EventType, bool) {
+	switch action {
+	case models.ActionCreated:
+		return models.EventTicketCreated, true
+	case models.ActionStatusChanged:
+		return models.EventTicketStatusChanged, true
+	case models.ActionAssigned:
+		return models.EventTicketAssigned, true
+	}
+	return "", false
+}
+
+func outboxKey(event *models.Event) string {
+	return fmt.Sprintf("%s#%s", event.OccurredAt, event.ID)
+}
+
+// Builds the put adding the event to the outbox, to be included in the
+// transaction of the change
+func outboxPut(event *models.Event) (*types.Put, error) {
+	item, err := attributevalue.MarshalMap(models.OutboxDbRecord{
+		OutboxEntry: models.OutboxEntry{Key: outboxKey(event), Event: *event},
+		PK:          outboxPK,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &types.Put{
+		TableName:           aws.String(TableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}, nil
+}
+
+func (tr *ticketRepository) ListOutbox(ctx context.Context, after string, limit int) ([]models.OutboxEntry, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: outboxPK},
+		},
+		// events committed just before the read must not be skipped
+		ConsistentRead: aws.Bool(true),
+		Limit:          aws.Int32(int32(limit)),
+	}
+	if after != "" {
+		input.KeyConditionExpression = aws.String("PK = :pk AND SK > :after")
+		input.ExpressionAttributeValues[":after"] = &types.AttributeValueMemberS{Value: after}
+	}
+
+	result, err := tr.client.Query(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingOutbox, err)
+	}
+	entries := make([]models.OutboxEntry, 0, len(result.Items))
+	for _, item := range result.Items {
+		var record models.OutboxDbRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingOutbox, err)
+		}
+		entries = append(entries, record.OutboxEntry)
+	}
+	return entries, nil
+}
+
+func (tr *ticketRepository) DeleteOutboxEntry(ctx context.Context, key string) error {
+	_, err := tr.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: outboxPK},
+			"SK": &types.AttributeValueMemberS{Value: key},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingOutbox, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"os"
+
+	"example.com/ticket-system/internal/workflow"
+)
+
+// Repositories groups the storage interfaces used by the API. Both backends
+// implement all of them on a single type.
+type Repositories struct {
+	Tickets    TicketRepository
+	Comments   CommentRepository
+	History    HistoryRepository
+	ImportJobs ImportJobRepository
+	Webhooks   WebhookRepository
+	Outbox     OutboxRepository
+	Assignment AssignmentRepository
+	Teams      TeamRepository
+}
+
+// NewFromEnv picks the repository implementation.
+// TICKET_REPOSITORY=memory runs the API without a DynamoDB endpoint.
+func NewFromEnv(ctx context.Context, wf *workflow.Workflow) Repositories {
+	if os.Getenv("TICKET_REPOSITORY") == "memory" {
+		repo := NewMemoryTicketRepository(wf)
+		return Repositories{Tickets: repo, Comments: repo, History: repo, ImportJobs: repo, Webhooks: repo, Outbox: repo, Assignment: repo, Teams: repo}
+	}
+	repo := NewTicketRepository(ctx, wf)
+	return Repositories{Tickets: repo, Comments: repo, History: repo, ImportJobs: repo, Webhooks: repo, Outbox: repo, Assignment: repo, Teams: repo}
+}
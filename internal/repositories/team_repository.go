@@ -0,0 +1,317 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	teamSKPrefix  = "team#"
+	queueSKPrefix = "queue#"
+)
+
+var (
+	ErrTeamNotFound   = notFound("team_not_found", "team not found")
+	ErrQueueNotFound  = notFound("queue_not_found", "queue not found")
+	ErrSavingTeam     = errors.New("error saving team")
+	ErrLoadingTeams   = errors.New("error loading teams")
+	ErrTeamInUse      = conflict("team_in_use", "team still works on queues")
+	ErrQueueInUse     = conflict("queue_in_use", "queue still holds tickets")
+	ErrNotQueueMember = invalid("not_queue_member", "assignedTo", "assignee is not a member of the ticket queue")
+	ErrUnknownQueue   = invalid("unknown_queue", "queue", "ticket queue does not exist")
+)
+
+// The teams of a tenant share its #teams partition and its queues the
+// #queues partition, so each kind is listed with a single query.
+type TeamRepository interface {
+	CreateTeam(ctx context.Context, team *models.Team) error
+	GetTeam(ctx context.Context, teamID string) (*models.Team, error)
+	ListTeams(ctx context.Context) ([]models.Team, error)
+	UpdateTeam(ctx context.Context, team *models.Team) error
+	// fails with ErrTeamInUse while a queue belongs to the team
+	DeleteTeam(ctx context.Context, teamID string) error
+	// the team of the queue must exist
+	CreateQueue(ctx context.Context, queue *models.Queue) error
+	GetQueue(ctx context.Context, queueID string) (*models.Queue, error)
+	ListQueues(ctx context.Context) ([]models.Queue, error)
+	UpdateQueue(ctx context.Context, queue *models.Queue) error
+	// fails with ErrQueueInUse while open tickets wait in the queue
+	DeleteQueue(ctx context.Context, queueID string) error
+}
+
+func teamsPK(ctx context.Context) string {
+	return fmt.Sprintf("%s#teams", tenantPrefix(ctx))
+}
+
+func queuesPK(ctx context.Context) string {
+	return fmt.Sprintf("%s#queues", tenantPrefix(ctx))
+}
+
+// Checks the queue of the ticket exists and an individual assignee belongs
+// to the team of the queue
+func checkQueue(ctx context.Context, teams TeamRepository, ticket *models.Ticket) error {
+	if ticket.Queue == "" {
+		return nil
+	}
+	queue, err := teams.GetQueue(ctx, ticket.Queue)
+	if errors.Is(err, ErrQueueNotFound) {
+		// the ticket is at fault, not the queue that is missing
+		return fmt.Errorf("%w - %w", ErrUnknownQueue, err)
+	}
+	if err != nil {
+		return err
+	}
+	if ticket.AssignedTo == "" || ticket.AssignedTo == "None" {
+		return nil
+	}
+	team, err := teams.GetTeam(ctx, queue.TeamID)
+	if err != nil {
+		return err
+	}
+	if !team.HasMember(ticket.AssignedTo) {
+		return fmt.Errorf("%w - %s", ErrNotQueueMember, ticket.AssignedTo)
+	}
+	return nil
+}
+
+// Sets the queue of the ticket, unassigning it when the assignee is not a
+// member of the team of the new queue
+func moveToQueue(ctx context.Context, teams TeamRepository, ticket *models.Ticket, queueID string) error {
+	ticket.Queue = queueID
+	err := checkQueue(ctx, teams, ticket)
+	if errors.Is(err, ErrNotQueueMember) {
+		ticket.AssignedTo = "None"
+		ticket.AssignedBy = ""
+		return nil
+	}
+	return err
+}
+
+// Resolved and closed tickets do not keep a queue from being deleted
+func checkQueueEmpty(ctx context.Context, tickets TicketRepository, queueID string) error {
+	queued, err := tickets.ListQueueTickets(ctx, queueID)
+	if err != nil {
+		return err
+	}
+	for _, ticket := range queued {
+		if ticket.Status != models.StatusResolved && ticket.Status != models.StatusClosed {
+			return fmt.Errorf("%w - %s", ErrQueueInUse, queueID)
+		}
+	}
+	return nil
+}
+
+// Puts the item, only if it exists already when update is set and only if
+// it does not otherwise
+func (tr *ticketRepository) putTeamItem(ctx context.Context, record any, update bool) error {
+	item, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return err
+	}
+	condition := "attribute_not_exists(PK)"
+	if update {
+		condition = "attribute_exists(PK)"
+	}
+	_, err = tr.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(TableName),
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+	})
+	return err
+}
+
+func (tr *ticketRepository) getTeamItem(ctx context.Context, pk string, sk string, record any) (bool, error) {
+	result, err := tr.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w - %w", ErrLoadingTeams, err)
+	}
+	if result.Item == nil {
+		return false, nil
+	}
+	if err := attributevalue.UnmarshalMap(result.Item, record); err != nil {
+		return false, fmt.Errorf("%w - %w", ErrLoadingTeams, err)
+	}
+	return true, nil
+}
+
+func (tr *ticketRepository) queryTeamItems(ctx context.Context, pk string, prefix string, add func(item map[string]types.AttributeValue) error) error {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: pk},
+			":prefix": &types.AttributeValueMemberS{Value: prefix},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrLoadingTeams, err)
+		}
+		for _, item := range page.Items {
+			if err := add(item); err != nil {
+				return fmt.Errorf("%w - %w", ErrLoadingTeams, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (tr *ticketRepository) deleteTeamItem(ctx context.Context, pk string, sk string) error {
+	_, err := tr.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: sk},
+		},
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	})
+	return err
+}
+
+func (tr *ticketRepository) CreateTeam(ctx context.Context, team *models.Team) error {
+	err := tr.putTeamItem(ctx, models.TeamDbRecord{Team: *team, PK: teamsPK(ctx), SK: teamSKPrefix + team.TeamID}, false)
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingTeam, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) GetTeam(ctx context.Context, teamID string) (*models.Team, error) {
+	var record models.TeamDbRecord
+	found, err := tr.getTeamItem(ctx, teamsPK(ctx), teamSKPrefix+teamID, &record)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w - %s", ErrTeamNotFound, teamID)
+	}
+	return &record.Team, nil
+}
+
+func (tr *ticketRepository) ListTeams(ctx context.Context) ([]models.Team, error) {
+	teams := []models.Team{}
+	err := tr.queryTeamItems(ctx, teamsPK(ctx), teamSKPrefix, func(item map[string]types.AttributeValue) error {
+		var record models.TeamDbRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return err
+		}
+		teams = append(teams, record.Team)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (tr *ticketRepository) UpdateTeam(ctx context.Context, team *models.Team) error {
+	err := tr.putTeamItem(ctx, models.TeamDbRecord{Team: *team, PK: teamsPK(ctx), SK: teamSKPrefix + team.TeamID}, true)
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrTeamNotFound, team.TeamID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingTeam, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) DeleteTeam(ctx context.Context, teamID string) error {
+	queues, err := tr.ListQueues(ctx)
+	if err != nil {
+		return err
+	}
+	for _, queue := range queues {
+		if queue.TeamID == teamID {
+			return fmt.Errorf("%w - %s", ErrTeamInUse, queue.QueueID)
+		}
+	}
+	err = tr.deleteTeamItem(ctx, teamsPK(ctx), teamSKPrefix+teamID)
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrTeamNotFound, teamID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingTeam, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) CreateQueue(ctx context.Context, queue *models.Queue) error {
+	if _, err := tr.GetTeam(ctx, queue.TeamID); err != nil {
+		return err
+	}
+	err := tr.putTeamItem(ctx, models.QueueDbRecord{Queue: *queue, PK: queuesPK(ctx), SK: queueSKPrefix + queue.QueueID}, false)
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingTeam, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) GetQueue(ctx context.Context, queueID string) (*models.Queue, error) {
+	var record models.QueueDbRecord
+	found, err := tr.getTeamItem(ctx, queuesPK(ctx), queueSKPrefix+queueID, &record)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w - %s", ErrQueueNotFound, queueID)
+	}
+	return &record.Queue, nil
+}
+
+func (tr *ticketRepository) ListQueues(ctx context.Context) ([]models.Queue, error) {
+	queues := []models.Queue{}
+	err := tr.queryTeamItems(ctx, queuesPK(ctx), queueSKPrefix, func(item map[string]types.AttributeValue) error {
+		var record models.QueueDbRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return err
+		}
+		queues = append(queues, record.Queue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queues, nil
+}
+
+func (tr *ticketRepository) UpdateQueue(ctx context.Context, queue *models.Queue) error {
+	if _, err := tr.GetTeam(ctx, queue.TeamID); err != nil {
+		return err
+	}
+	err := tr.putTeamItem(ctx, models.QueueDbRecord{Queue: *queue, PK: queuesPK(ctx), SK: queueSKPrefix + queue.QueueID}, true)
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrQueueNotFound, queue.QueueID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingTeam, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) DeleteQueue(ctx context.Context, queueID string) error {
+	if err := checkQueueEmpty(ctx, tr, queueID); err != nil {
+		return err
+	}
+	err := tr.deleteTeamItem(ctx, queuesPK(ctx), queueSKPrefix+queueID)
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrQueueNotFound, queueID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingTeam, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"example.com/ticket-system/internal/identity"
+)
+
+// Every key of a tenant starts with its prefix. The default tenant has none
+// so tickets written before tenants existed stay readable.
+func tenantPrefix(ctx context.Context) string {
+	tenant := identity.Tenant(ctx)
+	if tenant == identity.DefaultTenant {
+		return ""
+	}
+	return fmt.Sprintf("#tenant#%s", tenant)
+}
+
+// The ticket partition holds the ticket, its comments and its history. GSI
+// results are filtered on it since the indexes span all tenants.
+func ticketPK(ctx context.Context, id string) string {
+	return fmt.Sprintf("%s#ticket#%s", tenantPrefix(ctx), id)
+}
+
+// ParseTicketKey is the inverse of ticketPK, for readers of raw items such as
+// the table stream. It reports false for keys of other partitions.
+func ParseTicketKey(pk string) (tenant string, ticketID string, ok bool) {
+	prefix, ticketID, found := strings.Cut(pk, "#ticket#")
+	if !found || ticketID == "" {
+		return "", "", false
+	}
+	if prefix == "" {
+		return identity.DefaultTenant, ticketID, true
+	}
+	tenant, found = strings.CutPrefix(prefix, "#tenant#")
+	if !found || tenant == "" {
+		return "", "", false
+	}
+	return tenant, ticketID, true
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var (
+	ErrTicketNotDeleted = conflict("ticket_not_deleted", "ticket is not deleted")
+	ErrPurgingTicket    = errors.New("could not purge ticket")
+)
+
+// Soft deleting keeps the ticket with who deleted it and when, every read
+// then treats it as missing until it is restored
+func markDeleted(ctx context.Context, ticket *models.Ticket, now time.Time) {
+	ticket.DeletedAt = models.FormatSortableTime(now)
+	ticket.DeletedBy = identity.Actor(ctx)
+}
+
+func markRestored(ticket *models.Ticket) error {
+	if !ticket.IsDeleted() {
+		return fmt.Errorf("%w - %s", ErrTicketNotDeleted, ticket.TicketID)
+	}
+	ticket.DeletedAt = ""
+	ticket.DeletedBy = ""
+	return nil
+}
+
+func (tr *ticketRepository) DeleteTicket(ctx context.Context, id string, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := tr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	markDeleted(ctx, ticket, time.Now())
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionDeleted); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (tr *ticketRepository) RestoreTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	ticket, err := tr.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *ticket
+	if err := markRestored(ticket); err != nil {
+		return nil, err
+	}
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionRestored); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// Deletes every item of the ticket partition: the ticket, its comments and
+// its history
+func (tr *ticketRepository) PurgeTicket(ctx context.Context, id string) error {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk"),
+		ProjectionExpression:   aws.String("PK, SK"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: ticketPK(ctx, id)},
+		},
+	})
+
+	var requests []types.WriteRequest
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrPurgingTicket, err)
+		}
+		for _, item := range result.Items {
+			requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: item}})
+		}
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound)
+	}
+
+	for i := 0; i < len(requests); i += maxBatchWriteItems {
+		end := min(i+maxBatchWriteItems, len(requests))
+		unprocessed, err := writeBatch(ctx, tr.client, requests[i:end])
+		if err != nil {
+			return fmt.Errorf("%w - %w", ErrPurgingTicket, err)
+		}
+		if len(unprocessed) > 0 {
+			return fmt.Errorf("%w - %d items left", ErrPurgingTicket, len(unprocessed))
+		}
+	}
+	return nil
+}
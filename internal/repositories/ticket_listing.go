@@ -0,0 +1,266 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Filter condition leaving out soft deleted tickets
+const notDeleted = "attribute_not_exists(deletedAt)"
+
+// ticketQuery is a listing request translated to DynamoDB expressions. The
+// most selective GSI available is queried, the remaining filters are
+// applied as a filter expression. Without an assignee, creator or status the
+// table is scanned and results are not ordered. Results are limited to the
+// ticket partitions of the caller's tenant.
+type ticketQuery struct {
+	index        string
+	keyCondition []string
+	filter       []string
+	names        map[string]string
+	values       map[string]types.AttributeValue
+}
+
+func newTicketQuery(ctx context.Context, filter models.TicketFilter) *ticketQuery {
+	q := &ticketQuery{
+		names:  map[string]string{},
+		values: map[string]types.AttributeValue{},
+	}
+
+	switch {
+	case filter.AssignedTo != "":
+		q.index = "AssignedTo"
+	case filter.CreatedBy != "":
+		q.index = "CreatedBy"
+	case filter.Status != "":
+		q.index = "Status"
+	}
+
+	q.equals("AssignedTo", "assignedTo", filter.AssignedTo)
+	q.equals("CreatedBy", "createdBy", filter.CreatedBy)
+	q.equals("Status", "status", string(filter.Status))
+
+	// every index uses createdAt as range key
+	rangeCondition := &q.filter
+	if q.index != "" {
+		rangeCondition = &q.keyCondition
+	}
+	switch {
+	case !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero():
+		*rangeCondition = append(*rangeCondition, "createdAt BETWEEN :createdFrom AND :createdTo")
+	case !filter.CreatedFrom.IsZero():
+		*rangeCondition = append(*rangeCondition, "createdAt >= :createdFrom")
+	case !filter.CreatedTo.IsZero():
+		*rangeCondition = append(*rangeCondition, "createdAt <= :createdTo")
+	}
+	if !filter.CreatedFrom.IsZero() {
+		q.values[":createdFrom"] = &types.AttributeValueMemberS{Value: models.FormatCreatedAt(filter.CreatedFrom)}
+	}
+	if !filter.CreatedTo.IsZero() {
+		q.values[":createdTo"] = &types.AttributeValueMemberS{Value: models.FormatCreatedAt(filter.CreatedTo)}
+	}
+
+	q.filter = append(q.filter, "begins_with(PK, :ticketPrefix)", notDeleted)
+	q.values[":ticketPrefix"] = &types.AttributeValueMemberS{Value: ticketPK(ctx, "")}
+	if q.index == "" {
+		q.filter = append(q.filter, "SK = :details")
+		q.values[":details"] = &types.AttributeValueMemberS{Value: "details"}
+	}
+	return q
+}
+
+// Adds an equality on attribute, as key condition when it is the index key
+func (q *ticketQuery) equals(index string, attribute string, value string) {
+	if value == "" {
+		return
+	}
+	q.names["#"+attribute] = attribute
+	q.values[":"+attribute] = &types.AttributeValueMemberS{Value: value}
+	condition := fmt.Sprintf("#%s = :%s", attribute, attribute)
+	if q.index == index {
+		q.keyCondition = append(q.keyCondition, condition)
+	} else {
+		q.filter = append(q.filter, condition)
+	}
+}
+
+func (q *ticketQuery) filterExpression() *string {
+	if len(q.filter) == 0 {
+		return nil
+	}
+	return aws.String(strings.Join(q.filter, " AND "))
+}
+
+func (tr *ticketRepository) ListTickets(ctx context.Context, filter models.TicketFilter) (*models.TicketPage, error) {
+	startKey, err := decodeCursor(filter.Page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	q := newTicketQuery(ctx, filter)
+	limit := int(pageSize(filter.Page))
+
+	page := &models.TicketPage{Tickets: []models.Ticket{}}
+	// filter expressions run after Limit is applied, keep reading until the
+	// page is full or the index is exhausted
+	for len(page.Tickets) < limit {
+		var items []map[string]types.AttributeValue
+		if q.index == "" {
+			result, err := tr.client.Scan(ctx, &dynamodb.ScanInput{
+				TableName:                 aws.String(TableName),
+				FilterExpression:          q.filterExpression(),
+				ExpressionAttributeNames:  nilIfEmpty(q.names),
+				ExpressionAttributeValues: q.values,
+				Limit:                     aws.Int32(int32(limit - len(page.Tickets))),
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+			}
+			items, startKey = result.Items, result.LastEvaluatedKey
+		} else {
+			result, err := tr.client.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String(TableName),
+				IndexName:                 aws.String(q.index),
+				KeyConditionExpression:    aws.String(strings.Join(q.keyCondition, " AND ")),
+				FilterExpression:          q.filterExpression(),
+				ExpressionAttributeNames:  nilIfEmpty(q.names),
+				ExpressionAttributeValues: q.values,
+				ScanIndexForward:          aws.Bool(!filter.Page.Descending),
+				Limit:                     aws.Int32(int32(limit - len(page.Tickets))),
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+			}
+			items, startKey = result.Items, result.LastEvaluatedKey
+		}
+
+		for _, item := range items {
+			var record models.TicketDbRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+			}
+			page.Tickets = append(page.Tickets, record.Ticket)
+		}
+		if startKey == nil {
+			break
+		}
+	}
+
+	page.NextCursor, err = encodeCursor(startKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+	}
+	return page, nil
+}
+
+// The sparse SLADue index only holds the tickets with a running deadline,
+// keyed by tenant and by that deadline
+func (tr *ticketRepository) ListTicketsDueBefore(ctx context.Context, before time.Time) ([]models.Ticket, error) {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		IndexName:              aws.String("SLADue"),
+		KeyConditionExpression: aws.String("slaPartition = :partition AND slaDue <= :before"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":partition": &types.AttributeValueMemberS{Value: ticketPK(ctx, "")},
+			":before":    &types.AttributeValueMemberS{Value: models.FormatSortableTime(before)},
+		},
+	})
+
+	tickets := []models.Ticket{}
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrListingDueTickets, err)
+		}
+		for _, item := range result.Items {
+			var record models.TicketDbRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrListingDueTickets, err)
+			}
+			tickets = append(tickets, record.Ticket)
+		}
+	}
+	return tickets, nil
+}
+
+// The sparse OpenPriority index only holds the tickets that are neither
+// resolved nor closed, keyed by tenant and priority
+func (tr *ticketRepository) ListOpenTickets(ctx context.Context, priority models.TicketPriority) ([]models.Ticket, error) {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		IndexName:              aws.String("OpenPriority"),
+		KeyConditionExpression: aws.String("openPriority = :partition"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":partition": &types.AttributeValueMemberS{Value: openPriorityKey(ctx, priority)},
+		},
+	})
+
+	tickets := []models.Ticket{}
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+		}
+		for _, item := range result.Items {
+			var record models.TicketDbRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+			}
+			tickets = append(tickets, record.Ticket)
+		}
+	}
+	return tickets, nil
+}
+
+// Queries the Status index once per status. The index spans all tenants,
+// the tenant of each ticket is read back from its key.
+func (tr *ticketRepository) ListTicketsInStatus(ctx context.Context, statuses []models.TicketStatus) ([]models.TenantTicket, error) {
+	tickets := []models.TenantTicket{}
+	for _, status := range statuses {
+		paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+			TableName:              aws.String(TableName),
+			IndexName:              aws.String("Status"),
+			KeyConditionExpression: aws.String("#status = :status"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":status": &types.AttributeValueMemberS{Value: string(status)},
+			},
+		})
+		for paginator.HasMorePages() {
+			result, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+			}
+			for _, item := range result.Items {
+				var record models.TicketDbRecord
+				if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+					return nil, fmt.Errorf("%w - %w", ErrListingTickets, err)
+				}
+				tenant, _, ok := ParseTicketKey(record.PK)
+				if !ok || record.SK != "details" || record.IsDeleted() {
+					continue
+				}
+				tickets = append(tickets, models.TenantTicket{Tenant: tenant, Ticket: record.Ticket})
+			}
+		}
+	}
+	return tickets, nil
+}
+
+func nilIfEmpty(names map[string]string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTicketQuery(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		filter         models.TicketFilter
+		index          string
+		keyCondition   []string
+		expectedFilter []string
+	}{
+		{
+			name:           "assignee uses the AssignedTo index",
+			filter:         models.TicketFilter{AssignedTo: "andrew", Status: models.StatusOpen, CreatedFrom: from},
+			index:          "AssignedTo",
+			keyCondition:   []string{"#assignedTo = :assignedTo", "createdAt >= :createdFrom"},
+			expectedFilter: []string{"#status = :status", "begins_with(PK, :ticketPrefix)", "attribute_not_exists(deletedAt)"},
+		},
+		{
+			name:           "creator uses the CreatedBy index",
+			filter:         models.TicketFilter{CreatedBy: "hugo"},
+			index:          "CreatedBy",
+			keyCondition:   []string{"#createdBy = :createdBy"},
+			expectedFilter: []string{"begins_with(PK, :ticketPrefix)", "attribute_not_exists(deletedAt)"},
+		},
+		{
+			name:           "status uses the Status index",
+			filter:         models.TicketFilter{Status: models.StatusClosed, CreatedFrom: from, CreatedTo: from.Add(time.Hour)},
+			index:          "Status",
+			keyCondition:   []string{"#status = :status", "createdAt BETWEEN :createdFrom AND :createdTo"},
+			expectedFilter: []string{"begins_with(PK, :ticketPrefix)", "attribute_not_exists(deletedAt)"},
+		},
+		{
+			name:           "no key filter scans ticket details",
+			filter:         models.TicketFilter{CreatedTo: from},
+			expectedFilter: []string{"createdAt <= :createdTo", "begins_with(PK, :ticketPrefix)", "attribute_not_exists(deletedAt)", "SK = :details"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTicketQuery(context.Background(), tt.filter)
+			assert.Equal(t, tt.index, q.index)
+			assert.Equal(t, tt.keyCondition, q.keyCondition)
+			assert.Equal(t, tt.expectedFilter, q.filter)
+			assert.Equal(t, &types.AttributeValueMemberS{Value: "#ticket#"}, q.values[":ticketPrefix"])
+		})
+	}
+}
+
+func TestNewTicketQueryTenant(t *testing.T) {
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo", Tenant: "acme"})
+	q := newTicketQuery(ctx, models.TicketFilter{AssignedTo: "andrew"})
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "#tenant#acme#ticket#"}, q.values[":ticketPrefix"])
+}
+
+func TestNewTicketDbRecordOpenPriority(t *testing.T) {
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "hugo", Tenant: "acme"})
+	ticket := &models.Ticket{TicketID: "1234", Status: models.StatusOpen, Priority: models.PriorityUrgent}
+	assert.Equal(t, "#tenant#acme#priority#URGENT", newTicketDbRecord(ctx, ticket).OpenPriority)
+	assert.Equal(t, "#priority#URGENT", newTicketDbRecord(context.Background(), ticket).OpenPriority)
+
+	// the index only holds open tickets with a priority
+	ticket.Status = models.StatusClosed
+	assert.Empty(t, newTicketDbRecord(ctx, ticket).OpenPriority)
+	assert.Empty(t, newTicketDbRecord(ctx, &models.Ticket{TicketID: "1235", Status: models.StatusOpen}).OpenPriority)
+}
+
+func TestTicketDbRecordCreatedAt(t *testing.T) {
+	// the range key of the GSIs sorts as a string, the values have a fixed width
+	ticket := &models.Ticket{TicketID: "1234", CreatedAt: models.NewTimestamp(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))}
+	item, err := attributevalue.MarshalMap(newTicketDbRecord(context.Background(), ticket))
+	require.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "2024-03-01T10:00:00.000000000Z"}, item["createdAt"])
+
+	var record models.TicketDbRecord
+	require.NoError(t, attributevalue.UnmarshalMap(item, &record))
+	assert.Equal(t, ticket.CreatedAt, record.CreatedAt)
+
+	// items written before the values were RFC 3339
+	item["createdAt"] = &types.AttributeValueMemberS{Value: "2024-03-01 10:00:00.5 +0000 UTC"}
+	require.NoError(t, attributevalue.UnmarshalMap(item, &record))
+	assert.True(t, record.CreatedAt.Equal(time.Date(2024, 3, 1, 10, 0, 0, 500000000, time.UTC)))
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Applies the merge patch to the ticket and checks the result the way the
+// dedicated updates do: a new status must follow the workflow and a queued
+// ticket stays with the members of its team. Also returns the history
+// action of the change, the status taking precedence over the assignee.
+func applyTicketPatch(ctx context.Context, wf *workflow.Workflow, teams TeamRepository, ticket *models.Ticket, patch []byte) (*models.Ticket, string, error) {
+	patched, err := models.MergePatch(ticket, patch)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w - %w", ErrInvalidTicket, err)
+	}
+	patched.Tags = models.NormalizeTags(patched.Tags)
+	if err := patched.ValidateFields(); err != nil {
+		return nil, "", fmt.Errorf("%w - %w", ErrInvalidTicket, err)
+	}
+
+	action := models.ActionUpdated
+	if patched.AssignedTo != ticket.AssignedTo {
+		patched.AssignedBy = ""
+		action = models.ActionAssigned
+	}
+	if patched.Queue != ticket.Queue || patched.AssignedTo != ticket.AssignedTo {
+		if err := checkQueue(ctx, teams, patched); err != nil {
+			return nil, "", err
+		}
+	}
+	if patched.Status != ticket.Status {
+		// the guards see the patched ticket, a patch may assign the ticket
+		// and start working on it at once
+		to := patched.Status
+		patched.Status = ticket.Status
+		if err := wf.Transition(patched, to); err != nil {
+			return nil, "", fmt.Errorf("%w - %w", ErrInvalidStatus, err)
+		}
+		patched.SLAStatusChanged(ticket.Status, time.Now())
+		action = models.ActionStatusChanged
+	}
+	return patched, action, nil
+}
+
+// Builds the update writing only the attributes of the stored record that
+// differ from before, including the keys of the sparse indexes, on the
+// condition that the stored version is still before.Version. Also returns
+// the ticket as it will be stored.
+func ticketUpdate(ctx context.Context, before *models.Ticket, ticket *models.Ticket) (*types.Update, *models.Ticket, error) {
+	current, err := attributevalue.MarshalMap(newTicketDbRecord(ctx, before))
+	if err != nil {
+		return nil, nil, err
+	}
+	record := newTicketDbRecord(ctx, ticket)
+	record.Version = before.Version + 1
+	next, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := map[string]string{"#version": "version"}
+	values := map[string]types.AttributeValue{
+		":expectedVersion": &types.AttributeValueMemberN{Value: strconv.FormatInt(before.Version, 10)},
+	}
+	var set, remove []string
+	for i, attribute := range changedAttributes(current, next) {
+		name := fmt.Sprintf("#a%d", i)
+		names[name] = attribute
+		if value, ok := next[attribute]; ok {
+			values[fmt.Sprintf(":a%d", i)] = value
+			set = append(set, fmt.Sprintf("%s = :a%d", name, i))
+		} else {
+			remove = append(remove, name)
+		}
+	}
+	// the version always changes
+	expression := "SET " + strings.Join(set, ", ")
+	if len(remove) > 0 {
+		expression += " REMOVE " + strings.Join(remove, ", ")
+	}
+
+	// records written before versioning was introduced have no version attribute
+	condition := "attribute_exists(PK) AND #version = :expectedVersion"
+	if before.Version == 0 {
+		condition = "attribute_exists(PK) AND (attribute_not_exists(#version) OR #version = :expectedVersion)"
+	}
+	return &types.Update{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": next["PK"],
+			"SK": next["SK"],
+		},
+		UpdateExpression:          aws.String(expression),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}, &record.Ticket, nil
+}
+
+// Names of the attributes added, changed or removed from current to next,
+// sorted so the expressions are stable
+func changedAttributes(current map[string]types.AttributeValue, next map[string]types.AttributeValue) []string {
+	var changed []string
+	for name, value := range next {
+		if !reflect.DeepEqual(current[name], value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range current {
+		if _, ok := next[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketUpdate(t *testing.T) {
+	before := &models.Ticket{
+		TicketID:    "1234",
+		Description: "printer",
+		Status:      models.StatusOpen,
+		CreatedBy:   "hugo",
+		AssignedTo:  "andrew",
+		Priority:    models.PriorityHigh,
+		Tags:        []string{"hardware"},
+		Version:     3,
+	}
+	after := *before
+	after.Status = models.StatusClosed
+	after.Tags = nil
+
+	update, saved, err := ticketUpdate(context.Background(), before, &after)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), saved.Version)
+	// closing the ticket takes it out of the OpenPriority index
+	assert.Equal(t, "SET #a1 = :a1, #a3 = :a3 REMOVE #a0, #a2", *update.UpdateExpression)
+	assert.Equal(t, map[string]string{
+		"#version": "version",
+		"#a0":      "openPriority",
+		"#a1":      "status",
+		"#a2":      "tags",
+		"#a3":      "version",
+	}, update.ExpressionAttributeNames)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "CLOSED"}, update.ExpressionAttributeValues[":a1"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "4"}, update.ExpressionAttributeValues[":a3"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "3"}, update.ExpressionAttributeValues[":expectedVersion"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "#ticket#1234"}, update.Key["PK"])
+}
+
+func TestApplyTicketPatch(t *testing.T) {
+	repo := NewMemoryTicketRepository(workflow.Default())
+	ticket := &models.Ticket{
+		TicketID:    "1234",
+		Description: "printer",
+		Status:      models.StatusOpen,
+		AssignedTo:  "None",
+		Priority:    models.PriorityLow,
+		Version:     1,
+	}
+
+	patched, action, err := applyTicketPatch(context.Background(), repo.workflow, repo, ticket, []byte(`{"Description": "vpn", "Tags": ["vpn", "vpn "], "Priority": null}`))
+	require.NoError(t, err)
+	assert.Equal(t, models.ActionUpdated, action)
+	assert.Equal(t, "vpn", patched.Description)
+	assert.Equal(t, []string{"vpn"}, patched.Tags)
+	assert.Empty(t, patched.Priority)
+	assert.Equal(t, models.PriorityLow, ticket.Priority)
+
+	// the guards of the transition see the new assignee
+	_, _, err = applyTicketPatch(context.Background(), repo.workflow, repo, ticket, []byte(`{"Status": "RESOLVED"}`))
+	var transitionErr *workflow.TransitionError
+	assert.ErrorAs(t, err, &transitionErr)
+	patched, action, err = applyTicketPatch(context.Background(), repo.workflow, repo, ticket, []byte(`{"Status": "RESOLVED", "AssignedTo": "andrew"}`))
+	require.NoError(t, err)
+	assert.Equal(t, models.ActionStatusChanged, action)
+	assert.Equal(t, models.StatusResolved, patched.Status)
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected error
+	}{
+		{"not an object", `["Description"]`, models.ErrInvalidPatch},
+		{"unknown field", `{"Owner": "andrew"}`, models.ErrInvalidPatch},
+		{"ticket id", `{"TicketID": "1235"}`, models.ErrImmutableField},
+		{"version", `{"Version": 7}`, models.ErrImmutableField},
+		{"unknown severity", `{"Severity": "SEV1"}`, models.ErrInvalidTicket},
+		{"no description", `{"Description": null}`, models.ErrInvalidTicket},
+		{"created at", `{"CreatedAt": "2024-03-01T10:00:00Z"}`, models.ErrImmutableField},
+		{"unknown queue", `{"Queue": "missing"}`, ErrQueueNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := applyTicketPatch(context.Background(), repo.workflow, repo, ticket, []byte(tt.patch))
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"time"
 
 	models "example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/workflow"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -22,36 +25,89 @@ const (
 var (
 	ErrCreatingTicket        = errors.New("error creating ticket in database")
 	ErrLoadingTicket         = errors.New("error loading ticket from database")
+	ErrTicketNotFound        = notFound("ticket_not_found", "ticket not found")
 	ErrLoadingTicketsForUser = errors.New("could not load user assigned tickets")
-	ErrInvalidStatus         = errors.New("invalid status")
+	ErrLoadingQueueTickets   = errors.New("could not load queue tickets")
+	ErrListingTickets        = errors.New("could not list tickets")
+	ErrListingDueTickets     = errors.New("could not list tickets due")
+	ErrInvalidStatus         = invalid("invalid_status", "status", "invalid status")
+	ErrInvalidPriority       = invalid("invalid_priority", "priority", "invalid priority")
+	ErrInvalidTags           = invalid("invalid_tags", "tags", "invalid tags")
+	ErrInvalidTicket         = invalid("invalid_ticket", "", "invalid ticket")
+	ErrVersionConflict       = conflict("version_conflict", "ticket was modified concurrently")
+	ErrPreconditionFailed    = conflict("precondition_failed", "ticket version does not match")
 )
 
 type TicketRepository interface {
 	CreateTicket(ctx context.Context, ticket *models.Ticket) (string, error)
 	GetTicket(ctx context.Context, id string) (*models.Ticket, error)
 	GetTicketsAssignedTo(ctx context.Context, userName string) ([]models.Ticket, error)
-	UpdateStatus(ctx context.Context, id string, status string) error
+	// expectedVersion is the version the caller last read, 0 skips the check
+	UpdateStatus(ctx context.Context, id string, status string, expectedVersion int64) (*models.Ticket, error)
 	UpdateTicket(ctx context.Context, ticket *models.Ticket) error
-	UpdateAssignTo(ctx context.Context, id string, assignTo string) error
-	BulkImport(ctx context.Context, entries []models.Ticket) error
+	UpdateAssignTo(ctx context.Context, id string, assignTo string, expectedVersion int64) (*models.Ticket, error)
+	// returns the tickets that could not be written, the rest are stored
+	BulkImport(ctx context.Context, entries []models.Ticket) ([]models.BulkWriteFailure, error)
+	ListTickets(ctx context.Context, filter models.TicketFilter) (*models.TicketPage, error)
+	// tickets with an SLA deadline running out before the given time, the
+	// earliest first
+	ListTicketsDueBefore(ctx context.Context, before time.Time) ([]models.Ticket, error)
+	// tickets of every tenant in one of the statuses, for system processes
+	// only since it ignores the tenant of ctx
+	ListTicketsInStatus(ctx context.Context, statuses []models.TicketStatus) ([]models.TenantTicket, error)
+	// tickets in the queue, claimed or not, the oldest first
+	ListQueueTickets(ctx context.Context, queueID string) ([]models.Ticket, error)
+	// an empty queueID takes the ticket out of its queue, an assignee outside
+	// the team of the new queue is removed
+	MoveTicketToQueue(ctx context.Context, id string, queueID string, expectedVersion int64) (*models.Ticket, error)
+	// applies an RFC 7386 merge patch to the ticket representation, only
+	// the changed attributes are written
+	PatchTicket(ctx context.Context, id string, patch []byte, expectedVersion int64) (*models.Ticket, error)
+	UpdatePriority(ctx context.Context, id string, priority models.TicketPriority, expectedVersion int64) (*models.Ticket, error)
+	// adds the tags in add the ticket does not have, then removes those in
+	// remove
+	UpdateTags(ctx context.Context, id string, add []string, remove []string, expectedVersion int64) (*models.Ticket, error)
+	// open tickets of the caller's tenant with the priority, the oldest first
+	ListOpenTickets(ctx context.Context, priority models.TicketPriority) ([]models.Ticket, error)
+	// soft deletes the ticket, it is hidden until restored
+	DeleteTicket(ctx context.Context, id string, expectedVersion int64) (*models.Ticket, error)
+	RestoreTicket(ctx context.Context, id string) (*models.Ticket, error)
+	// removes the ticket along with its comments and history, deleted or not
+	PurgeTicket(ctx context.Context, id string) error
 }
 
 type ticketRepository struct {
-	client *dynamodb.Client
+	client   *dynamodb.Client
+	workflow *workflow.Workflow
 }
 
-func NewTicketRepository(ctx context.Context) *ticketRepository {
+func NewTicketRepository(ctx context.Context, wf *workflow.Workflow) *ticketRepository {
 	cfg, _ := config.LoadDefaultConfig(ctx)
 	client := dynamodb.NewFromConfig(cfg)
 	return &ticketRepository{
-		client: client,
+		client:   client,
+		workflow: wf,
 	}
 }
+
+// Soft deleted tickets are not found
 func (tr *ticketRepository) GetTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	ticket, err := tr.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.IsDeleted() {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound)
+	}
+	return ticket, nil
+}
+
+// Reads the ticket, soft deleted or not
+func (tr *ticketRepository) getTicket(ctx context.Context, id string) (*models.Ticket, error) {
 	result, err := tr.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(TableName),
 		Key: map[string]types.AttributeValue{
-			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("#ticket#%s", id)},
+			"PK": &types.AttributeValueMemberS{Value: ticketPK(ctx, id)},
 			"SK": &types.AttributeValueMemberS{Value: "details"},
 		},
 	})
@@ -61,7 +117,7 @@ func (tr *ticketRepository) GetTicket(ctx context.Context, id string) (*models.T
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("%w - ticket not found", ErrLoadingTicket)
+		return nil, fmt.Errorf("%w - %w", ErrLoadingTicket, ErrTicketNotFound)
 	}
 
 	var ticketRecord models.TicketDbRecord
@@ -81,25 +137,32 @@ func (tr *ticketRepository) GetTicketsAssignedTo(ctx context.Context, userName s
 		TableName:              aws.String(TableName),
 		IndexName:              aws.String("AssignedTo"),
 		KeyConditionExpression: aws.String("assignedTo = :assignedTo"),
+		// the index spans all tenants
+		FilterExpression: aws.String("begins_with(PK, :ticketPrefix) AND " + notDeleted),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":assignedTo": &types.AttributeValueMemberS{Value: userName},
+			":assignedTo":   &types.AttributeValueMemberS{Value: userName},
+			":ticketPrefix": &types.AttributeValueMemberS{Value: ticketPK(ctx, "")},
 		},
 	}
 
-	result, err := tr.client.Query(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("%w - %w", ErrLoadingTicketsForUser, err)
-	}
-
 	var ticketRecords []models.TicketDbRecord
 
-	for _, item := range result.Items {
-		var ticketRecord models.TicketDbRecord
-		err = attributevalue.UnmarshalMap(item, &ticketRecord)
+	// a single query returns at most 1 MB, follow LastEvaluatedKey for the rest
+	paginator := dynamodb.NewQueryPaginator(tr.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("%w - %w", ErrLoadingTicket, err)
+			return nil, fmt.Errorf("%w - %w", ErrLoadingTicketsForUser, err)
+		}
+
+		for _, item := range result.Items {
+			var ticketRecord models.TicketDbRecord
+			err = attributevalue.UnmarshalMap(item, &ticketRecord)
+			if err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrLoadingTicket, err)
+			}
+			ticketRecords = append(ticketRecords, ticketRecord)
 		}
-		ticketRecords = append(ticketRecords, ticketRecord)
 	}
 
 	var tickets []models.Ticket = []models.Ticket{}
@@ -115,22 +178,36 @@ func (tr *ticketRepository) GetTicketsAssignedTo(ctx context.Context, userName s
 // Creates a new ticket and returns the ticket id
 func (tr *ticketRepository) CreateTicket(ctx context.Context, ticket *models.Ticket) (string, error) {
 	slog.InfoContext(ctx, "Creating Ticket", "ticket", ticket)
+	if err := checkQueue(ctx, tr, ticket); err != nil {
+		return "", err
+	}
 	if ticket.TicketID == "" {
 		ticket.TicketID = uuid.NewString()
 	}
-	ticketRecord := models.TicketDbRecord{
-		Ticket: *ticket,
-		PK:     fmt.Sprintf("#ticket#%s", ticket.TicketID),
-		SK:     "details",
+	ticket.Version = 1
+	item, err := attributevalue.MarshalMap(newTicketDbRecord(ctx, ticket))
+	if err != nil {
+		return "", fmt.Errorf("%w - %w", ErrCreatingTicket, err)
 	}
-	item, err := attributevalue.MarshalMap(ticketRecord)
+	history, err := historyPut(ctx, models.ActionCreated, nil, ticket)
+	if err != nil {
+		return "", fmt.Errorf("%w - %w", ErrCreatingTicket, err)
+	}
+	event, err := newTicketEvent(ctx, models.EventTicketCreated, ticket)
+	if err != nil {
+		return "", fmt.Errorf("%w - %w", ErrCreatingTicket, err)
+	}
+	outbox, err := outboxPut(event)
 	if err != nil {
 		return "", fmt.Errorf("%w - %w", ErrCreatingTicket, err)
 	}
 
-	_, err = tr.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(TableName),
-		Item:      item,
+	_, err = tr.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{TableName: aws.String(TableName), Item: item}},
+			{Put: history},
+			{Put: outbox},
+		},
 	})
 
 	if err != nil {
@@ -141,107 +218,311 @@ func (tr *ticketRepository) CreateTicket(ctx context.Context, ticket *models.Tic
 
 }
 
-func (tr *ticketRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+func (tr *ticketRepository) UpdateStatus(ctx context.Context, id string, status string, expectedVersion int64) (*models.Ticket, error) {
 	ticket, err := tr.GetTicket(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
 	}
 
-	ticket.Status = models.TicketStatus(status)
-	valid := ticket.ValidateStatus()
-	if !valid {
-		return fmt.Errorf("%s - %s", ErrInvalidStatus, status)
+	before := *ticket
+	if err := tr.workflow.Transition(ticket, models.TicketStatus(status)); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidStatus, err)
+	}
+	ticket.SLAStatusChanged(before.Status, time.Now())
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionStatusChanged); err != nil {
+		return nil, err
 	}
-	return tr.UpdateTicket(ctx, ticket)
+	return ticket, nil
 }
 
-func (tr *ticketRepository) UpdateAssignTo(ctx context.Context, id string, assignTo string) error {
+func (tr *ticketRepository) UpdateAssignTo(ctx context.Context, id string, assignTo string, expectedVersion int64) (*models.Ticket, error) {
 	ticket, err := tr.GetTicket(ctx, id)
 	if err != nil {
 		slog.ErrorContext(ctx, "UpdateAssignTo", "error", err)
-		return err
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
 	}
+	before := *ticket
 	ticket.AssignedTo = assignTo
-	return tr.UpdateTicket(ctx, ticket)
+	ticket.AssignedBy = ""
+	if err := checkQueue(ctx, tr, ticket); err != nil {
+		return nil, err
+	}
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionAssigned); err != nil {
+		return nil, err
+	}
+	return ticket, nil
 }
 
+// Writes the ticket only if the stored version still matches ticket.Version.
+// On success ticket.Version holds the new version.
 func (tr *ticketRepository) UpdateTicket(ctx context.Context, ticket *models.Ticket) error {
 	if ticket.TicketID == "" {
 		return errors.New("ticket ID required")
 	}
-	ticketRecord := models.TicketDbRecord{
-		Ticket: *ticket,
-		PK:     fmt.Sprintf("#ticket#%s", ticket.TicketID),
-		SK:     "details",
+	before, err := tr.GetTicket(ctx, ticket.TicketID)
+	if err != nil {
+		return err
 	}
-	item, err := attributevalue.MarshalMap(ticketRecord)
+	if ticket.Queue != before.Queue || ticket.AssignedTo != before.AssignedTo {
+		if err := checkQueue(ctx, tr, ticket); err != nil {
+			return err
+		}
+	}
+	return tr.saveTicket(ctx, before, ticket, models.ActionUpdated)
+}
+
+func (tr *ticketRepository) MoveTicketToQueue(ctx context.Context, id string, queueID string, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := tr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	if err := moveToQueue(ctx, tr, ticket, queueID); err != nil {
+		return nil, err
+	}
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionQueued); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (tr *ticketRepository) PatchTicket(ctx context.Context, id string, patch []byte, expectedVersion int64) (*models.Ticket, error) {
+	ticket, err := tr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	patched, action, err := applyTicketPatch(ctx, tr.workflow, tr, ticket, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := tr.updateTicket(ctx, ticket, patched, action); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+func (tr *ticketRepository) UpdatePriority(ctx context.Context, id string, priority models.TicketPriority, expectedVersion int64) (*models.Ticket, error) {
+	if !priority.Valid() {
+		return nil, fmt.Errorf("%w - %s", ErrInvalidPriority, priority)
+	}
+	ticket, err := tr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	ticket.Priority = priority
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionPriorityChanged); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (tr *ticketRepository) UpdateTags(ctx context.Context, id string, add []string, remove []string, expectedVersion int64) (*models.Ticket, error) {
+	if err := models.ValidateTags(add); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidTags, err)
+	}
+	ticket, err := tr.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(ticket, expectedVersion); err != nil {
+		return nil, err
+	}
+	before := *ticket
+	ticket.AddTags(add...)
+	ticket.RemoveTags(remove...)
+	if err := tr.saveTicket(ctx, &before, ticket, models.ActionTagged); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (tr *ticketRepository) ListQueueTickets(ctx context.Context, queueID string) ([]models.Ticket, error) {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		IndexName:              aws.String("Queue"),
+		KeyConditionExpression: aws.String("queue = :queue"),
+		// the index spans all tenants
+		FilterExpression: aws.String("begins_with(PK, :ticketPrefix) AND " + notDeleted),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":queue":        &types.AttributeValueMemberS{Value: queueID},
+			":ticketPrefix": &types.AttributeValueMemberS{Value: ticketPK(ctx, "")},
+		},
+	})
+
+	tickets := []models.Ticket{}
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingQueueTickets, err)
+		}
+		for _, item := range result.Items {
+			var record models.TicketDbRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrLoadingQueueTickets, err)
+			}
+			tickets = append(tickets, record.Ticket)
+		}
+	}
+	return tickets, nil
+}
+
+// Conditionally writes the ticket together with the history entry
+// describing the change from before, and the event of the action if it has
+// one
+func (tr *ticketRepository) saveTicket(ctx context.Context, before *models.Ticket, ticket *models.Ticket, action string) error {
+	put, saved, err := ticketPut(ctx, ticket)
 	if err != nil {
 		slog.ErrorContext(ctx, "UpdateTicket MarshallMap", "error", err)
 		return fmt.Errorf("failed to marshal ticket: %w", err)
 	}
+	return tr.writeTicket(ctx, types.TransactWriteItem{Put: put}, before, ticket, saved, action)
+}
 
-	_, err = tr.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(TableName),
-		Item:      item,
-	})
+// Like saveTicket, but only writes the attributes that changed from before
+func (tr *ticketRepository) updateTicket(ctx context.Context, before *models.Ticket, ticket *models.Ticket, action string) error {
+	update, saved, err := ticketUpdate(ctx, before, ticket)
 	if err != nil {
-		slog.ErrorContext(ctx, "PutItem", "error", err)
-		return fmt.Errorf("failed to update ticket: %w", err)
+		return fmt.Errorf("failed to marshal ticket: %w", err)
 	}
-	return nil
+	return tr.writeTicket(ctx, types.TransactWriteItem{Update: update}, before, ticket, saved, action)
 }
 
-func (tr *ticketRepository) BulkImport(ctx context.Context, entries []models.Ticket) error {
-	const batchSize = 40
+// Runs the write of the ticket in a transaction with its history entry and
+// event. On success ticket.Version is the version saved.
+func (tr *ticketRepository) writeTicket(ctx context.Context, write types.TransactWriteItem, before *models.Ticket, ticket *models.Ticket, saved *models.Ticket, action string) error {
+	history, err := historyPut(ctx, action, before, saved)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ticket history: %w", err)
+	}
 
-	// process in batches of batchSize
-	for i := 0; i < len(entries); i += batchSize {
-		end := i + batchSize
-		if end > len(entries) {
-			end = len(entries)
+	items := []types.TransactWriteItem{
+		write,
+		{Put: history},
+	}
+	if eventType, ok := ticketActionEvent(action); ok {
+		event, err := newTicketEvent(ctx, eventType, saved)
+		if err != nil {
+			return fmt.Errorf("failed to marshal ticket event: %w", err)
 		}
-		batch := entries[i:end]
-		err := tr.processBatch(ctx, batch)
+		outbox, err := outboxPut(event)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error processing batch", "error", err)
-			return fmt.Errorf("could not proccess batch from %d to %d", i, end)
+			return fmt.Errorf("failed to marshal ticket event: %w", err)
 		}
+		items = append(items, types.TransactWriteItem{Put: outbox})
 	}
 
+	_, err = tr.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrVersionConflict, ticket.TicketID)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "TransactWriteItems", "error", err)
+		return fmt.Errorf("failed to update ticket: %w", err)
+	}
+	ticket.Version = saved.Version
 	return nil
 }
 
-func (tr *ticketRepository) processBatch(ctx context.Context, batch []models.Ticket) error {
-	var requests []types.WriteRequest
-	for _, entry := range batch {
-
-		ticketRecord := models.TicketDbRecord{
-			Ticket: entry,
-			PK:     fmt.Sprintf("#ticket#%s", entry.TicketID),
-			SK:     "details",
-		}
+// Builds the put writing the next version of the ticket, on the condition
+// that the stored version is still ticket.Version. Also returns the ticket
+// as it will be stored.
+func ticketPut(ctx context.Context, ticket *models.Ticket) (*types.Put, *models.Ticket, error) {
+	expectedVersion := ticket.Version
+	ticketRecord := newTicketDbRecord(ctx, ticket)
+	ticketRecord.Version = expectedVersion + 1
+	item, err := attributevalue.MarshalMap(ticketRecord)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		item, err := attributevalue.MarshalMap(ticketRecord)
+	// records written before versioning was introduced have no version attribute
+	condition := "attribute_exists(PK) AND #version = :expectedVersion"
+	if expectedVersion == 0 {
+		condition = "attribute_exists(PK) AND (attribute_not_exists(#version) OR #version = :expectedVersion)"
+	}
+	return &types.Put{
+		TableName:           aws.String(TableName),
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+		ExpressionAttributeNames: map[string]string{
+			"#version": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":expectedVersion": &types.AttributeValueMemberN{Value: strconv.FormatInt(expectedVersion, 10)},
+		},
+	}, &ticketRecord.Ticket, nil
+}
 
-		if err != nil {
-			return fmt.Errorf("error marshalling record")
-		}
+// Partition of the OpenPriority index holding the open tickets of the
+// caller's tenant with the priority
+func openPriorityKey(ctx context.Context, priority models.TicketPriority) string {
+	return fmt.Sprintf("%s#priority#%s", tenantPrefix(ctx), priority)
+}
 
-		req := types.WriteRequest{
-			PutRequest: &types.PutRequest{
-				Item: item,
-			},
+// Builds the stored form of the ticket, with the keys of the SLA index while
+// one of its deadlines runs and the key of the OpenPriority index while it is
+// open. Soft deleted tickets are left out of both.
+func newTicketDbRecord(ctx context.Context, ticket *models.Ticket) models.TicketDbRecord {
+	record := models.TicketDbRecord{
+		Ticket: *ticket,
+		PK:     ticketPK(ctx, ticket.TicketID),
+		SK:     "details",
+	}
+	if ticket.IsDeleted() {
+		return record
+	}
+	if ticket.SLA != nil {
+		if due := ticket.SLA.NextDue(); due != "" {
+			record.SLAPartition = ticketPK(ctx, "")
+			record.SLADue = due
 		}
-		requests = append(requests, req)
 	}
-	input := &dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]types.WriteRequest{
-			TableName: requests,
-		},
+	if ticket.Priority != "" && ticket.IsOpen() {
+		record.OpenPriority = openPriorityKey(ctx, ticket.Priority)
 	}
-	_, err := tr.client.BatchWriteItem(ctx, input)
-	if err != nil {
-		return fmt.Errorf("batch write failed: %w", err)
+	return record
+}
+
+// returns ErrPreconditionFailed when the caller has a stale copy of the ticket
+func checkVersion(ticket *models.Ticket, expectedVersion int64) error {
+	if expectedVersion != 0 && ticket.Version != expectedVersion {
+		return fmt.Errorf("%w - expected %d, current %d", ErrPreconditionFailed, expectedVersion, ticket.Version)
 	}
 	return nil
 }
+
+// Reports whether a write was rejected by its condition expression, either
+// directly or as part of a cancelled transaction
+func isConditionFailure(err error) bool {
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return true
+	}
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	models "example.com/ticket-system/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	webhookSKPrefix    = "subscription#"
+	deadLetterSKPrefix = "deadletter#"
+)
+
+var (
+	ErrWebhookNotFound = notFound("webhook_not_found", "webhook not found")
+	ErrSavingWebhook   = errors.New("error saving webhook")
+	ErrLoadingWebhooks = errors.New("error loading webhooks")
+)
+
+// The subscriptions of a tenant and its failed deliveries share a single
+// #webhooks partition. Dead letters use a deadletter#<timestamp>#<id> sort
+// key so they are listed in the order they failed.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *models.WebhookSubscription) error
+	ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	AddDeadLetter(ctx context.Context, letter *models.WebhookDeadLetter) error
+	ListDeadLetters(ctx context.Context, page models.PageRequest) (*models.WebhookDeadLetterPage, error)
+}
+
+func webhooksPK(ctx context.Context) string {
+	return fmt.Sprintf("%s#webhooks", tenantPrefix(ctx))
+}
+
+func webhookSK(webhookID string) string {
+	return webhookSKPrefix + webhookID
+}
+
+func deadLetterSK(letter *models.WebhookDeadLetter) string {
+	return fmt.Sprintf("%s%s#%s", deadLetterSKPrefix, letter.FailedAt, letter.DeadLetterID)
+}
+
+func (tr *ticketRepository) CreateWebhook(ctx context.Context, webhook *models.WebhookSubscription) error {
+	item, err := attributevalue.MarshalMap(models.WebhookSubscriptionDbRecord{
+		WebhookSubscription: *webhook,
+		PK:                  webhooksPK(ctx),
+		SK:                  webhookSK(webhook.WebhookID),
+	})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingWebhook, err)
+	}
+	_, err = tr.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(TableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingWebhook, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	paginator := dynamodb.NewQueryPaginator(tr.client, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: webhooksPK(ctx)},
+			":prefix": &types.AttributeValueMemberS{Value: webhookSKPrefix},
+		},
+	})
+
+	webhooks := []models.WebhookSubscription{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingWebhooks, err)
+		}
+		for _, item := range page.Items {
+			var record models.WebhookSubscriptionDbRecord
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				return nil, fmt.Errorf("%w - %w", ErrLoadingWebhooks, err)
+			}
+			webhooks = append(webhooks, record.WebhookSubscription)
+		}
+	}
+	return webhooks, nil
+}
+
+func (tr *ticketRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+	_, err := tr.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: webhooksPK(ctx)},
+			"SK": &types.AttributeValueMemberS{Value: webhookSK(webhookID)},
+		},
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	})
+	if isConditionFailure(err) {
+		return fmt.Errorf("%w - %s", ErrWebhookNotFound, webhookID)
+	}
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingWebhook, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) AddDeadLetter(ctx context.Context, letter *models.WebhookDeadLetter) error {
+	item, err := attributevalue.MarshalMap(models.WebhookDeadLetterDbRecord{
+		WebhookDeadLetter: *letter,
+		PK:                webhooksPK(ctx),
+		SK:                deadLetterSK(letter),
+	})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingWebhook, err)
+	}
+	_, err = tr.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrSavingWebhook, err)
+	}
+	return nil
+}
+
+func (tr *ticketRepository) ListDeadLetters(ctx context.Context, page models.PageRequest) (*models.WebhookDeadLetterPage, error) {
+	startKey, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tr.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":     &types.AttributeValueMemberS{Value: webhooksPK(ctx)},
+			":prefix": &types.AttributeValueMemberS{Value: deadLetterSKPrefix},
+		},
+		ScanIndexForward:  aws.Bool(!page.Descending),
+		Limit:             aws.Int32(pageSize(page)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingWebhooks, err)
+	}
+
+	letters := &models.WebhookDeadLetterPage{DeadLetters: []models.WebhookDeadLetter{}}
+	for _, item := range result.Items {
+		var record models.WebhookDeadLetterDbRecord
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			return nil, fmt.Errorf("%w - %w", ErrLoadingWebhooks, err)
+		}
+		letters.DeadLetters = append(letters.DeadLetters, record.WebhookDeadLetter)
+	}
+	letters.NextCursor, err = encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrLoadingWebhooks, err)
+	}
+	return letters, nil
+}
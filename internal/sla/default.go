@@ -0,0 +1,20 @@
+package sla
+
+import "example.com/ticket-system/internal/models"
+
+var defaultDefinition = Definition{
+	Policies: []PolicyDefinition{
+		{Name: "urgent", Priorities: []models.TicketPriority{models.PriorityUrgent}, FirstResponse: "30m", Resolution: "4h"},
+		{Name: "high", Priorities: []models.TicketPriority{models.PriorityHigh}, FirstResponse: "1h", Resolution: "8h"},
+		{Name: "standard", FirstResponse: "8h", Resolution: "72h"},
+	},
+}
+
+// Default returns the built-in policies, every ticket gets one
+func Default() *Policies {
+	policies, err := New(defaultDefinition)
+	if err != nil {
+		panic(err)
+	}
+	return policies
+}
@@ -0,0 +1,159 @@
+// Package sla selects the service level policy of new tickets and sets
+// their first-response and resolution deadlines. Policies are matched in
+// the order they are defined, by ticket priority and by customer, the tenant
+// the ticket belongs to.
+package sla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidDefinition = errors.New("invalid SLA policy definition")
+
+// Definition is the serializable form of the policies, loaded from JSON or
+// YAML. Durations use the time.ParseDuration syntax, e.g. "4h" or "90m".
+type Definition struct {
+	Policies []PolicyDefinition `json:"policies" yaml:"policies"`
+}
+
+type PolicyDefinition struct {
+	Name string `json:"name" yaml:"name"`
+	// empty matches every priority
+	Priorities []models.TicketPriority `json:"priorities,omitempty" yaml:"priorities,omitempty"`
+	// tenant ids, empty matches every customer
+	Customers     []string `json:"customers,omitempty" yaml:"customers,omitempty"`
+	FirstResponse string   `json:"firstResponse" yaml:"firstResponse"`
+	Resolution    string   `json:"resolution" yaml:"resolution"`
+}
+
+type Policy struct {
+	Name          string
+	priorities    map[models.TicketPriority]bool
+	customers     map[string]bool
+	FirstResponse time.Duration
+	Resolution    time.Duration
+}
+
+func (p *Policy) matches(tenant string, ticket *models.Ticket) bool {
+	if len(p.priorities) > 0 && !p.priorities[ticket.Priority] {
+		return false
+	}
+	if len(p.customers) > 0 && !p.customers[tenant] {
+		return false
+	}
+	return true
+}
+
+type Policies struct {
+	policies []Policy
+}
+
+// Builds the policies, checking every name is unique, every priority known
+// and every duration positive
+func New(def Definition) (*Policies, error) {
+	names := map[string]bool{}
+	policies := &Policies{}
+	for _, policyDef := range def.Policies {
+		if policyDef.Name == "" || names[policyDef.Name] {
+			return nil, fmt.Errorf("%w - missing or duplicate name %q", ErrInvalidDefinition, policyDef.Name)
+		}
+		names[policyDef.Name] = true
+
+		policy := Policy{
+			Name:       policyDef.Name,
+			priorities: make(map[models.TicketPriority]bool),
+			customers:  make(map[string]bool),
+		}
+		for _, priority := range policyDef.Priorities {
+			if !priority.Valid() {
+				return nil, fmt.Errorf("%w - policy %s: unknown priority %s", ErrInvalidDefinition, policyDef.Name, priority)
+			}
+			policy.priorities[priority] = true
+		}
+		for _, customer := range policyDef.Customers {
+			policy.customers[customer] = true
+		}
+		var err error
+		if policy.FirstResponse, err = parseDuration(policyDef.FirstResponse); err != nil {
+			return nil, fmt.Errorf("%w - policy %s firstResponse: %w", ErrInvalidDefinition, policyDef.Name, err)
+		}
+		if policy.Resolution, err = parseDuration(policyDef.Resolution); err != nil {
+			return nil, fmt.Errorf("%w - policy %s resolution: %w", ErrInvalidDefinition, policyDef.Name, err)
+		}
+		policies.policies = append(policies.policies, policy)
+	}
+	return policies, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("must be positive")
+	}
+	return d, nil
+}
+
+// Reads the policies from a .json, .yaml or .yml file
+func Load(path string) (*Policies, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+
+	var def Definition
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &def)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &def)
+	default:
+		err = fmt.Errorf("unsupported file type %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+	return New(def)
+}
+
+// Loads the definition file set in SLA_POLICIES, or the default policies
+func LoadFromEnv() (*Policies, error) {
+	if path := os.Getenv("SLA_POLICIES"); path != "" {
+		return Load(path)
+	}
+	return Default(), nil
+}
+
+// Select returns the first policy matching the ticket, nil when none does
+func (p *Policies) Select(tenant string, ticket *models.Ticket) *Policy {
+	for i := range p.policies {
+		if p.policies[i].matches(tenant, ticket) {
+			return &p.policies[i]
+		}
+	}
+	return nil
+}
+
+// Apply sets the deadlines of the policy matching a new ticket, created at
+// now. Tickets no policy matches are left without SLA.
+func (p *Policies) Apply(tenant string, ticket *models.Ticket, now time.Time) {
+	policy := p.Select(tenant, ticket)
+	if policy == nil {
+		return
+	}
+	ticket.SLA = &models.TicketSLA{
+		Policy:           policy.Name,
+		FirstResponseDue: models.FormatSortableTime(now.Add(policy.FirstResponse)),
+		ResolutionDue:    models.FormatSortableTime(now.Add(policy.Resolution)),
+	}
+}
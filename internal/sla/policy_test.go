@@ -0,0 +1,122 @@
+package sla
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	path := filepath.Join("..", "..", "sla.example.yaml")
+	policies, err := Load(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		tenant   string
+		priority models.TicketPriority
+		expected string
+	}{
+		{"customer policy comes first", "acme", "URGENT", "acme-premium"},
+		{"by priority", "globex", "URGENT", "urgent"},
+		{"fallback", "globex", "LOW", "standard"},
+		{"no priority", "default", "", "standard"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := policies.Select(tt.tenant, &models.Ticket{Priority: tt.priority})
+			require.NotNil(t, policy)
+			assert.Equal(t, tt.expected, policy.Name)
+		})
+	}
+}
+
+func TestNewRejectsInvalidPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy PolicyDefinition
+	}{
+		{"missing name", PolicyDefinition{FirstResponse: "1h", Resolution: "2h"}},
+		{"bad duration", PolicyDefinition{Name: "p", FirstResponse: "soon", Resolution: "2h"}},
+		{"negative duration", PolicyDefinition{Name: "p", FirstResponse: "1h", Resolution: "-2h"}},
+		{"unknown priority", PolicyDefinition{Name: "p", Priorities: []models.TicketPriority{"P1"}, FirstResponse: "1h", Resolution: "2h"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(Definition{Policies: []PolicyDefinition{tt.policy}})
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+		})
+	}
+
+	_, err := New(Definition{Policies: []PolicyDefinition{
+		{Name: "p", FirstResponse: "1h", Resolution: "2h"},
+		{Name: "p", FirstResponse: "1h", Resolution: "2h"},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+
+	path := filepath.Join(t.TempDir(), "policies.toml")
+	require.NoError(t, os.WriteFile(path, []byte(""), 0o644))
+	_, err = Load(path)
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+}
+
+func TestApplyWithoutMatchingPolicy(t *testing.T) {
+	policies, err := New(Definition{Policies: []PolicyDefinition{
+		{Name: "urgent", Priorities: []models.TicketPriority{models.PriorityUrgent}, FirstResponse: "1h", Resolution: "2h"},
+	}})
+	require.NoError(t, err)
+
+	ticket := &models.Ticket{Priority: "LOW"}
+	policies.Apply("default", ticket, time.Now())
+	assert.Nil(t, ticket.SLA)
+}
+
+func TestClock(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	ticket := &models.Ticket{Status: models.StatusOpen, Priority: "HIGH"}
+	Default().Apply("default", ticket, created)
+	require.NotNil(t, ticket.SLA)
+	assert.Equal(t, "high", ticket.SLA.Policy)
+	assert.Equal(t, models.FormatSortableTime(created.Add(time.Hour)), ticket.SLA.NextDue())
+
+	firstResponse := ticket.SLA.FirstResponse(created.Add(2 * time.Hour))
+	assert.True(t, firstResponse.Breached)
+	assert.Equal(t, -time.Hour, firstResponse.Remaining)
+
+	// the customer is asked for details after 30 minutes, which answers the
+	// requester and stops the clock
+	changeStatus := func(to models.TicketStatus, at time.Time) {
+		from := ticket.Status
+		ticket.Status = to
+		ticket.SLAStatusChanged(from, at)
+	}
+	before := *ticket
+	changeStatus(models.StatusPendingCustomer, created.Add(30*time.Minute))
+	assert.Empty(t, before.SLA.RespondedAt, "copies of the ticket keep their SLA")
+	assert.True(t, ticket.SLA.Paused())
+	assert.Empty(t, ticket.SLA.NextDue())
+	assert.False(t, ticket.SLA.FirstResponse(created.Add(5*time.Hour)).Breached)
+	resolution := ticket.SLA.Resolution(created.Add(10 * time.Hour))
+	assert.Equal(t, 7*time.Hour+30*time.Minute, resolution.Remaining, "frozen while paused")
+
+	// two hours later the customer answers, the resolution deadline moves
+	changeStatus(models.StatusInProgress, created.Add(150*time.Minute))
+	assert.False(t, ticket.SLA.Paused())
+	assert.Equal(t, models.FormatSortableTime(created.Add(10*time.Hour)), ticket.SLA.ResolutionDue)
+	assert.Equal(t, models.FormatSortableTime(created.Add(time.Hour)), ticket.SLA.FirstResponseDue)
+
+	changeStatus(models.StatusResolved, created.Add(9*time.Hour))
+	resolution = ticket.SLA.Resolution(created.Add(20 * time.Hour))
+	assert.Equal(t, models.FormatSortableTime(created.Add(9*time.Hour)), resolution.MetAt)
+	assert.False(t, resolution.Breached)
+
+	// reopening restarts the resolution clock where it stopped
+	changeStatus(models.StatusReopened, created.Add(11*time.Hour))
+	assert.Empty(t, ticket.SLA.ResolvedAt)
+	assert.Equal(t, models.FormatSortableTime(created.Add(12*time.Hour)), ticket.SLA.NextDue())
+}
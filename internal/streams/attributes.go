@@ -0,0 +1,45 @@
+package streams
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// The Lambda event has its own attribute type, converted so the images can
+// be unmarshalled like the items read by the repositories
+func toAttributeValues(image map[string]events.DynamoDBAttributeValue) map[string]types.AttributeValue {
+	values := make(map[string]types.AttributeValue, len(image))
+	for name, value := range image {
+		values[name] = toAttributeValue(value)
+	}
+	return values
+}
+
+func toAttributeValue(value events.DynamoDBAttributeValue) types.AttributeValue {
+	switch value.DataType() {
+	case events.DataTypeString:
+		return &types.AttributeValueMemberS{Value: value.String()}
+	case events.DataTypeNumber:
+		return &types.AttributeValueMemberN{Value: value.Number()}
+	case events.DataTypeBoolean:
+		return &types.AttributeValueMemberBOOL{Value: value.Boolean()}
+	case events.DataTypeBinary:
+		return &types.AttributeValueMemberB{Value: value.Binary()}
+	case events.DataTypeStringSet:
+		return &types.AttributeValueMemberSS{Value: value.StringSet()}
+	case events.DataTypeNumberSet:
+		return &types.AttributeValueMemberNS{Value: value.NumberSet()}
+	case events.DataTypeBinarySet:
+		return &types.AttributeValueMemberBS{Value: value.BinarySet()}
+	case events.DataTypeList:
+		list := make([]types.AttributeValue, 0, len(value.List()))
+		for _, item := range value.List() {
+			list = append(list, toAttributeValue(item))
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case events.DataTypeMap:
+		return &types.AttributeValueMemberM{Value: toAttributeValues(value.Map())}
+	default:
+		return &types.AttributeValueMemberNULL{Value: true}
+	}
+}
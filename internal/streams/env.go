@@ -0,0 +1,21 @@
+package streams
+
+import "os"
+
+// NewHandlersFromEnv returns the notification and metrics handlers, and the
+// search indexing handler when SEARCH_ENDPOINT names the search cluster.
+// SEARCH_INDEX overrides the name of the index, "tickets" by default.
+func NewHandlersFromEnv() []Handler {
+	handlers := []Handler{
+		NewNotificationHandler(LogNotifier{}),
+		NewMetricsHandler(os.Stdout),
+	}
+	if endpoint := os.Getenv("SEARCH_ENDPOINT"); endpoint != "" {
+		index := os.Getenv("SEARCH_INDEX")
+		if index == "" {
+			index = "tickets"
+		}
+		handlers = append(handlers, NewSearchIndexHandler(NewHTTPSearchIndex(endpoint, index)))
+	}
+	return handlers
+}
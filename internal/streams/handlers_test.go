@@ -0,0 +1,148 @@
+package streams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationHandler(t *testing.T) {
+	ctx := context.Background()
+	changes := loadChanges(t)
+
+	notifier := NewMockNotifier(t)
+	notifier.On("Notify", mock.Anything, Notification{Tenant: "default", Recipient: "bob", TicketID: "1001", Message: "Ticket 1001 was assigned to you"}).Return(nil).Once()
+	notifier.On("Notify", mock.Anything, Notification{Tenant: "acme", Recipient: "carol", TicketID: "42", Message: "Ticket 42 is now RESOLVED"}).Return(nil).Once()
+
+	handler := NewNotificationHandler(notifier)
+	for _, change := range changes {
+		require.NoError(t, handler.HandleChange(ctx, change))
+	}
+}
+
+func TestSearchIndexHandler(t *testing.T) {
+	ctx := context.Background()
+	changes := loadChanges(t)
+
+	type request struct {
+		method string
+		path   string
+		body   string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.URL.EscapedPath(), string(body)})
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	handler := NewSearchIndexHandler(NewHTTPSearchIndex(server.URL+"/", "tickets"))
+	for _, change := range changes {
+		require.NoError(t, handler.HandleChange(ctx, change))
+	}
+	// only the version moved, nothing to reindex
+	unchanged := *changes[1]
+	unchanged.Fields = nil
+	require.NoError(t, handler.HandleChange(ctx, &unchanged))
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, http.MethodPut, requests[0].method)
+	assert.Equal(t, "/tickets/_doc/default:1001", requests[0].path)
+	var document SearchDocument
+	require.NoError(t, json.Unmarshal([]byte(requests[0].body), &document))
+	assert.Equal(t, SearchDocument{
+		Tenant:      "default",
+		TicketID:    "1001",
+		Description: "Printer is jammed",
+		Status:      "OPEN",
+		CreatedBy:   "alice",
+		CreatedAt:   time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+		AssignedTo:  "bob",
+		Tags:        []string{"hardware"},
+	}, document)
+	assert.Equal(t, "/tickets/_doc/acme:42", requests[1].path)
+	assert.Equal(t, request{http.MethodDelete, "/tickets/_doc/default:1001", ""}, requests[2])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	err := NewSearchIndexHandler(NewHTTPSearchIndex(failing.URL, "tickets")).HandleChange(ctx, changes[0])
+	assert.ErrorIs(t, err, ErrIndexing)
+}
+
+func TestSearchIndexHandlerSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	ticket := models.Ticket{TicketID: "1001", Description: "Printer is jammed", Status: models.StatusOpen, Version: 2}
+	deleted := ticket
+	deleted.DeletedAt = "2024-03-01T10:00:00.000000000Z"
+	deleted.DeletedBy = "alice"
+	deleted.Version = 3
+	restored := ticket
+	restored.Version = 4
+
+	index := NewMockSearchIndex(t)
+	index.EXPECT().RemoveTicket(mock.Anything, "default", "1001").Return(nil).Once()
+	index.EXPECT().IndexTicket(mock.Anything, "default", &restored).Return(nil).Once()
+
+	handler := NewSearchIndexHandler(index)
+	require.NoError(t, handler.HandleChange(ctx, &Change{
+		Type: ChangeModified, Tenant: "default", TicketID: "1001", Old: &ticket, New: &deleted,
+		Fields: models.DiffTickets(&ticket, &deleted),
+	}))
+	require.NoError(t, handler.HandleChange(ctx, &Change{
+		Type: ChangeModified, Tenant: "default", TicketID: "1001", Old: &deleted, New: &restored,
+		Fields: models.DiffTickets(&deleted, &restored),
+	}))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	ctx := context.Background()
+	changes := loadChanges(t)
+
+	var out bytes.Buffer
+	handler := NewMetricsHandler(&out)
+	for _, change := range changes {
+		require.NoError(t, handler.HandleChange(ctx, change))
+	}
+
+	var documents []map[string]any
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var document map[string]any
+		require.NoError(t, decoder.Decode(&document))
+		documents = append(documents, document)
+	}
+	require.Len(t, documents, 3)
+
+	assert.Equal(t, "default", documents[0]["Tenant"])
+	assert.Equal(t, 1.0, documents[0]["TicketsCreated"])
+
+	resolved := documents[1]
+	assert.Equal(t, "acme", resolved["Tenant"])
+	assert.Equal(t, "RESOLVED", resolved["Status"])
+	assert.Equal(t, 1.0, resolved["StatusChanges"])
+	assert.Equal(t, 3600.0, resolved["TimeToResolve"])
+	metadata := resolved["_aws"].(map[string]any)
+	assert.Equal(t, 1700003600000.0, metadata["Timestamp"])
+	definition := metadata["CloudWatchMetrics"].([]any)[0].(map[string]any)
+	assert.Equal(t, MetricsNamespace, definition["Namespace"])
+	assert.Equal(t, []any{[]any{"Status", "Tenant"}}, definition["Dimensions"])
+
+	assert.Equal(t, 1.0, documents[2]["TicketsRemoved"])
+}
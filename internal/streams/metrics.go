@@ -0,0 +1,92 @@
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+)
+
+const MetricsNamespace = "TicketSystem"
+
+// MetricsHandler counts the ticket changes. The metrics are written as log
+// lines in the CloudWatch embedded metric format, which CloudWatch turns
+// into metrics without any API call from the Lambda.
+type MetricsHandler struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewMetricsHandler(out io.Writer) *MetricsHandler {
+	return &MetricsHandler{out: out}
+}
+
+type metric struct {
+	name  string
+	value float64
+	unit  string
+}
+
+func (h *MetricsHandler) HandleChange(ctx context.Context, change *Change) error {
+	dimensions := map[string]string{"Tenant": change.Tenant}
+	var metrics []metric
+	switch change.Type {
+	case ChangeInserted:
+		metrics = append(metrics, metric{"TicketsCreated", 1, "Count"})
+	case ChangeRemoved:
+		metrics = append(metrics, metric{"TicketsRemoved", 1, "Count"})
+	}
+	if status, ok := change.Field("status"); ok && change.New != nil {
+		dimensions["Status"] = status.After
+		metrics = append(metrics, metric{"StatusChanges", 1, "Count"})
+		if change.New.Status == models.StatusResolved {
+			if createdAt := change.New.CreatedAt; !createdAt.IsZero() && !change.At.IsZero() {
+				metrics = append(metrics, metric{"TimeToResolve", change.At.Sub(createdAt.Time).Seconds(), "Seconds"})
+			}
+		}
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+	return h.write(change.At, dimensions, metrics)
+}
+
+// Writes a single embedded metric format document, one line
+func (h *MetricsHandler) write(at time.Time, dimensions map[string]string, metrics []metric) error {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	names := make([]string, 0, len(dimensions))
+	document := map[string]any{}
+	for name, value := range dimensions {
+		names = append(names, name)
+		document[name] = value
+	}
+	sort.Strings(names)
+	definitions := make([]map[string]string, 0, len(metrics))
+	for _, m := range metrics {
+		definitions = append(definitions, map[string]string{"Name": m.name, "Unit": m.unit})
+		document[m.name] = m.value
+	}
+	document["_aws"] = map[string]any{
+		"Timestamp": at.UnixMilli(),
+		"CloudWatchMetrics": []map[string]any{{
+			"Namespace":  MetricsNamespace,
+			"Dimensions": [][]string{names},
+			"Metrics":    definitions,
+		}},
+	}
+
+	line, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.out.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,76 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Notification is a message for a single user about one of their tickets
+type Notification struct {
+	Tenant    string
+	Recipient string
+	TicketID  string
+	Message   string
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes the notifications to the log, until users have a
+// channel to receive them on
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	slog.InfoContext(ctx, "Notification", "tenant", notification.Tenant, "recipient", notification.Recipient, "ticketId", notification.TicketID, "message", notification.Message)
+	return nil
+}
+
+// NotificationHandler tells the assignee about the tickets given to them
+// and the requester about the status changes of their tickets
+type NotificationHandler struct {
+	notifier Notifier
+}
+
+func NewNotificationHandler(notifier Notifier) *NotificationHandler {
+	return &NotificationHandler{notifier: notifier}
+}
+
+func (h *NotificationHandler) HandleChange(ctx context.Context, change *Change) error {
+	if change.New == nil {
+		return nil
+	}
+	for _, notification := range notifications(change) {
+		if err := h.notifier.Notify(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func notifications(change *Change) []Notification {
+	var notifications []Notification
+	notify := func(recipient string, message string) {
+		if recipient == "" || recipient == "None" {
+			return
+		}
+		notifications = append(notifications, Notification{
+			Tenant:    change.Tenant,
+			Recipient: recipient,
+			TicketID:  change.TicketID,
+			Message:   message,
+		})
+	}
+
+	if assignee, ok := change.Field("assignedTo"); ok {
+		notify(assignee.After, fmt.Sprintf("Ticket %s was assigned to you", change.TicketID))
+	}
+	if change.Type == ChangeModified {
+		if status, ok := change.Field("status"); ok {
+			notify(change.New.CreatedBy, fmt.Sprintf("Ticket %s is now %s", change.TicketID, status.After))
+		}
+	}
+	return notifications
+}
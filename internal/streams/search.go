@@ -0,0 +1,133 @@
+package streams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+)
+
+var ErrIndexing = errors.New("search indexing failed")
+
+// SearchIndex keeps the searchable copy of the tickets
+type SearchIndex interface {
+	IndexTicket(ctx context.Context, tenant string, ticket *models.Ticket) error
+	RemoveTicket(ctx context.Context, tenant string, ticketID string) error
+}
+
+// SearchIndexHandler mirrors the ticket writes to the search index
+type SearchIndexHandler struct {
+	index SearchIndex
+}
+
+func NewSearchIndexHandler(index SearchIndex) *SearchIndexHandler {
+	return &SearchIndexHandler{index: index}
+}
+
+func (h *SearchIndexHandler) HandleChange(ctx context.Context, change *Change) error {
+	switch {
+	case change.New == nil, change.New.IsDeleted():
+		// soft deleted tickets are hidden until restored, the restore
+		// clears deletedAt and indexes them again
+		return h.index.RemoveTicket(ctx, change.Tenant, change.TicketID)
+	case len(change.Fields) == 0:
+		// only the version moved, the document is unchanged
+		return nil
+	default:
+		return h.index.IndexTicket(ctx, change.Tenant, change.New)
+	}
+}
+
+// SearchDocument is the indexed form of a ticket
+type SearchDocument struct {
+	Tenant      string    `json:"tenant"`
+	TicketID    string    `json:"ticketId"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	AssignedTo  string    `json:"assignedTo"`
+	Priority    string    `json:"priority,omitempty"`
+	Severity    string    `json:"severity,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+}
+
+func NewSearchDocument(tenant string, ticket *models.Ticket) SearchDocument {
+	return SearchDocument{
+		Tenant:      tenant,
+		TicketID:    ticket.TicketID,
+		Description: ticket.Description,
+		Status:      string(ticket.Status),
+		CreatedBy:   ticket.CreatedBy,
+		CreatedAt:   ticket.CreatedAt.Time,
+		AssignedTo:  ticket.AssignedTo,
+		Priority:    string(ticket.Priority),
+		Severity:    string(ticket.Severity),
+		Category:    string(ticket.Category),
+		Tags:        ticket.Tags,
+	}
+}
+
+// HTTPSearchIndex writes the documents through the document API of an
+// OpenSearch or Elasticsearch cluster. The tickets of all tenants share the
+// index, their documents are keyed by tenant and ticket id.
+type HTTPSearchIndex struct {
+	endpoint string
+	index    string
+	client   *http.Client
+}
+
+func NewHTTPSearchIndex(endpoint string, index string) *HTTPSearchIndex {
+	return &HTTPSearchIndex{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		index:    index,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (i *HTTPSearchIndex) IndexTicket(ctx context.Context, tenant string, ticket *models.Ticket) error {
+	body, err := json.Marshal(NewSearchDocument(tenant, ticket))
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrIndexing, err)
+	}
+	return i.do(ctx, http.MethodPut, tenant, ticket.TicketID, body)
+}
+
+func (i *HTTPSearchIndex) RemoveTicket(ctx context.Context, tenant string, ticketID string) error {
+	return i.do(ctx, http.MethodDelete, tenant, ticketID, nil)
+}
+
+func (i *HTTPSearchIndex) do(ctx context.Context, method string, tenant string, ticketID string, body []byte) error {
+	documentURL := fmt.Sprintf("%s/%s/_doc/%s", i.endpoint, url.PathEscape(i.index), url.PathEscape(tenant+":"+ticketID))
+	request, err := http.NewRequestWithContext(ctx, method, documentURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrIndexing, err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := i.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w - %w", ErrIndexing, err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	// removing a document that was never indexed is not an error
+	if response.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return nil
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("%w - status %d", ErrIndexing, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package streams consumes the DynamoDB stream of the tickets table. Each
+// write to a ticket item is decoded into a Change holding the ticket before
+// and after, and the fields that differ, then passed to every handler in
+// turn. The stream must be configured with the NEW_AND_OLD_IMAGES view.
+//
+// Lambda retries a failed record, so a handler may see a change again after
+// a later handler failed on it.
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+type ChangeType string
+
+const (
+	ChangeInserted ChangeType = "INSERT"
+	ChangeModified ChangeType = "MODIFY"
+	ChangeRemoved  ChangeType = "REMOVE"
+)
+
+var ErrMalformedRecord = errors.New("malformed stream record")
+
+// Change is a single write to a ticket
+type Change struct {
+	EventID  string
+	Type     ChangeType
+	Tenant   string
+	TicketID string
+	// nil when the ticket was inserted
+	Old *models.Ticket
+	// nil when the ticket was removed
+	New *models.Ticket
+	// fields that differ between Old and New
+	Fields []models.FieldChange
+	At     time.Time
+}
+
+// Field returns the change of the named attribute, if it changed
+func (c *Change) Field(name string) (models.FieldChange, bool) {
+	for _, change := range c.Fields {
+		if change.Field == name {
+			return change, true
+		}
+	}
+	return models.FieldChange{}, false
+}
+
+// Handler reacts to ticket changes. An error makes Lambda retry the record.
+type Handler interface {
+	HandleChange(ctx context.Context, change *Change) error
+}
+
+// NewStreamHandler returns the Lambda handler for the table stream. Records
+// of items other than tickets are skipped. Processing stops at the first
+// failure, which is reported so Lambda resumes the shard from that record
+// and the changes of a ticket are never handled out of order.
+func NewStreamHandler(handlers ...Handler) func(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	return func(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+		response := events.DynamoDBEventResponse{BatchItemFailures: []events.DynamoDBBatchItemFailure{}}
+		for i := range event.Records {
+			record := &event.Records[i]
+			change, err := DecodeChange(record)
+			if err != nil {
+				// retrying will not fix a malformed record
+				slog.ErrorContext(ctx, "Dropping malformed stream record", "eventId", record.EventID, "error", err)
+				continue
+			}
+			if change == nil {
+				continue
+			}
+			if err := handle(ctx, handlers, change); err != nil {
+				slog.ErrorContext(ctx, "Failed to handle ticket change", "eventId", record.EventID, "ticketId", change.TicketID, "error", err)
+				response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{ItemIdentifier: record.Change.SequenceNumber})
+				break
+			}
+		}
+		return response, nil
+	}
+}
+
+func handle(ctx context.Context, handlers []Handler, change *Change) error {
+	for _, handler := range handlers {
+		if err := handler.HandleChange(ctx, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeChange reads the ticket images of a stream record. It returns nil
+// when the record is not about a ticket.
+func DecodeChange(record *events.DynamoDBEventRecord) (*Change, error) {
+	pk := record.Change.Keys["PK"]
+	sk := record.Change.Keys["SK"]
+	if pk.DataType() != events.DataTypeString || sk.DataType() != events.DataTypeString || sk.String() != "details" {
+		return nil, nil
+	}
+	tenant, ticketID, ok := repositories.ParseTicketKey(pk.String())
+	if !ok {
+		return nil, nil
+	}
+
+	change := &Change{
+		EventID:  record.EventID,
+		Type:     ChangeType(record.EventName),
+		Tenant:   tenant,
+		TicketID: ticketID,
+		At:       record.Change.ApproximateCreationDateTime.Time,
+	}
+	var err error
+	switch change.Type {
+	case ChangeInserted:
+		change.New, err = decodeTicket(record.Change.NewImage)
+	case ChangeModified:
+		if change.Old, err = decodeTicket(record.Change.OldImage); err == nil {
+			change.New, err = decodeTicket(record.Change.NewImage)
+		}
+	case ChangeRemoved:
+		change.Old, err = decodeTicket(record.Change.OldImage)
+	default:
+		return nil, fmt.Errorf("%w - event %q", ErrMalformedRecord, record.EventName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	change.Fields = models.DiffTickets(change.Old, change.New)
+	return change, nil
+}
+
+func decodeTicket(image map[string]events.DynamoDBAttributeValue) (*models.Ticket, error) {
+	if len(image) == 0 {
+		return nil, fmt.Errorf("%w - missing image, the stream must use NEW_AND_OLD_IMAGES", ErrMalformedRecord)
+	}
+	var record models.TicketDbRecord
+	if err := attributevalue.UnmarshalMap(toAttributeValues(image), &record); err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrMalformedRecord, err)
+	}
+	return &record.Ticket, nil
+}
@@ -0,0 +1,123 @@
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/models"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func loadEvent(t *testing.T) events.DynamoDBEvent {
+	data, err := os.ReadFile("testdata/stream_event.json")
+	require.NoError(t, err)
+	var event events.DynamoDBEvent
+	require.NoError(t, json.Unmarshal(data, &event))
+	return event
+}
+
+// decodes the ticket records of the fixture, the others are skipped
+func loadChanges(t *testing.T) []*Change {
+	var changes []*Change
+	event := loadEvent(t)
+	for i := range event.Records {
+		change, err := DecodeChange(&event.Records[i])
+		require.NoError(t, err)
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func TestDecodeChange(t *testing.T) {
+	changes := loadChanges(t)
+	require.Len(t, changes, 3)
+
+	inserted := changes[0]
+	assert.Equal(t, ChangeInserted, inserted.Type)
+	assert.Equal(t, "default", inserted.Tenant)
+	assert.Equal(t, "1001", inserted.TicketID)
+	assert.Nil(t, inserted.Old)
+	assert.Equal(t, []string{"hardware"}, inserted.New.Tags)
+	assert.Equal(t, time.Unix(1700000000, 0), inserted.At)
+	assignee, ok := inserted.Field("assignedTo")
+	assert.True(t, ok)
+	assert.Equal(t, models.FieldChange{Field: "assignedTo", Before: "", After: "bob"}, assignee)
+
+	modified := changes[1]
+	assert.Equal(t, ChangeModified, modified.Type)
+	assert.Equal(t, "acme", modified.Tenant)
+	assert.Equal(t, "42", modified.TicketID)
+	assert.Equal(t, int64(3), modified.Old.Version)
+	assert.Equal(t, int64(4), modified.New.Version)
+	assert.Equal(t, []models.FieldChange{{Field: "status", Before: "IN_PROGRESS", After: "RESOLVED"}}, modified.Fields)
+
+	removed := changes[2]
+	assert.Equal(t, ChangeRemoved, removed.Type)
+	assert.Nil(t, removed.New)
+	assert.Equal(t, "Printer is jammed", removed.Old.Description)
+	_, ok = removed.Field("description")
+	assert.True(t, ok)
+}
+
+func TestDecodeChangeWithoutImages(t *testing.T) {
+	event := loadEvent(t)
+	record := event.Records[3]
+	record.Change.OldImage = nil
+
+	_, err := DecodeChange(&record)
+	assert.ErrorIs(t, err, ErrMalformedRecord)
+}
+
+func TestStreamHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes every ticket change to the handlers in order", func(t *testing.T) {
+		first := NewMockHandler(t)
+		second := NewMockHandler(t)
+		var handled []string
+		first.On("HandleChange", mock.Anything, mock.Anything).Return(nil).Times(3)
+		second.On("HandleChange", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			change := args.Get(1).(*Change)
+			handled = append(handled, string(change.Type)+" "+change.TicketID)
+		}).Return(nil).Times(3)
+
+		response, err := NewStreamHandler(first, second)(ctx, loadEvent(t))
+		require.NoError(t, err)
+		assert.Empty(t, response.BatchItemFailures)
+		assert.Equal(t, []string{"INSERT 1001", "MODIFY 42", "REMOVE 1001"}, handled)
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		handler := NewMockHandler(t)
+		handler.On("HandleChange", mock.Anything, mock.MatchedBy(func(change *Change) bool {
+			return change.TicketID == "1001"
+		})).Return(nil).Once()
+		handler.On("HandleChange", mock.Anything, mock.MatchedBy(func(change *Change) bool {
+			return change.TicketID == "42"
+		})).Return(errors.New("unavailable")).Once()
+
+		response, err := NewStreamHandler(handler)(ctx, loadEvent(t))
+		require.NoError(t, err)
+		assert.Equal(t, []events.DynamoDBBatchItemFailure{{ItemIdentifier: "103"}}, response.BatchItemFailures)
+	})
+
+	t.Run("drops malformed records", func(t *testing.T) {
+		event := loadEvent(t)
+		event.Records[0].EventName = "UNKNOWN"
+		handler := NewMockHandler(t)
+		handler.On("HandleChange", mock.Anything, mock.Anything).Return(nil).Twice()
+
+		response, err := NewStreamHandler(handler)(ctx, event)
+		require.NoError(t, err)
+		assert.Empty(t, response.BatchItemFailures)
+	})
+}
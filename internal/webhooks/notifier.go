@@ -0,0 +1,212 @@
+// Package webhooks notifies subscribers of ticket lifecycle events. Each
+// event relayed from the outbox is posted as JSON to the URLs registered for
+// its type, signed with the secret of the subscription. Failed deliveries
+// are retried with an exponential backoff, for a limited time, and end up in
+// the dead-letter list of the tenant.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+	defaultTimeout     = 10 * time.Second
+	// the relay waits on the deliveries of an event, so a failing
+	// subscriber is dead lettered early rather than holding it up
+	defaultMaxRetryTime = 15 * time.Second
+)
+
+var ErrDeliveryFailed = errors.New("webhook delivery failed")
+
+// Notifier is the outbox sink posting events to the webhook subscribers
+type Notifier struct {
+	webhooks    repositories.WebhookRepository
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	// no retry is started past this time after the first attempt
+	maxRetryTime time.Duration
+}
+
+type Option func(*Notifier)
+
+// WithHTTPClient replaces the client posting the events
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// WithRetries sets the number of attempts per delivery. The delay before a
+// retry doubles from backoff up to maxBackoff.
+func WithRetries(maxAttempts int, backoff time.Duration, maxBackoff time.Duration) Option {
+	return func(n *Notifier) {
+		n.maxAttempts = maxAttempts
+		n.backoff = backoff
+		n.maxBackoff = maxBackoff
+	}
+}
+
+// WithMaxRetryTime caps the time spent retrying the delivery of an event
+func WithMaxRetryTime(maxRetryTime time.Duration) Option {
+	return func(n *Notifier) {
+		n.maxRetryTime = maxRetryTime
+	}
+}
+
+func NewNotifier(webhooks repositories.WebhookRepository, options ...Option) *Notifier {
+	n := &Notifier{
+		webhooks:     webhooks,
+		client:       &http.Client{Timeout: defaultTimeout},
+		maxAttempts:  defaultMaxAttempts,
+		backoff:      defaultBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		maxRetryTime: defaultMaxRetryTime,
+	}
+	for _, option := range options {
+		option(n)
+	}
+	if n.maxAttempts < 1 {
+		n.maxAttempts = 1
+	}
+	return n
+}
+
+// Send delivers the event to every subscription of its tenant that listens
+// to its type, and returns once each delivery succeeded or was dead lettered
+func (n *Notifier) Send(ctx context.Context, event models.Event) error {
+	ctx = identity.WithIdentity(ctx, identity.Identity{Subject: identity.SystemActor, Tenant: event.Tenant})
+	webhooks, err := n.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(webhooks))
+	for i, webhook := range webhooks {
+		if !webhook.Subscribes(event.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, webhook models.WebhookSubscription) {
+			defer wg.Done()
+			errs[i] = n.deliver(ctx, &webhook, &event, payload)
+		}(i, webhook)
+	}
+	wg.Wait()
+	// a dead letter that could not be saved is retried with the event
+	return errors.Join(errs...)
+}
+
+// Retries until the subscriber accepts the event, a client error other than
+// 408 and 429 is not retried. The event is dead lettered once the attempts
+// or the retry time run out. When ctx is done the delivery is abandoned and
+// the event stays in the outbox.
+func (n *Notifier) deliver(ctx context.Context, webhook *models.WebhookSubscription, event *models.Event, payload []byte) error {
+	deadline := time.Now().Add(n.maxRetryTime)
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		var retry bool
+		retry, err = n.send(ctx, webhook, event, payload)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.WarnContext(ctx, "Webhook delivery failed", "webhookId", webhook.WebhookID, "eventId", event.ID, "attempt", attempt, "error", err)
+		delay := n.delay(attempt)
+		if !retry || attempt == n.maxAttempts || time.Now().Add(delay).After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	letter := &models.WebhookDeadLetter{
+		DeadLetterID: uuid.NewString(),
+		WebhookID:    webhook.WebhookID,
+		URL:          webhook.URL,
+		EventID:      event.ID,
+		EventType:    event.Type,
+		Payload:      string(payload),
+		Attempts:     attempt,
+		LastError:    err.Error(),
+		FailedAt:     models.FormatSortableTime(time.Now()),
+	}
+	if err := n.webhooks.AddDeadLetter(ctx, letter); err != nil {
+		slog.ErrorContext(ctx, "Failed to save webhook dead letter", "webhookId", webhook.WebhookID, "eventId", event.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *Notifier) send(ctx context.Context, webhook *models.WebhookSubscription, event *models.Event, payload []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("%w - %w", ErrDeliveryFailed, err)
+	}
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEventID, event.ID)
+	request.Header.Set(HeaderEventType, string(event.Type))
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, payload))
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return true, fmt.Errorf("%w - %w", ErrDeliveryFailed, err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	switch code := response.StatusCode; {
+	case code >= 200 && code < 300:
+		return false, nil
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests, code >= 500:
+		return true, fmt.Errorf("%w - status %d", ErrDeliveryFailed, code)
+	default:
+		return false, fmt.Errorf("%w - status %d", ErrDeliveryFailed, code)
+	}
+}
+
+// Exponential backoff with jitter, between half and all of the doubled delay
+func (n *Notifier) delay(attempt int) time.Duration {
+	delay := n.backoff << (attempt - 1)
+	if delay <= 0 || delay > n.maxBackoff {
+		delay = n.maxBackoff
+	}
+	if delay <= 1 {
+		return delay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,143 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example.com/ticket-system/internal/identity"
+	"example.com/ticket-system/internal/models"
+	"example.com/ticket-system/internal/repositories"
+	"example.com/ticket-system/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163", Sign("secret", 1700000000, []byte("{}")))
+}
+
+func TestNotifier(t *testing.T) {
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "alice", Tenant: "acme"})
+
+	tests := []struct {
+		name             string
+		responses        []int
+		expectedAttempts int32
+		expectedLetter   string
+	}{
+		{"delivered", []int{200}, 1, ""},
+		{"retried until accepted", []int{503, 429, 204}, 3, ""},
+		{"dead lettered after the last attempt", []int{500, 502, 503}, 3, "webhook delivery failed - status 503"},
+		{"client error is not retried", []int{410}, 1, "webhook delivery failed - status 410"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := attempts.Add(1)
+				body, _ := io.ReadAll(r.Body)
+				timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+				assert.Equal(t, Sign("0123456789abcdef", timestamp, body), r.Header.Get(HeaderSignature))
+				assert.Equal(t, string(models.EventTicketAssigned), r.Header.Get(HeaderEventType))
+				w.WriteHeader(tt.responses[attempt-1])
+			}))
+			defer receiver.Close()
+
+			repo := repositories.NewMemoryTicketRepository(workflow.Default())
+			require.NoError(t, repo.CreateWebhook(ctx, &models.WebhookSubscription{
+				WebhookID: "hook-1",
+				URL:       receiver.URL,
+				Secret:    "0123456789abcdef",
+				Events:    []models.EventType{models.EventTicketAssigned},
+			}))
+			notifier := NewNotifier(repo, WithRetries(3, time.Millisecond, 5*time.Millisecond))
+
+			event := models.Event{ID: "event-1", Type: models.EventTicketAssigned, Tenant: "acme", Actor: "alice", Subject: "1234", Data: json.RawMessage(`{"ticket":{}}`)}
+			require.NoError(t, notifier.Send(context.Background(), event))
+			// not subscribed
+			require.NoError(t, notifier.Send(context.Background(), models.Event{ID: "event-2", Type: models.EventCommentAdded, Tenant: "acme"}))
+
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+			letters, err := repo.ListDeadLetters(ctx, models.PageRequest{})
+			require.NoError(t, err)
+			if tt.expectedLetter == "" {
+				assert.Empty(t, letters.DeadLetters)
+				return
+			}
+			require.Len(t, letters.DeadLetters, 1)
+			letter := letters.DeadLetters[0]
+			assert.Equal(t, "hook-1", letter.WebhookID)
+			assert.Equal(t, models.EventTicketAssigned, letter.EventType)
+			assert.Equal(t, int(tt.expectedAttempts), letter.Attempts)
+			assert.Equal(t, tt.expectedLetter, letter.LastError)
+
+			assert.Equal(t, "event-1", letter.EventID)
+			var payload models.Event
+			require.NoError(t, json.Unmarshal([]byte(letter.Payload), &payload))
+			assert.Equal(t, event, payload)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	notifier := NewNotifier(nil, WithRetries(10, time.Second, 10*time.Second))
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		delay := notifier.delay(attempt + 1)
+		assert.GreaterOrEqual(t, delay, max/2)
+		assert.LessOrEqual(t, delay, max)
+	}
+}
+
+func TestNotifierStopsRetrying(t *testing.T) {
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{Subject: "alice", Tenant: "acme"})
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	repo := repositories.NewMemoryTicketRepository(workflow.Default())
+	require.NoError(t, repo.CreateWebhook(ctx, &models.WebhookSubscription{
+		WebhookID: "hook-1",
+		URL:       receiver.URL,
+		Secret:    "0123456789abcdef",
+		Events:    []models.EventType{models.EventTicketAssigned},
+	}))
+	event := models.Event{ID: "event-1", Type: models.EventTicketAssigned, Tenant: "acme", Subject: "1234"}
+
+	t.Run("cancelled during the backoff", func(t *testing.T) {
+		attempts.Store(0)
+		notifier := NewNotifier(repo, WithRetries(5, time.Hour, time.Hour), WithMaxRetryTime(2*time.Hour))
+		cancelled, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, notifier.Send(cancelled, event), context.DeadlineExceeded)
+		assert.Equal(t, int32(1), attempts.Load())
+		// left in the outbox, not dead lettered
+		letters, err := repo.ListDeadLetters(ctx, models.PageRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, letters.DeadLetters)
+	})
+
+	t.Run("out of retry time", func(t *testing.T) {
+		attempts.Store(0)
+		notifier := NewNotifier(repo, WithRetries(5, time.Hour, time.Hour), WithMaxRetryTime(time.Minute))
+
+		require.NoError(t, notifier.Send(context.Background(), event))
+		assert.Equal(t, int32(1), attempts.Load())
+		letters, err := repo.ListDeadLetters(ctx, models.PageRequest{})
+		require.NoError(t, err)
+		require.Len(t, letters.DeadLetters, 1)
+		assert.Equal(t, 1, letters.DeadLetters[0].Attempts)
+	})
+}
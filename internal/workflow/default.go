@@ -0,0 +1,51 @@
+package workflow
+
+import "example.com/ticket-system/internal/models"
+
+var defaultDefinition = Definition{
+	Statuses: []models.TicketStatus{
+		models.StatusOpen,
+		models.StatusInProgress,
+		models.StatusPendingCustomer,
+		models.StatusResolved,
+		models.StatusClosed,
+		models.StatusReopened,
+	},
+	Transitions: []TransitionDefinition{
+		{From: models.StatusOpen, To: []models.TicketStatus{models.StatusInProgress, models.StatusPendingCustomer, models.StatusClosed}},
+		{From: models.StatusInProgress, To: []models.TicketStatus{models.StatusOpen, models.StatusPendingCustomer, models.StatusClosed}},
+		{From: models.StatusPendingCustomer, To: []models.TicketStatus{models.StatusInProgress, models.StatusClosed}},
+		{From: models.StatusReopened, To: []models.TicketStatus{models.StatusInProgress, models.StatusPendingCustomer, models.StatusClosed}},
+		{
+			From:   models.StatusOpen,
+			To:     []models.TicketStatus{models.StatusResolved},
+			Guards: []string{"assigned"},
+		},
+		{
+			From:   models.StatusInProgress,
+			To:     []models.TicketStatus{models.StatusResolved},
+			Guards: []string{"assigned"},
+		},
+		{
+			From:   models.StatusPendingCustomer,
+			To:     []models.TicketStatus{models.StatusResolved},
+			Guards: []string{"assigned"},
+		},
+		{
+			From:   models.StatusReopened,
+			To:     []models.TicketStatus{models.StatusResolved},
+			Guards: []string{"assigned"},
+		},
+		{From: models.StatusResolved, To: []models.TicketStatus{models.StatusClosed, models.StatusReopened}},
+		{From: models.StatusClosed, To: []models.TicketStatus{models.StatusReopened}},
+	},
+}
+
+// Default returns the built-in support workflow
+func Default() *Workflow {
+	wf, err := New(defaultDefinition)
+	if err != nil {
+		panic(err)
+	}
+	return wf
+}
@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"example.com/ticket-system/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrTransitionNotAllowed = errors.New("status transition not allowed")
+	ErrInvalidDefinition    = errors.New("invalid workflow definition")
+)
+
+// Guard decides whether a ticket may enter a status, returning the reason when it may not
+type Guard func(ticket *models.Ticket) error
+
+// Guards that can be referenced by name from a workflow definition
+var builtinGuards = map[string]Guard{
+	"assigned": func(ticket *models.Ticket) error {
+		if ticket.AssignedTo == "" || ticket.AssignedTo == "None" {
+			return errors.New("ticket must be assigned")
+		}
+		return nil
+	},
+}
+
+// Definition is the serializable form of a workflow, loaded from JSON or YAML
+type Definition struct {
+	Statuses    []models.TicketStatus  `json:"statuses" yaml:"statuses"`
+	Transitions []TransitionDefinition `json:"transitions" yaml:"transitions"`
+}
+
+type TransitionDefinition struct {
+	From   models.TicketStatus   `json:"from" yaml:"from"`
+	To     []models.TicketStatus `json:"to" yaml:"to"`
+	Guards []string              `json:"guards,omitempty" yaml:"guards,omitempty"`
+}
+
+// TransitionError describes why a ticket cannot move to the requested status
+type TransitionError struct {
+	From   models.TicketStatus
+	To     models.TicketStatus
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change status from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrTransitionNotAllowed
+}
+
+type Workflow struct {
+	statuses    map[models.TicketStatus]bool
+	transitions map[models.TicketStatus]map[models.TicketStatus][]Guard
+}
+
+// Builds a workflow, checking that every status and guard it references
+// exists. New tickets are created OPEN, so the workflow must include it.
+func New(def Definition) (*Workflow, error) {
+	wf := &Workflow{
+		statuses:    make(map[models.TicketStatus]bool),
+		transitions: make(map[models.TicketStatus]map[models.TicketStatus][]Guard),
+	}
+	for _, status := range def.Statuses {
+		wf.statuses[status] = true
+	}
+	if len(wf.statuses) == 0 {
+		return nil, fmt.Errorf("%w - no statuses", ErrInvalidDefinition)
+	}
+	if !wf.statuses[models.StatusOpen] {
+		return nil, fmt.Errorf("%w - missing initial status %s", ErrInvalidDefinition, models.StatusOpen)
+	}
+
+	for _, transition := range def.Transitions {
+		if !wf.statuses[transition.From] {
+			return nil, fmt.Errorf("%w - unknown status %s", ErrInvalidDefinition, transition.From)
+		}
+		var guards []Guard
+		for _, name := range transition.Guards {
+			guard, ok := builtinGuards[name]
+			if !ok {
+				return nil, fmt.Errorf("%w - unknown guard %s", ErrInvalidDefinition, name)
+			}
+			guards = append(guards, guard)
+		}
+		if wf.transitions[transition.From] == nil {
+			wf.transitions[transition.From] = make(map[models.TicketStatus][]Guard)
+		}
+		for _, to := range transition.To {
+			if !wf.statuses[to] {
+				return nil, fmt.Errorf("%w - unknown status %s", ErrInvalidDefinition, to)
+			}
+			wf.transitions[transition.From][to] = append(wf.transitions[transition.From][to], guards...)
+		}
+	}
+	return wf, nil
+}
+
+// Reads a workflow definition from a .json, .yaml or .yml file
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+
+	var def Definition
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &def)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &def)
+	default:
+		err = fmt.Errorf("unsupported file type %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w - %w", ErrInvalidDefinition, err)
+	}
+	return New(def)
+}
+
+// Loads the definition file set in WORKFLOW_DEFINITION, or the default workflow
+func LoadFromEnv() (*Workflow, error) {
+	if path := os.Getenv("WORKFLOW_DEFINITION"); path != "" {
+		return Load(path)
+	}
+	return Default(), nil
+}
+
+func (wf *Workflow) IsValidStatus(status models.TicketStatus) bool {
+	return wf.statuses[status]
+}
+
+// Moves the ticket to the given status if the workflow allows it
+func (wf *Workflow) Transition(ticket *models.Ticket, to models.TicketStatus) error {
+	if !wf.statuses[to] {
+		return &TransitionError{From: ticket.Status, To: to, Reason: "unknown status"}
+	}
+	guards, ok := wf.transitions[ticket.Status][to]
+	if !ok {
+		return &TransitionError{From: ticket.Status, To: to, Reason: "transition is not part of the workflow"}
+	}
+	for _, guard := range guards {
+		if err := guard(ticket); err != nil {
+			return &TransitionError{From: ticket.Status, To: to, Reason: err.Error()}
+		}
+	}
+	ticket.Status = to
+	return nil
+}
@@ -0,0 +1,101 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/ticket-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultWorkflowTransitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		ticket     models.Ticket
+		to         models.TicketStatus
+		wantReason string
+	}{
+		{
+			name:   "open to in progress",
+			ticket: models.Ticket{Status: models.StatusOpen},
+			to:     models.StatusInProgress,
+		},
+		{
+			name:   "resolve assigned ticket",
+			ticket: models.Ticket{Status: models.StatusInProgress, AssignedTo: "andrew"},
+			to:     models.StatusResolved,
+		},
+		{
+			name:       "resolve unassigned ticket",
+			ticket:     models.Ticket{Status: models.StatusInProgress, AssignedTo: "None"},
+			to:         models.StatusResolved,
+			wantReason: "ticket must be assigned",
+		},
+		{
+			name:       "closed to in progress",
+			ticket:     models.Ticket{Status: models.StatusClosed},
+			to:         models.StatusInProgress,
+			wantReason: "transition is not part of the workflow",
+		},
+		{
+			name:       "unknown status",
+			ticket:     models.Ticket{Status: models.StatusOpen},
+			to:         "ARCHIVED",
+			wantReason: "unknown status",
+		},
+	}
+
+	wf := Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := tt.ticket
+			err := wf.Transition(&ticket, tt.to)
+			if tt.wantReason == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.to, ticket.Status)
+				return
+			}
+			var transitionErr *TransitionError
+			require.ErrorAs(t, err, &transitionErr)
+			assert.ErrorIs(t, err, ErrTransitionNotAllowed)
+			assert.Equal(t, tt.wantReason, transitionErr.Reason)
+			assert.Equal(t, tt.ticket.Status, ticket.Status)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "workflow.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`
+statuses: [OPEN, CLOSED]
+transitions:
+  - from: OPEN
+    to: [CLOSED]
+    guards: [assigned]
+`), 0o644))
+	jsonPath := filepath.Join(dir, "workflow.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{
+		"statuses": ["OPEN", "CLOSED"],
+		"transitions": [{"from": "OPEN", "to": ["CLOSED"], "guards": ["assigned"]}]
+	}`), 0o644))
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		wf, err := Load(path)
+		require.NoError(t, err)
+		assert.Error(t, wf.Transition(&models.Ticket{Status: models.StatusOpen}, models.StatusClosed))
+		assert.NoError(t, wf.Transition(&models.Ticket{Status: models.StatusOpen, AssignedTo: "hugo"}, models.StatusClosed))
+	}
+
+	_, err := New(Definition{
+		Statuses:    []models.TicketStatus{models.StatusOpen},
+		Transitions: []TransitionDefinition{{From: models.StatusOpen, To: []models.TicketStatus{models.StatusClosed}}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+
+	// new tickets could never leave a status the workflow does not know
+	_, err = New(Definition{Statuses: []models.TicketStatus{"NEW", models.StatusClosed}})
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+}